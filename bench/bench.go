@@ -0,0 +1,242 @@
+// Package bench runs the concurrency vs parallelism comparisons as a
+// library: each workload is timed at GOMAXPROCS=1 and again at Procs, and
+// the raw timings come back as Results for the caller to report or store.
+//
+//	results, err := bench.Run(ctx, bench.DefaultConfig())
+//	for _, r := range results {
+//		fmt.Printf("%s: %.2fx of %.2fx\n", r.Workload, r.Speedup(), r.MaxSpeedup())
+//	}
+package bench
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"compare_process/internal/runner"
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// Workload is a wave of goroutines that can run under any GOMAXPROCS.
+type Workload = workloads.Workload
+
+// Metrics collects the counters and gauges a workload records as it runs.
+type Metrics = workloads.Metrics
+
+// Sizes sizes the built-in CPU, I/O and mixed workloads.
+type Sizes = workloads.Config
+
+// Clock is the time the I/O workload waits on and is timed by; set
+// Sizes.Clock to replace the real one.
+type Clock = workloads.Clock
+
+// NewVirtualClock returns a clock on which the I/O workload finishes
+// at once with the same elapsed time every run, whatever
+// GOMAXPROCS is: for testing code built on a Runner, not for measuring.
+func NewVirtualClock() Clock {
+	return workloads.NewVirtualClock()
+}
+
+// Result holds one workload's concurrent and parallel timings, with its
+// metrics, allocations and runtime histograms.
+type Result = runner.Result
+
+// OutlierRule decides which timings are too far from the rest to keep.
+type OutlierRule = stats.OutlierRule
+
+// The outlier rules Config.Outliers takes.
+const (
+	NoOutliers = stats.NoOutliers
+	Tukey      = stats.Tukey
+	MADRule    = stats.MADRule
+)
+
+// NewWorkload adapts a run function to Workload. run must set GOMAXPROCS
+// to maxProcs for the wave and restore it before returning; m may be nil.
+func NewWorkload(name string, tasks int, run func(maxProcs int, m *Metrics) time.Duration) Workload {
+	return workloads.New(name, tasks, run)
+}
+
+// ShardFunc is one shard of a custom workload: a run calls it once per
+// shard, each in its own goroutine. Errors are counted, not fatal.
+type ShardFunc = workloads.ShardFunc
+
+// NewCustomWorkload wraps fn as a workload of shards goroutines, or one
+// per core when shards is zero, for Config.Workloads.
+func NewCustomWorkload(name string, shards int, fn ShardFunc) Workload {
+	return workloads.Custom(name, shards, fn)
+}
+
+// RegisterCustom registers fn under name for the bench command, whose
+// custom suite runs the full comparison on it and whose -list and -run
+// see it. Call it from an init: in a plugin loaded with -plugin, or in a
+// file added to cmd/bench behind a build tag. With shards zero it runs
+// -goroutines shards, or one per core.
+func RegisterCustom(name string, shards int, fn ShardFunc) {
+	workloads.RegisterCustom(name, shards, fn)
+}
+
+// Config sets what a Runner compares and how often.
+type Config struct {
+	// Iterations is how many times each workload runs per mode, or with
+	// CIWidth set, the fewest runs before the interval is checked
+	Iterations int
+	// CIWidth, if set, keeps running iterations until the 95% confidence
+	// interval of each mode's mean is within ±CIWidth of it (0.02 for ±2%)
+	CIWidth float64
+	// MaxIterations caps the runs per mode when CIWidth is set
+	MaxIterations int
+	// BenchTime, if set, makes each iteration rerun the workload until
+	// BenchTime has passed, like testing.B, and time the average run
+	BenchTime time.Duration
+	// Outliers is the rule that discards wild timings from each mode
+	// before any statistic sees them; empty keeps them all
+	Outliers OutlierRule
+	// Robust centers results on the median and MAD instead of the mean
+	// and standard deviation
+	Robust bool
+	// Procs is GOMAXPROCS for the parallel runs
+	Procs int
+	// Sizes sizes the built-in workloads
+	Sizes Sizes
+	// Workloads are compared in order; empty means the built-in CPU, I/O
+	// and mixed workloads at Sizes
+	Workloads []Workload
+	// OrderSeed seeds the coin tosses that pick which mode runs first in
+	// each A/B/B/A block, so a seed reproduces the order
+	OrderSeed int64
+	// OnIteration, if set, is called after each iteration with its
+	// concurrent and parallel timings
+	OnIteration func(workload string, i int, concurrent, parallel time.Duration)
+}
+
+// DefaultConfig returns the settings the bench command uses without
+// flags.
+func DefaultConfig() Config {
+	return Config{Iterations: 5, MaxIterations: 50, Outliers: NoOutliers, Procs: runtime.NumCPU(), OrderSeed: time.Now().UnixNano(), Sizes: workloads.DefaultConfig()}
+}
+
+// Validate reports the first setting no comparison can run with.
+func (c Config) Validate() error {
+	if c.Iterations < 1 {
+		return fmt.Errorf("iterations %d is below 1", c.Iterations)
+	}
+	if c.CIWidth < 0 || c.CIWidth >= 1 {
+		return fmt.Errorf("CI width %g is outside [0, 1)", c.CIWidth)
+	}
+	if c.CIWidth > 0 {
+		if c.Iterations < 2 {
+			return fmt.Errorf("iterations %d is below 2, too few for a confidence interval", c.Iterations)
+		}
+		if c.MaxIterations < c.Iterations {
+			return fmt.Errorf("max iterations %d is below iterations %d", c.MaxIterations, c.Iterations)
+		}
+	}
+	if c.BenchTime < 0 {
+		return fmt.Errorf("benchtime %v is negative", c.BenchTime)
+	}
+	if c.Outliers != "" {
+		if _, err := stats.ParseOutlierRule(string(c.Outliers)); err != nil {
+			return err
+		}
+	}
+	if c.Procs < 1 {
+		return fmt.Errorf("gomaxprocs %d is below 1", c.Procs)
+	}
+	return c.Sizes.Validate()
+}
+
+func (c Config) String() string {
+	iterations := fmt.Sprint(c.Iterations)
+	if c.CIWidth > 0 {
+		iterations = fmt.Sprintf("%d-%d ci-width=±%g%%", c.Iterations, c.MaxIterations, c.CIWidth*100)
+	}
+	if c.BenchTime > 0 {
+		iterations += fmt.Sprintf(" benchtime=%v", c.BenchTime)
+	}
+	estimate := "mean"
+	if c.Robust {
+		estimate = "median"
+	}
+	return fmt.Sprintf("iterations=%s order-seed=%d outliers=%s estimate=%s gomaxprocs=%d %s",
+		iterations, c.OrderSeed, cmp.Or(c.Outliers, NoOutliers), estimate, c.Procs, c.Sizes)
+}
+
+// Runs returns how often Compare runs each workload.
+func (c Config) Runs() runner.Iterations {
+	runs := runner.Fixed(c.Iterations)
+	if c.CIWidth > 0 {
+		runs = runner.Iterations{Min: c.Iterations, Max: c.MaxIterations, RelativeCI: c.CIWidth}
+	}
+	runs.Window = c.BenchTime
+	runs.OrderSeed = c.OrderSeed
+	return runs
+}
+
+// Runner compares workloads under one Config.
+type Runner struct {
+	cfg Config
+}
+
+// NewRunner validates cfg and returns a Runner for it.
+func NewRunner(cfg Config) (*Runner, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Runner{cfg: cfg}, nil
+}
+
+// Config returns the Runner's settings.
+func (r *Runner) Config() Config { return r.cfg }
+
+// Compare times w at GOMAXPROCS=1 and at Procs as often as the config's
+// Runs say, settling the runtime before every run, then applies the
+// config's outlier rule and estimate. If ctx is done first, it returns the
+// iterations so far with ctx's error.
+func (r *Runner) Compare(ctx context.Context, w Workload) (Result, error) {
+	var onIteration func(int, time.Duration, time.Duration)
+	if r.cfg.OnIteration != nil {
+		onIteration = func(i int, concurrent, parallel time.Duration) {
+			r.cfg.OnIteration(w.Name(), i, concurrent, parallel)
+		}
+	}
+	result, err := runner.Compare(ctx, w, r.cfg.Procs, r.cfg.Runs(), onIteration)
+	result = result.RejectOutliers(r.cfg.Outliers)
+	result.Robust = r.cfg.Robust
+	return result, err
+}
+
+// Run compares every configured workload in order. It stops at the first
+// workload interrupted by ctx, returning the results so far, that
+// workload's iterations included, with ctx's error.
+func (r *Runner) Run(ctx context.Context) ([]Result, error) {
+	ws := r.cfg.Workloads
+	if len(ws) == 0 {
+		ws = r.cfg.Sizes.Basic()
+	}
+
+	var results []Result
+	for _, w := range ws {
+		result, err := r.Compare(ctx, w)
+		if err != nil {
+			if len(result.Concurrent)+len(result.ConcurrentOutliers) > 0 {
+				results = append(results, result)
+			}
+			return results, fmt.Errorf("%s: %w", w.Name(), err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Run compares cfg's workloads with a new Runner.
+func Run(ctx context.Context, cfg Config) ([]Result, error) {
+	r, err := NewRunner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return r.Run(ctx)
+}
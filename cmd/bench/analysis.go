@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+func (s *session) testArithmetic() {
+	fmt.Println("🧮 Arithmetic Throughput Microbenchmarks")
+	fmt.Println(strings.Repeat("-", 60))
+
+	procs := runtime.NumCPU()
+	c := workloads.MeasureCeilings()
+	s.ceilings = c
+
+	fmt.Printf("   Kernel       | 1 Core       | %2d Cores     | Scaling\n", procs)
+	fmt.Printf("   -------------|--------------|--------------|--------\n")
+	rows := []struct {
+		name        string
+		single, all float64
+	}{
+		{"Integer ALU", c.IntSingle, c.IntAll},
+		{"Float64", c.FloatSingle, c.FloatAll},
+		{"Saxpy (SIMD)", c.VectorSingle, c.VectorAll},
+	}
+	for _, r := range rows {
+		fmt.Printf("   %-12s | %6.2f Gop/s | %6.2f Gop/s | %.2fx\n",
+			r.name, r.single/1e9, r.all/1e9, r.all/r.single)
+	}
+	fmt.Printf("\n   Note: these are the machine's compute ceilings; pure ALU kernels should\n")
+	fmt.Printf("   scale almost linearly, so anything less points at frequency or SMT limits\n\n")
+}
+
+func testRoofline() {
+	fmt.Println("🏠 Roofline Analysis")
+	fmt.Println(strings.Repeat("-", 60))
+
+	procs := runtime.NumCPU()
+	a, b, c := workloads.StreamArrays()
+
+	runtime.GC()
+	peakOps := workloads.MeasurePeakIntOps(procs)
+	bandwidth := workloads.MeasureMemoryBandwidth(procs, b, a)
+	ridge := peakOps / bandwidth
+
+	fmt.Printf("   Compute ceiling:  %.2f Gops/s (%d cores)\n", peakOps/1e9, procs)
+	fmt.Printf("   Memory ceiling:   %.2f GB/s\n", bandwidth/1e9)
+	fmt.Printf("   Ridge point:      %.2f ops/byte\n\n", ridge)
+
+	fmt.Printf("   Kernel      | Intensity  | Achieved     | Roof         | %% Roof | Bound\n")
+	fmt.Printf("   ------------|------------|--------------|--------------|--------|--------\n")
+
+	for _, k := range workloads.RooflineKernels(a, b, c) {
+		runtime.GC()
+		elapsed := k.Run(procs)
+		achieved := k.Ops / elapsed.Seconds()
+
+		intensity := k.Intensity()
+		roof := math.Min(peakOps, bandwidth*intensity)
+		bound := "compute"
+		if intensity < ridge {
+			bound = "memory"
+		}
+
+		intensityStr := "∞"
+		if !math.IsInf(intensity, 1) {
+			intensityStr = fmt.Sprintf("%.3f", intensity)
+		}
+		fmt.Printf("   %-11s | %-10s | %6.2f Gop/s | %6.2f Gop/s | %5.1f%% | %s\n",
+			k.Name, intensityStr, achieved/1e9, roof/1e9, achieved/roof*100, bound)
+	}
+
+	fmt.Printf("\n   Note: memory-bound kernels stop scaling once the cores saturate the memory\n")
+	fmt.Printf("   ceiling; compute-bound kernels scale with cores until they hit the ALU roof\n\n")
+}
+
+func (s *session) testCBaseline() {
+	fmt.Println("🅲 C Baseline (Prime Workload, cgo + pthreads)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	if !workloads.CBaselineAvailable {
+		fmt.Println("   Unavailable: rebuild with `CGO_ENABLED=1 go build -tags cbaseline ./cmd/bench`")
+		fmt.Println()
+		return
+	}
+
+	iterations := 3
+	w := s.cfg.Sizes.CPU()
+	tasks := w.Tasks()
+	limit := s.cfg.Sizes.PrimeLimit
+	var goSerial, goParallel, cSerial, cParallel []time.Duration
+
+	onGo := s.onIteration("c-baseline", "Go", iterations)
+	onC := s.onIteration("c-baseline", "C", iterations)
+	for i := 0; i < iterations; i++ {
+		runtime.GC()
+		goSerial = append(goSerial, w.Run(1, nil))
+		goParallel = append(goParallel, w.Run(tasks, nil))
+		cSerial = append(cSerial, workloads.RunCPrimesSerial(tasks, limit))
+		cParallel = append(cParallel, workloads.RunCPrimesPthreads(tasks, limit))
+		onGo(i, goSerial[i], goParallel[i])
+		onC(i, cSerial[i], cParallel[i])
+	}
+
+	avgGoSerial, avgGoParallel := stats.Average(goSerial), stats.Average(goParallel)
+	avgCSerial, avgCParallel := stats.Average(cSerial), stats.Average(cParallel)
+
+	fmt.Printf("\n   Impl | 1 Thread   | %2d Threads | Speedup\n", tasks)
+	fmt.Printf("   -----|------------|------------|--------\n")
+	fmt.Printf("   Go   | %-10v | %-10v | %.2fx\n", avgGoSerial.Round(time.Microsecond),
+		avgGoParallel.Round(time.Microsecond), float64(avgGoSerial)/float64(avgGoParallel))
+	fmt.Printf("   C    | %-10v | %-10v | %.2fx\n", avgCSerial.Round(time.Microsecond),
+		avgCParallel.Round(time.Microsecond), float64(avgCSerial)/float64(avgCParallel))
+	fmt.Printf("\n   Go/C time ratio: %.2fx (1 thread), %.2fx (%d threads)\n\n",
+		float64(avgGoSerial)/float64(avgCSerial), float64(avgGoParallel)/float64(avgCParallel), tasks)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"strconv"
+	"time"
+
+	"compare_process/bench"
+)
+
+// configFlags registers the benchmark sizing flags on fs and returns a
+// function that builds the config once fs has been parsed.
+func configFlags(fs *flag.FlagSet) func() (bench.Config, error) {
+	defaults := bench.DefaultConfig()
+	iterations := fs.Int("iterations", defaults.Iterations, "runs averaged per mode in the cpu and io suites, -chaos and -sweep-procs")
+	ciWidth := fs.Float64("ci-width", 0, "keep iterating until the 95% CI of each mode's mean is within ±this fraction of it, e.g. 0.02 (0: run exactly -iterations)")
+	maxIterations := fs.Int("max-iterations", defaults.MaxIterations, "most runs per mode when -ci-width is set")
+	benchTime := fs.Duration("benchtime", 0, "rerun the workload in each iteration until this much time has passed, like go test -benchtime, and time the average run (0: one run per iteration)")
+	outliers := fs.String("outliers", string(defaults.Outliers), "discard outlying runs before the stats: none, tukey (1.5 IQR fences) or mad (modified z-score > 3.5)")
+	robust := fs.Bool("robust", false, "report the median and MAD of the runs instead of the mean and standard deviation")
+	orderSeed := fs.Int64("order-seed", defaults.OrderSeed, "random seed for which mode runs first in each A/B/B/A block of the cpu and io suites")
+	procs := fs.Int("gomaxprocs", defaults.Procs, "GOMAXPROCS for the parallel runs, and the core count the suites size their workers by")
+	primeLimit := fs.Int("prime-limit", defaults.Sizes.PrimeLimit, "how far each CPU task counts primes")
+	ioSleep := fs.Duration("io-sleep", defaults.Sizes.IOSleep, "simulated wait of one I/O operation")
+	ioOps := fs.Int("io-ops", defaults.Sizes.IOOps, "I/O operations per I/O task")
+	ioDist := fs.String("io-dist", "constant", "distribution of the simulated I/O waits around -io-sleep: constant, uniform, normal, lognormal or pareto")
+	ioSeed := fs.Int64("io-seed", time.Now().UnixNano(), "random seed for -io-dist")
+	goroutines := fs.Int("goroutines", 0, "goroutines per workload wave (default: 1 per core for cpu and mixed, 2 for io)")
+	micro := fs.Bool("micro", false, "also time each operation inside the basic workloads' tasks (adds clock reads to them)")
+	virtualClock := fs.Bool("virtual-clock", false, "run the I/O workload against a virtual clock: instant and reproducible, for checking the harness, not for measuring")
+	disk := fs.Bool("io-disk", false, "make the I/O and mixed workloads write and read real temp files instead of sleeping through -io-sleep")
+	diskBytes := fs.Int("io-disk-bytes", defaults.Sizes.DiskBytes, "bytes each -io-disk operation writes and reads back")
+	fsync := fs.Bool("io-fsync", false, "fsync each -io-disk write, so it waits on the device and not just the page cache")
+	diskDir := fs.String("io-dir", "", "directory for the -io-disk temp files (default: the OS temp dir)")
+	network := fs.Bool("io-net", false, "make the I/O and mixed workloads send real TCP requests to an echo server in the process, which answers after -io-sleep")
+	return func() (bench.Config, error) {
+		c := bench.Config{
+			Iterations:    *iterations,
+			CIWidth:       *ciWidth,
+			MaxIterations: *maxIterations,
+			BenchTime:     *benchTime,
+			Outliers:      bench.OutlierRule(*outliers),
+			Robust:        *robust,
+			OrderSeed:     *orderSeed,
+			Procs:         *procs,
+			Sizes: bench.Sizes{
+				PrimeLimit: *primeLimit,
+				IOSleep:    *ioSleep,
+				IOOps:      *ioOps,
+				IODist:     *ioDist,
+				IOSeed:     *ioSeed,
+				Goroutines: *goroutines,
+				Micro:      *micro,
+				Disk:       *disk,
+				DiskBytes:  *diskBytes,
+				Fsync:      *fsync,
+				DiskDir:    *diskDir,
+				Net:        *network,
+			},
+		}
+		if *virtualClock {
+			c.Sizes.Clock = bench.NewVirtualClock()
+		}
+		return c, c.Validate()
+	}
+}
+
+// configArgs renders c as the flags that reproduce it, for child
+// processes.
+func configArgs(c bench.Config) []string {
+	return []string{
+		"-iterations", strconv.Itoa(c.Iterations),
+		"-ci-width", strconv.FormatFloat(c.CIWidth, 'g', -1, 64),
+		"-max-iterations", strconv.Itoa(c.MaxIterations),
+		"-benchtime", c.BenchTime.String(),
+		"-outliers", string(c.Outliers),
+		"-robust=" + strconv.FormatBool(c.Robust),
+		"-order-seed", strconv.FormatInt(c.OrderSeed, 10),
+		"-gomaxprocs", strconv.Itoa(c.Procs),
+		"-prime-limit", strconv.Itoa(c.Sizes.PrimeLimit),
+		"-io-sleep", c.Sizes.IOSleep.String(),
+		"-io-ops", strconv.Itoa(c.Sizes.IOOps),
+		"-io-dist", cmp.Or(c.Sizes.IODist, "constant"),
+		"-io-seed", strconv.FormatInt(c.Sizes.IOSeed, 10),
+		"-goroutines", strconv.Itoa(c.Sizes.Goroutines),
+		"-micro=" + strconv.FormatBool(c.Sizes.Micro),
+		"-virtual-clock=" + strconv.FormatBool(c.Sizes.Virtual()),
+		"-io-disk=" + strconv.FormatBool(c.Sizes.Disk),
+		"-io-disk-bytes", strconv.Itoa(c.Sizes.DiskBytes),
+		"-io-fsync=" + strconv.FormatBool(c.Sizes.Fsync),
+		"-io-dir", c.Sizes.DiskDir,
+		"-io-net=" + strconv.FormatBool(c.Sizes.Net),
+	}
+}
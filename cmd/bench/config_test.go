@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"compare_process/bench"
+)
+
+// parseConfig parses args with the benchmark sizing flags alone.
+func parseConfig(args ...string) (bench.Config, error) {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	config := configFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return bench.Config{}, err
+	}
+	return config()
+}
+
+func TestConfigFlags(t *testing.T) {
+	defaults := bench.DefaultConfig()
+	tests := []struct {
+		name  string
+		args  []string
+		check func(c bench.Config) bool
+	}{
+		{"defaults", nil, func(c bench.Config) bool {
+			return c.Iterations == defaults.Iterations && c.Procs == defaults.Procs &&
+				c.Sizes.PrimeLimit == defaults.Sizes.PrimeLimit && c.Sizes.IOSleep == defaults.Sizes.IOSleep
+		}},
+		{"sizes", []string{"-iterations", "3", "-gomaxprocs", "2", "-prime-limit", "1000", "-io-sleep", "2ms", "-io-ops", "4", "-goroutines", "8"}, func(c bench.Config) bool {
+			return c.Iterations == 3 && c.Procs == 2 && c.Sizes.PrimeLimit == 1000 &&
+				c.Sizes.IOSleep == 2*time.Millisecond && c.Sizes.IOOps == 4 && c.Sizes.Goroutines == 8
+		}},
+		{"statistics", []string{"-ci-width", "0.05", "-max-iterations", "20", "-outliers", "mad", "-robust", "-order-seed", "9"}, func(c bench.Config) bool {
+			return c.CIWidth == 0.05 && c.MaxIterations == 20 && c.Outliers == bench.MADRule && c.Robust && c.OrderSeed == 9
+		}},
+		{"virtual clock", []string{"-virtual-clock"}, func(c bench.Config) bool { return c.Sizes.Virtual() }},
+		{"latency distribution", []string{"-io-dist", "pareto", "-io-seed", "3"}, func(c bench.Config) bool {
+			return c.Sizes.IODist == "pareto" && c.Sizes.IOSeed == 3
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseConfig(tt.args...)
+			if err != nil {
+				t.Fatalf("parsing %q: %v", tt.args, err)
+			}
+			if !tt.check(c) {
+				t.Errorf("parsing %q gave %s", tt.args, c)
+			}
+		})
+	}
+}
+
+func TestConfigFlagsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no iterations", []string{"-iterations", "0"}},
+		{"no cores", []string{"-gomaxprocs", "0"}},
+		{"ci width", []string{"-ci-width", "1"}},
+		{"max below iterations", []string{"-ci-width", "0.1", "-iterations", "10", "-max-iterations", "5"}},
+		{"outlier rule", []string{"-outliers", "grubbs"}},
+		{"prime limit", []string{"-prime-limit", "1"}},
+		{"latency distribution", []string{"-io-dist", "bimodal"}},
+		{"virtual disk", []string{"-io-disk", "-virtual-clock"}},
+		{"disk and network", []string{"-io-disk", "-io-net"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := parseConfig(tt.args...); err == nil {
+				t.Errorf("parsing %q gave %s, want an error", tt.args, c)
+			}
+		})
+	}
+}
+
+// TestConfigArgs checks configArgs renders a config as flags that parse
+// back to it, so an isolated child runs what its parent was asked to.
+func TestConfigArgs(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"-iterations", "7", "-gomaxprocs", "3", "-benchtime", "50ms", "-outliers", "tukey", "-order-seed", "11"},
+		{"-ci-width", "0.02", "-max-iterations", "40", "-robust", "-micro"},
+		{"-io-dist", "lognormal", "-io-seed", "5", "-goroutines", "12", "-io-sleep", "3ms"},
+		{"-io-disk", "-io-disk-bytes", "4096", "-io-fsync", "-io-dir", "."},
+		{"-io-net"},
+	}
+	for _, args := range tests {
+		want, err := parseConfig(args...)
+		if err != nil {
+			t.Fatalf("parsing %q: %v", args, err)
+		}
+		got, err := parseConfig(configArgs(want)...)
+		if err != nil {
+			t.Fatalf("parsing configArgs of %q: %v", args, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("configArgs of %q parsed back as %s, want %s", args, got, want)
+		}
+	}
+
+	// A virtual clock is a fresh one in the child
+	want, err := parseConfig("-virtual-clock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseConfig(configArgs(want)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Sizes.Virtual() || got.String() != want.String() {
+		t.Errorf("configArgs of -virtual-clock parsed back as %s, want %s", got, want)
+	}
+}
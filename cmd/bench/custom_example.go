@@ -0,0 +1,25 @@
+//go:build customexample
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"compare_process/bench"
+)
+
+// A build-tag hook: built with -tags customexample, this file registers a
+// hashing workload for the custom suite. Copy it under your own tag and
+// swap in your function to benchmark it without a plugin.
+func init() {
+	bench.RegisterCustom("sha256", 0, func(ctx context.Context, shard int) error {
+		block := make([]byte, 64<<10)
+		block[0] = byte(shard)
+		for i := 0; i < 100; i++ {
+			sum := sha256.Sum256(block)
+			copy(block, sum[:])
+		}
+		return ctx.Err()
+	})
+}
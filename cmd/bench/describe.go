@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// description documents one suite for `bench describe`
+type description struct {
+	measures string
+	params   []string // flags that shape the suite
+	expected string   // how it should scale
+	related  []string
+}
+
+// catalog describes every suite -suites accepts
+var catalog = map[string]description{
+	"cpu": {
+		measures: "Counting primes in one goroutine per core, at GOMAXPROCS=1 and at -gomaxprocs, with a significance test on the speedup.",
+		params:   []string{"-prime-limit", "-goroutines", "-iterations", "-ci-width", "-benchtime", "-outliers", "-robust", "-confidence", "-micro", "-gomaxprocs"},
+		expected: "Close to linear in the cores: near NumCPU× when there are at least as many goroutines as cores.",
+		related:  []string{"scalability", "recommend", "classify"},
+	},
+	"io": {
+		measures: "Goroutines that sleep through simulated requests (or with -io-disk write and read real temp files, or with -io-net make TCP requests to an in-process echo server), then a load curve of 1 to 256 clients with p50/p99 latency, then with -arrivals open-loop load with queueing delay and corrected and uncorrected percentiles.",
+		params:   []string{"-io-sleep", "-io-ops", "-io-dist", "-io-seed", "-io-disk", "-io-disk-bytes", "-io-fsync", "-io-dir", "-io-net", "-goroutines", "-iterations", "-ci-width", "-benchtime", "-confidence", "-micro", "-arrivals", "-arrival-rate", "-arrival-workers", "-arrival-requests"},
+		expected: "About 1×: waiting needs concurrency, not parallelism.",
+		related:  []string{"limits", "threads", "eventloop"},
+	},
+	"mixed": {
+		measures: "Alternating CPU and I/O goroutines, timed once in each mode, then with -arrivals open-loop load with queueing delay.",
+		params:   []string{"-prime-limit", "-io-sleep", "-io-ops", "-io-dist", "-io-seed", "-io-disk", "-io-disk-bytes", "-io-fsync", "-io-dir", "-io-net", "-goroutines", "-micro", "-arrivals", "-arrival-rate", "-arrival-workers", "-arrival-requests"},
+		expected: "Between the two: the CPU half scales, the I/O half doesn't.",
+		related:  []string{"cpu", "io", "classify"},
+	},
+	"limits": {
+		measures: "Throughput and p50/p99 latency of I/O and mixed requests through a pool capped at 1 to 64 in flight.",
+		params:   []string{"-p99-budget"},
+		expected: "Throughput grows with the cap until latency passes the budget; the recommendation is the smallest cap within 5% of the best.",
+		related:  []string{"adaptive", "io"},
+	},
+	"threads": {
+		measures: "OS threads the runtime adds when goroutines block on timers, locked threads, raw syscalls and C calls.",
+		expected: "Timers add none; syscalls and cgo calls add roughly one thread per blocked goroutine.",
+		related:  []string{"io", "footprint"},
+	},
+	"scalability": {
+		measures: "A fixed sum split across 1 to 4×NumCPU goroutines at every power-of-two GOMAXPROCS, as a heatmap with a Universal Scalability Law fit, then the sum cut into uneven tasks split statically or through a shared queue.",
+		expected: "Speedup tracks min(goroutines, GOMAXPROCS) and flattens past the cores; contention and crosstalk bend it down. On uneven tasks the queue finishes sooner with imbalance near 1×.",
+		related:  []string{"cpu", "recommend"},
+	},
+	"hybrid": {
+		measures: "The CPU workload pinned to each core class (performance, efficiency) of a hybrid CPU.",
+		expected: "Efficiency cores are slower per core, so NumCPU-based efficiency understates scaling. Skipped on uniform CPUs.",
+		related:  []string{"recommend", "cpu"},
+	},
+	"classify": {
+		measures: "CPU time against wall time, and cache misses via perf stat when installed, to label each workload compute-, memory- or I/O-bound.",
+		expected: "CPU is compute-bound, I/O is I/O-bound, mixed falls between.",
+		related:  []string{"cpu", "io", "mixed"},
+	},
+	"recommend": {
+		measures: "The CPU workload over GOMAXPROCS up to the quota, affinity and core-class limit of this host.",
+		expected: "The smallest GOMAXPROCS reaching 95% of peak throughput, usually the usable core count.",
+		related:  []string{"scalability", "hybrid"},
+	},
+	"ownership": {
+		measures: "Passing 16 KiB payloads between goroutines five ways: fresh pointer, sync.Pool, array copy, slice copy, and the aliasing bug.",
+		expected: "Pooling and pointer handoff allocate least; copies cost bandwidth; aliasing corrupts messages.",
+		related:  []string{"contention", "futures"},
+	},
+	"footprint": {
+		measures: "Stack and heap each parked goroutine holds, idle and a few calls deep.",
+		params:   []string{"-footprint-counts"},
+		expected: "A few KiB per idle goroutine, more once stacks grow.",
+		related:  []string{"threads", "eventloop"},
+	},
+	"contention": {
+		measures: "Throughput of one buffered channel with N:1, 1:N and N:N senders and receivers.",
+		expected: "Throughput peaks at a few goroutines, then collapses as they fight over the channel lock.",
+		related:  []string{"sharding", "broadcast"},
+	},
+	"sharding": {
+		measures: "The contention suite's volume through 1 to many channel shards merged downstream.",
+		expected: "More shards beat one channel once contention dominates.",
+		related:  []string{"contention", "accumulate"},
+	},
+	"adaptive": {
+		measures: "Fixed concurrency limits against an AIMD limiter in front of a backend that degrades under overload.",
+		expected: "AIMD settles near the backend's capacity without tuning; too high a fixed limit blows up latency.",
+		related:  []string{"limits"},
+	},
+	"broadcast": {
+		measures: "Publishing to 1 to 64 subscribers via channels per subscriber, a sync.Cond log, and an atomic snapshot.",
+		expected: "Channels scale linearly in cost per subscriber; the shared log and snapshot amortize it.",
+		related:  []string{"contention", "futures"},
+	},
+	"futures": {
+		measures: "Collecting 100,000 async results via channels, a WaitGroup and slice, and a generic Future[T].",
+		expected: "The shared slice is cheapest; channels and futures pay an allocation per call.",
+		related:  []string{"ownership", "dag"},
+	},
+	"barriers": {
+		measures: "Per-phase cost of WaitGroup fork-join, channel release and sync.Cond barriers with 1 to 4×NumCPU workers.",
+		expected: "Cost grows with workers; long-lived workers beat respawning goroutines.",
+		related:  []string{"dag", "bfs"},
+	},
+	"accumulate": {
+		measures: "Summing 8M adds in per-worker locals, false-shared slots, one atomic and one mutex.",
+		expected: "Locals scale; false sharing, the atomic and the mutex get slower with more workers.",
+		related:  []string{"sharding", "dedup"},
+	},
+	"eventloop": {
+		measures: "Goroutine-per-connection against a single deadline-heap loop for 100 to 10,000 connections.",
+		expected: "Similar throughput; the loop uses less memory, goroutines are simpler and spread over cores.",
+		related:  []string{"io", "footprint"},
+	},
+	"dag": {
+		measures: "NumCPU workers running wide, balanced, narrow and chain dependency graphs of 1,024 nodes.",
+		expected: "Speedup up to the work/span bound: near NumCPU for wide graphs, 1× for a chain.",
+		related:  []string{"barriers", "bfs"},
+	},
+	"bfs": {
+		measures: "Level-synchronous parallel BFS against a serial queue on a random graph and a grid.",
+		expected: "The random graph's huge frontiers scale; the grid's many small levels pay sync overhead each level.",
+		related:  []string{"dag", "barriers"},
+	},
+	"stream": {
+		measures: "Sliding-window aggregation of 1M events sharded across 1 to NumCPU goroutines, with watermark latency.",
+		expected: "Throughput grows with shards until the single source saturates.",
+		related:  []string{"sharding", "dedup"},
+	},
+	"dedup": {
+		measures: "Deduplicating 2M checksummed blocks into a mutex map, 64 sharded maps and a sync.Map.",
+		expected: "Sharded maps scale best; one mutex flattens early.",
+		related:  []string{"accumulate", "stream"},
+	},
+	"cancel": {
+		measures: "Time from context cancel to the last goroutine exiting, for workloads that check the context and ones that don't.",
+		params:   []string{"-prime-limit", "-io-sleep", "-io-ops"},
+		expected: "Checked loops stop in microseconds; unchecked ones run their task to the end.",
+		related:  []string{"cpu", "io"},
+	},
+	"gcpause": {
+		measures: "How late a 1ms ticker wakes beside allocating goroutines on the other cores, at each GOGC, with missed deadlines and a lateness histogram.",
+		params:   []string{"-gcpause-gogc"},
+		expected: "Higher GOGC runs fewer GC cycles and trims the ticker's tail; on one core the allocator's time slice dominates.",
+		related:  []string{"cancel", "footprint"},
+	},
+	"membw": {
+		measures: "STREAM's copy, scale, add and triad over 32 MiB arrays at each power-of-two GOMAXPROCS, in GB/s, beside the prime workload's speedup, then triad's single-core and all-core bandwidth and their ratio.",
+		expected: "Bandwidth flattens after a few cores while the prime workload keeps scaling to NumCPU.",
+		related:  []string{"cpu", "scalability", "accumulate"},
+	},
+	"counters": {
+		measures: "A counter shared by NumCPU workers behind sync.Mutex, sync.RWMutex, atomic.AddInt64 or per-worker shards, at GOMAXPROCS 1 and -gomaxprocs, for each read share.",
+		params:   []string{"-counter-primitives", "-counter-reads", "-gomaxprocs", "-iterations"},
+		expected: "Below 1× for mutex and atomic on writes as the cache line bounces; RWMutex gains only at high read shares; sharded increments scale.",
+		related:  []string{"contention", "sharding", "accumulate"},
+	},
+	"channels": {
+		measures: "Messages per second through producer/consumer pairs with their own channel, at each buffer size and 1, NumCPU and 4×NumCPU pairs, at GOMAXPROCS 1 and -gomaxprocs.",
+		params:   []string{"-channel-buffers", "-gomaxprocs"},
+		expected: "Unbuffered handoffs are cheap on one P and slow across Ps; larger buffers recover most of the parallel throughput.",
+		related:  []string{"contention", "sharding", "ownership"},
+	},
+	"appends": {
+		measures: "One million-item slice built by NumCPU workers through a shared slice under a mutex, preallocated indexed writes, per-worker slices merged at the end, or a channel to one collector, at GOMAXPROCS 1 and -gomaxprocs.",
+		params:   []string{"-append-strategies", "-gomaxprocs", "-iterations"},
+		expected: "Indexed and merge scale with the cores; mutex and channel stay near or below 1× as every item goes through one lock or one receiver.",
+		related:  []string{"counters", "accumulate", "channels"},
+	},
+	"maps": {
+		measures: "Operations per second on a 4096-key map shared by NumCPU workers as sync.Map, one map behind an RWMutex, or 64 sharded maps, for read-heavy, mixed and write-heavy traffic at each power-of-two GOMAXPROCS.",
+		params:   []string{"-map-impls", "-map-patterns"},
+		expected: "sync.Map scales on read-heavy traffic; the single RWMutex map flattens or falls as cores are added; the sharded map scales for every pattern.",
+		related:  []string{"counters", "sharding", "contention"},
+	},
+	"spawn": {
+		measures: "Goroutines created and torn down per second, wall time per spawn and the p50/p99 wait from go statement to first instruction, for batches of -spawn-counts goroutines at GOMAXPROCS 1 and -gomaxprocs.",
+		params:   []string{"-spawn-counts", "-gomaxprocs", "-iterations"},
+		expected: "A few hundred nanoseconds per spawn; more Ps add little throughput since one goroutine does all the spawning, but cut start latency.",
+		related:  []string{"footprint", "adaptive", "futures"},
+	},
+	"strings": {
+		measures: "Half a million formatted lines assembled into one string by NumCPU workers through per-worker builders joined at the end, one locked builder, or a channel of fragments, in MB/s with the MiB and allocations of each run, at GOMAXPROCS 1 and -gomaxprocs.",
+		params:   []string{"-string-strategies", "-gomaxprocs", "-iterations"},
+		expected: "Per-worker builders scale with the cores; the locked builder and the channel stay near 1× and the channel allocates once per line.",
+		related:  []string{"appends", "ownership", "channels"},
+	},
+	"errors": {
+		measures: "20k short tasks on NumCPU workers collecting errors errgroup-style, into a locked slice, or over a channel, with no failures, one early failure and 1% failing, with the tasks run and errors returned, at GOMAXPROCS 1 and -gomaxprocs.",
+		params:   []string{"-error-strategies", "-gomaxprocs", "-iterations"},
+		expected: "Equal cost with no failures; on an early failure errgroup runs a handful of tasks and returns one error while the others finish the run.",
+		related:  []string{"cancel", "futures"},
+	},
+	"pools": {
+		measures: "20k short computations and 2k 1ms waits run one goroutine per task, through a worker pool, or errgroup.SetLimit-style, with wall time, peak goroutines and MiB allocated, at GOMAXPROCS 1 and -gomaxprocs.",
+		params:   []string{"-pool-strategies", "-pool-limit", "-gomaxprocs", "-iterations"},
+		expected: "The pool wins on short computations; goroutine per task wins on waits, where a pool sized to the cores caps throughput.",
+		related:  []string{"spawn", "adaptive", "footprint"},
+	},
+	"loadgen": {
+		measures: "One I/O server frozen for -stall mid-run, driven by closed-loop clients timed from send, HdrHistogram-corrected and from schedule, and by open-loop steady arrivals, with p50, p99, p99.9 and max latency.",
+		params:   []string{"-stall", "-arrival-rate", "-arrival-workers", "-arrival-requests", "-gomaxprocs"},
+		expected: "The closed loop timed from send hides the stall in its tail; timed from schedule it agrees with the open loop, whose p99 is far higher.",
+		related:  []string{"io", "limits", "eventloop"},
+	},
+	"placement": {
+		measures: "Memory-bound kernels (STREAM triad, a shared atomic counter, a private L2-sized sum) with two workers pinned to hyperthread siblings, separate cores and separate sockets, against one worker pinned alone. Linux only.",
+		expected: "Siblings slow the private-cache and bandwidth kernels most; the shared counter is cheapest between siblings and dearest across sockets.",
+		related:  []string{"membw", "hybrid", "counters"},
+	},
+	"pipeline": {
+		measures: "Items per second through a parse → transform → aggregate pipeline at each set of stage widths and channel buffer, at GOMAXPROCS 1 and -gomaxprocs, with each stage's utilization and the bottleneck named, then a search for the fastest per-stage split of -pipeline-budget goroutines.",
+		params:   []string{"-pipeline-widths", "-pipeline-buffers", "-pipeline-budget", "-gomaxprocs"},
+		expected: "Transform limits narrow pipelines; widening it scales with the cores until the single aggregate stage becomes the bottleneck. The tuner gives most of the budget to transform.",
+		related:  []string{"stream", "channels", "dag"},
+	},
+	"custom": {
+		measures: "Your own func(ctx, shard) error, from a -plugin or bench.RegisterCustom, timed like the cpu suite.",
+		params:   []string{"-plugin", "-goroutines", "-iterations", "-ci-width", "-benchtime", "-outliers", "-robust", "-confidence", "-run"},
+		expected: "Up to NumCPU× for CPU-bound shards, about 1× for shards that wait; errors are counted per run.",
+		related:  []string{"cpu", "io", "classify"},
+	},
+}
+
+// runDescribeCommand implements `bench describe`: it prints what a suite
+// measures, the flags that shape it, how it should scale and related
+// suites. Without a name it lists the suites.
+func runDescribeCommand(args []string) error {
+	if len(args) == 0 {
+		fmt.Println("Suites (bench describe <suite> for details):")
+		for _, name := range slices.Concat(defaultSuites, extraSuites) {
+			fmt.Printf("   %-12s %s\n", name, catalog[name].measures)
+		}
+		return nil
+	}
+
+	for i, name := range args {
+		d, ok := catalog[name]
+		if !ok {
+			return fmt.Errorf("describe: unknown suite %q (want %s)", name, strings.Join(slices.Concat(defaultSuites, extraSuites), ", "))
+		}
+		w := os.Stdout
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if slices.Contains(defaultSuites, name) {
+			fmt.Fprintf(w, "%s (runs by default)\n", name)
+		} else {
+			fmt.Fprintf(w, "%s (run with -suites %s)\n", name, name)
+		}
+		fmt.Fprintf(w, "   Measures:   %s\n", d.measures)
+		if len(d.params) > 0 {
+			fmt.Fprintf(w, "   Parameters: %s\n", strings.Join(d.params, " "))
+		}
+		fmt.Fprintf(w, "   Expected:   %s\n", d.expected)
+		if len(d.related) > 0 {
+			fmt.Fprintf(w, "   Related:    %s\n", strings.Join(d.related, ", "))
+		}
+	}
+	return nil
+}
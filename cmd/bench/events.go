@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"compare_process/bench"
+	"compare_process/internal/events"
+	"compare_process/internal/report"
+	"compare_process/internal/runner"
+	"compare_process/internal/workloads"
+)
+
+// runSuite runs one suite between SuiteStarted and SuiteFinished events,
+// recording the scheduler latency it caused. An isolated suite brings its
+// child's recording instead, since the parent only waited.
+//
+// A panic in the suite or in any guarded workload goroutine, or the suite
+// going over a resource cap, fails just this suite: it is recorded with
+// the reason and the session moves on. After a panic the session waits
+// up to panicGrace for the suite to return, which it does when the
+// guarded goroutine's unwinding releases whatever waits on it. A suite
+// still blocked after that, such as one whose consumer waits on a send
+// the panic skipped, is abandoned with its goroutines. A suite over its
+// cap is still running and can't be stopped from outside, so caps only
+// apply in an -isolate child, which exits instead.
+func (s *session) runSuite(name string, run func()) {
+	s.suite = name
+	defer func() { s.suite = "" }()
+	s.bus.Publish(events.SuiteStarted{Suite: name})
+	start := time.Now()
+	watch := runner.StartSchedWatch()
+	recorded := len(s.sched)
+	if running, err := guarded(run, s.caps); err != nil {
+		s.fail(name, err)
+		if _, over := err.(*runner.CapExceeded); over {
+			s.exitOverCap()
+		}
+		if running {
+			s.warn("suite "+name+" did not return after its panic; abandoned it", nil)
+		}
+	}
+	if len(s.sched) == recorded {
+		s.sched = append(s.sched, runner.SuiteSched{Suite: name, SchedLatency: watch.Stop()})
+	}
+	suite := s.unreported(name)
+	for _, o := range s.outputs {
+		if err := o.reporter.SuiteResult(suite); err != nil {
+			s.warn("reporting suite failed", err)
+		}
+	}
+	s.bus.Publish(events.SuiteFinished{Suite: name, Elapsed: time.Since(start)})
+}
+
+// unreported returns what the session recorded since the last suite was
+// reported, as suite name's, and marks it reported. That includes the
+// cooldown taken before the suite started.
+func (s *session) unreported(name string) report.Suite {
+	suite := report.Suite{
+		Name:      name,
+		Results:   s.results[s.reported.results:],
+		Cooldowns: s.cooldowns[s.reported.cooldowns:],
+		Sched:     s.sched[s.reported.sched:],
+		Failures:  s.failures[s.reported.failures:],
+		Scaling:   s.scaling[s.reported.scaling:],
+	}
+	s.reported.results, s.reported.cooldowns, s.reported.sched = len(s.results), len(s.cooldowns), len(s.sched)
+	s.reported.failures, s.reported.scaling = len(s.failures), len(s.scaling)
+	return suite
+}
+
+// capInterval is how often guarded checks the resource caps
+const capInterval = 50 * time.Millisecond
+
+// panicGrace is how long guarded waits for a suite to return after a
+// panic before abandoning it
+const panicGrace = 5 * time.Second
+
+// guarded runs run on its own goroutine and waits for it to return, or
+// for it to go over caps. A panic in it or a workload goroutine fails it,
+// and guarded waits up to panicGrace more for it to return so it doesn't
+// race the session afterwards. A suite over its caps, or still blocked
+// after the grace, is left running and reported so. GOMAXPROCS is
+// restored after a failure since the deferred restore may not have run.
+// The error is a *workloads.Panic or a *runner.CapExceeded.
+func guarded(run func(), caps runner.Caps) (running bool, err error) {
+	// An echo of the last suite's panic isn't this one's
+	select {
+	case <-workloads.Panics():
+	default:
+	}
+
+	procs := runtime.GOMAXPROCS(0)
+	capWatch := runner.WatchCaps(caps, capInterval)
+	defer capWatch.Stop()
+	done := make(chan struct{})
+	go func() {
+		// Guard reports before done closes, so a panic is never missed
+		defer close(done)
+		defer workloads.Guard()
+		run()
+	}()
+	select {
+	case <-done:
+		// The suite may have guarded a panic and carried on
+		select {
+		case p := <-workloads.Panics():
+			runtime.GOMAXPROCS(procs)
+			return false, p
+		default:
+			return false, nil
+		}
+	case p := <-workloads.Panics():
+		select {
+		case <-done:
+		case <-time.After(panicGrace):
+			running = true
+		}
+		runtime.GOMAXPROCS(procs)
+		return running, p
+	case e := <-capWatch.C:
+		runtime.GOMAXPROCS(procs)
+		return true, e
+	}
+}
+
+// exitOverCapCode is an -isolate child's exit status after its suite went
+// over a resource cap
+const exitOverCapCode = 3
+
+// exitOverCap ends an isolated child whose suite went over a cap. The
+// suite is still running, so nothing it recorded can be read safely: the
+// child closes its event log, which already has the failure, and exits,
+// taking the suite's goroutines with it. The parent records the failure
+// from the log.
+func (s *session) exitOverCap() {
+	if err := s.bus.Close(); err != nil {
+		slog.Error("delivering events failed", "err", err)
+	}
+	os.Exit(exitOverCapCode)
+}
+
+// fail records suite as stopped by err, for the report and the event
+// stream. A panic brings its stack.
+func (s *session) fail(suite string, err error) {
+	f := report.SuiteFailure{Suite: suite, Reason: err.Error()}
+	if p, ok := err.(*workloads.Panic); ok {
+		f.Stack = string(p.Stack)
+	}
+	s.failures = append(s.failures, f)
+	s.bus.Publish(events.SuiteFailed{Suite: f.Suite, Reason: f.Reason, Stack: f.Stack})
+}
+
+// onIteration returns a runner.Compare callback publishing each iteration
+// of suite.
+func (s *session) onIteration(suite, workload string, iterations int) func(int, time.Duration, time.Duration) {
+	return func(i int, concurrent, parallel time.Duration) {
+		s.bus.Publish(events.IterationCompleted{
+			Suite:      suite,
+			Workload:   workload,
+			Iteration:  i + 1,
+			Of:         iterations,
+			Concurrent: concurrent,
+			Parallel:   parallel,
+		})
+	}
+}
+
+// publishIteration is the bench runner's iteration callback, publishing
+// each iteration under the running suite. With a CI target the run count
+// isn't known ahead, so iterations count toward the cap.
+func (s *session) publishIteration(workload string, i int, concurrent, parallel time.Duration) {
+	s.onIteration(s.suite, workload, s.cfg.Runs().Max)(i, concurrent, parallel)
+}
+
+// compare times w with the bench runner. The session's context never
+// ends, so it always completes.
+func (s *session) compare(w bench.Workload) bench.Result {
+	r, _ := s.bench.Compare(context.Background(), w)
+	return r
+}
+
+func (s *session) warn(msg string, err error) {
+	w := events.Warning{Message: msg}
+	if err != nil {
+		w.Err = err.Error()
+	}
+	s.bus.Publish(w)
+}
+
+// runIsolated runs suite in a child process, adding its results to the
+// session and forwarding its iteration and warning events to the export
+// sinks. The child logged them to the console itself.
+func (s *session) runIsolated(suite string, args []string) {
+	dir, err := os.MkdirTemp("", "bench-isolate-")
+	if err != nil {
+		s.warn("creating isolation directory failed", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run, err := runner.RunIsolated(dir, suite, args...)
+	if err != nil {
+		// A child over a resource cap exits without results, leaving its
+		// failure in the event log
+		if s.replayEvents(run.EventPath, true) == 0 {
+			s.warn("isolated suite failed", err)
+		}
+		return
+	}
+
+	file, err := report.ReadJSON(run.ResultPath)
+	if err != nil {
+		s.warn("reading isolated suite results failed", err)
+		return
+	}
+	s.results = append(s.results, file.RunnerResults()...)
+	s.sched = append(s.sched, file.Sched...)
+	s.failures = append(s.failures, file.Failures...)
+	s.scaling = append(s.scaling, file.Scaling...)
+	s.replayEvents(run.EventPath, false)
+}
+
+// replayEvents forwards an isolated child's iteration, failure and warning
+// events from its log at path to the export sinks. A child that exited
+// early left no results file, so with recordFailures its failures are
+// added to the session from the log instead. It returns how many failures
+// the log had.
+func (s *session) replayEvents(path string, recordFailures bool) int {
+	f, err := os.Open(path)
+	if err != nil {
+		s.warn("reading isolated suite events failed", err)
+		return 0
+	}
+	defer f.Close()
+	failed := 0
+	err = events.ReadJSONL(f, func(at time.Time, e events.Event) {
+		switch e := e.(type) {
+		case events.SuiteFailed:
+			failed++
+			if recordFailures {
+				s.failures = append(s.failures, report.SuiteFailure{Suite: e.Suite, Reason: e.Reason, Stack: e.Stack})
+			}
+			s.exports.Emit(at, e)
+		case events.IterationCompleted, events.Warning:
+			s.exports.Emit(at, e)
+		}
+	})
+	if err != nil {
+		s.warn("reading isolated suite events failed", err)
+	}
+	return failed
+}
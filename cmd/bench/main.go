@@ -0,0 +1,704 @@
+// Command bench compares goroutine concurrency (GOMAXPROCS=1) against
+// parallelism (GOMAXPROCS=NumCPU) across CPU, I/O and mixed workloads.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"compare_process/bench"
+	"compare_process/internal/events"
+	"compare_process/internal/report"
+	"compare_process/internal/runner"
+	"compare_process/internal/sysinfo"
+	"compare_process/internal/workloads"
+)
+
+// suiteRun is one named suite of the main benchmark run
+type suiteRun struct {
+	name string
+	run  func()
+}
+
+// subcommands work on saved results or the suite catalog instead of
+// running the benchmark
+var subcommands = map[string]func(args []string) error{
+	"report":    runReportCommand,
+	"aggregate": runAggregateCommand,
+	"describe":  runDescribeCommand,
+	"compare":   runCompareCommand,
+}
+
+// defaultSuites are the suites run unless -suites says otherwise
+var defaultSuites = []string{"cpu", "io", "mixed", "limits", "threads", "scalability", "hybrid", "classify", "recommend"}
+
+// extraSuites only run when -suites names them
+var extraSuites = []string{"ownership", "footprint", "contention", "sharding", "adaptive", "broadcast", "futures", "barriers", "accumulate", "eventloop", "dag", "bfs", "stream", "dedup", "cancel", "gcpause", "membw", "counters", "channels", "appends", "maps", "spawn", "strings", "errors", "pools", "loadgen", "pipeline", "placement", "custom"}
+
+// selectSuites returns the suites of the comma-separated list whose names
+// match run. Unless fromList is set the candidates are every suite, the
+// way go test -run picks from every test.
+func selectSuites(list string, fromList bool, run *regexp.Regexp) ([]string, error) {
+	all := slices.Concat(defaultSuites, extraSuites)
+	candidates := all
+	if fromList {
+		candidates = splitList(list)
+	}
+	var selected []string
+	for _, name := range candidates {
+		if !slices.Contains(all, name) {
+			return nil, fmt.Errorf("unknown suite %q (want %s)", name, strings.Join(all, ","))
+		}
+		if run.MatchString(name) || name == "custom" && len(customWorkloads(run)) > 0 {
+			selected = append(selected, name)
+		}
+	}
+	return selected, nil
+}
+
+// printList prints what -list shows: the suites and registered workloads
+// a run with the same -run and -suites would execute.
+func printList(suites []string, registered []workloads.Registration) {
+	fmt.Println("Suites:")
+	for _, name := range suites {
+		fmt.Printf("   %s\n", name)
+	}
+	fmt.Println("Workloads:")
+	for _, r := range registered {
+		fmt.Printf("   %-12s %s\n", r.Name, r.New(workloads.DefaultConfig()).Name())
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if command, ok := subcommands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	raceLesson := flag.Bool("race-lesson", false, "run racy workloads (build with -race to see reports), then corrected versions")
+	chaos := flag.Bool("chaos", false, "measure workload robustness under random GC, GOMAXPROCS changes and spin bursts")
+	chaosSeed := flag.Int64("chaos-seed", time.Now().UnixNano(), "random seed for -chaos")
+	sweepProcs := flag.Bool("sweep-procs", false, "time the CPU workload at every GOMAXPROCS from 1 to NumCPU and print the speedup curve")
+	expect := flag.Bool("expect", false, "check the outcomes each registered workload expects, such as CPU scaling and flat I/O, and flag surprises")
+	antagonistKind := flag.String("antagonist", "", "run a noisy-neighbor process during the suites: cpu or mem")
+	antagonistCores := flag.Int("antagonist-cores", max(1, runtime.NumCPU()/2), "cores the antagonist occupies")
+	antagonistMemMB := flag.Int("antagonist-mem-mb", 256, "buffer size for the mem antagonist")
+	antagonistWorker := flag.String("antagonist-worker", "", "internal: run as the antagonist child process")
+	arith := flag.Bool("arith", false, "measure integer, float and vector throughput ceilings before the suites")
+	cBaseline := flag.Bool("c-baseline", false, "compare the prime workload against a C/pthreads baseline (build with -tags cbaseline)")
+	suites := flag.String("suites", strings.Join(defaultSuites, ","), "comma-separated suites to run")
+	runPattern := flag.String("run", "", "run only the suites and registered workloads whose names match this regexp; without -suites it picks from every suite")
+	list := flag.Bool("list", false, "list the suites and registered workloads -run selects, then exit")
+	plugins := flag.String("plugin", "", "comma-separated workload plugins (.so) for the custom suite")
+	gcGrid := flag.Bool("gc-grid", false, "re-run -suites in child processes across a GOGC × GOMEMLIMIT grid")
+	gogcValues := flag.String("gc-grid-gogc", "50,100,200,400", "GOGC values for -gc-grid")
+	memLimitValues := flag.String("gc-grid-memlimit", "off,256MiB,1GiB", "GOMEMLIMIT values for -gc-grid")
+	godebugGrid := flag.Bool("godebug-grid", false, "re-run -suites in child processes under each -godebug-settings value and report the change")
+	godebugSettings := flag.String("godebug-settings", "asyncpreemptoff=1;cpu.all=off", "semicolon-separated GODEBUG values for -godebug-grid")
+	calibrate := flag.Bool("calibrate", false, "size -prime-limit and -io-ops so one goroutine's run takes -calibrate-target on this machine")
+	calibrateTarget := flag.Duration("calibrate-target", time.Second, "single-goroutine run time -calibrate aims for")
+	confidence := flag.Float64("confidence", 0.95, "confidence level for the speedup significance test in the cpu and io suites")
+	p99Budget := flag.Duration("p99-budget", 20*time.Millisecond, "p99 latency budget for the concurrency-limit recommendation")
+	arrivals := flag.String("arrivals", "", "after the io and mixed suites' closed-loop runs, offer open-loop load under these arrival processes: steady, poisson, bursts")
+	arrivalRate := flag.Float64("arrival-rate", 0, "mean requests per second for -arrivals (0 = 80% of the workers' capacity)")
+	arrivalWorkers := flag.Int("arrival-workers", 16, "server workers serving -arrivals requests")
+	arrivalRequests := flag.Int("arrival-requests", 2000, "requests offered per arrival process")
+	pipelineWidths := flag.String("pipeline-widths", "1,2,4,8", "goroutines in the pipeline suite's stages: a width for parse and transform fanning in to one aggregate, or parse/transform/aggregate such as 2/6/1")
+	pipelineBudget := flag.Int("pipeline-budget", 0, "goroutines the pipeline suite's tuner shares out between the stages (0 = 2 per -gomaxprocs core, at least 6)")
+	pipelineBuffers := flag.String("pipeline-buffers", "0,64", "capacities of the channels between the pipeline suite's stages")
+	stall := flag.Duration("stall", 100*time.Millisecond, "how long the loadgen suite freezes its server halfway through each run")
+	appendStrategies := flag.String("append-strategies", "mutex,indexed,merge,channel", "append strategies for the appends suite")
+	poolStrategies := flag.String("pool-strategies", "per-task,pool,limited", "task running strategies for the pools suite")
+	poolLimit := flag.Int("pool-limit", 0, "workers of the pools suite's pool and limit (0 = -gomaxprocs, at least 8)")
+	errorStrategies := flag.String("error-strategies", "errgroup,multierror,channel", "error collection strategies for the errors suite")
+	buildStrategies := flag.String("string-strategies", "join,locked,channel", "string building strategies for the strings suite")
+	spawnCounts := flag.String("spawn-counts", "1000,100000,1000000", "goroutine counts for the spawn suite")
+	mapImpls := flag.String("map-impls", "syncmap,mutex,sharded", "map implementations for the maps suite")
+	mapPatterns := flag.String("map-patterns", "read-heavy,mixed,write-heavy", "access patterns for the maps suite")
+	channelBuffers := flag.String("channel-buffers", "0,1,64,1024", "channel capacities for the channels suite")
+	counterPrimitives := flag.String("counter-primitives", "mutex,rwmutex,atomic,sharded", "counter primitives for the counters suite")
+	counterReads := flag.String("counter-reads", "0,90,99", "percentages of reads among the counters suite's operations")
+	gcpauseGOGC := flag.String("gcpause-gogc", "25,100,400", "GOGC values for the gcpause suite")
+	footprintCounts := flag.String("footprint-counts", "10000,100000,1000000", "goroutine counts for the footprint suite")
+	cooldown := flag.Duration("cooldown", 0, "idle time between suites so earlier ones don't thermally penalize later ones")
+	cooldownFreq := flag.Bool("cooldown-freq", false, "extend -cooldown until the CPU frequency recovers to its starting value")
+	isolate := flag.Bool("isolate", false, "run each suite in a fresh child process so GOMAXPROCS, GC and heap state can't carry over")
+	isolatedChild := flag.Bool("isolated-child", false, "internal: run as an isolated suite child process")
+	maxHeapMB := flag.Int("max-heap-mb", 0, "fail a suite whose heap grows past this many MiB, with -isolate (0: no cap)")
+	maxGoroutines := flag.Int("max-goroutines", 0, "fail a suite once the process has more goroutines than this, with -isolate (0: no cap)")
+	shuffleSuites := flag.Bool("shuffle-suites", false, "run the selected suites in random order")
+	flag.BoolVar(shuffleSuites, "shuffle", false, "shorthand for -shuffle-suites")
+	shuffleSeed := flag.Int64("shuffle-seed", time.Now().UnixNano(), "random seed for -shuffle-suites")
+	jsonOut := flag.String("json", "", "write results and run metadata to this JSON file")
+	csvOut := flag.String("csv", "", "write every iteration's duration to this CSV file")
+	formats := flag.String("format", "console", "comma-separated run reports, each name or name=path to write a file instead of stdout: "+strings.Join(report.ReporterNames(), ", "))
+	bundleOut := flag.String("bundle", "", "write JSON, CSV, Markdown, HTML and charts into a timestamped directory under this one")
+	roofline := flag.Bool("roofline", false, "add a roofline analysis of compute vs memory ceilings after the suites")
+	logFormat := flag.String("log-format", "text", "progress log format on stderr: text or json")
+	logLevel := flag.String("log-level", "info", "minimum progress log level: debug, info, warn or error")
+	eventsJSONL := flag.String("events-jsonl", "", "also write progress events to this file, one JSON object per line")
+	eventsURL := flag.String("events-url", "", "also POST each progress event as JSON to this URL")
+	numberStyle := numberFlags(flag.CommandLine)
+	benchConfig := configFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger, err := newLogger(*logFormat, *logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	style, err := numberStyle()
+	if err != nil {
+		fatal(2, "invalid report number format", "err", err)
+	}
+	cfg, err := benchConfig()
+	if err != nil {
+		fatal(2, "invalid benchmark config", "err", err)
+	}
+	for _, path := range splitList(*plugins) {
+		if err := loadPlugin(path); err != nil {
+			fatal(2, "invalid -plugin", "err", err)
+		}
+	}
+	run, err := regexp.Compile(*runPattern)
+	if err != nil {
+		fatal(2, "invalid -run", "err", err)
+	}
+	suitesSet := false
+	flag.Visit(func(f *flag.Flag) { suitesSet = suitesSet || f.Name == "suites" })
+	selected, err := selectSuites(*suites, suitesSet || *runPattern == "", run)
+	if err != nil {
+		fatal(2, "unknown suite", "err", err)
+	}
+	if *list {
+		printList(selected, workloads.Registered(run))
+		return
+	}
+	if *calibrate {
+		if *calibrateTarget <= 0 {
+			fatal(2, "invalid -calibrate-target, want a positive duration", "value", *calibrateTarget)
+		}
+		slog.Info("calibrating workload sizes", "target", *calibrateTarget)
+		var cal runner.Calibration
+		cfg.Sizes, cal = runner.Calibrate(cfg.Sizes, *calibrateTarget)
+		slog.Info("calibrated workload sizes",
+			"prime_limit", cal.PrimeLimit, "cpu_run", cal.CPU.Round(time.Millisecond),
+			"io_ops", cal.IOOps, "io_run", cal.IO.Round(time.Millisecond))
+	}
+
+	// exports feeds only the machine-readable sinks, so events replayed
+	// from -isolate children reach them without being logged twice
+	exports := &events.Bus{}
+	if *eventsJSONL != "" {
+		sink, err := events.CreateJSONLSink(*eventsJSONL)
+		if err != nil {
+			fatal(2, "opening event log failed", "err", err)
+		}
+		exports.Subscribe(sink)
+	}
+	if *eventsURL != "" {
+		exports.Subscribe(events.NewHTTPSink(*eventsURL))
+	}
+	bus := &events.Bus{}
+	bus.Subscribe(events.ConsoleSink{Logger: logger})
+	bus.Subscribe(exports)
+	defer func() {
+		if err := bus.Close(); err != nil {
+			slog.Error("delivering events failed", "err", err)
+		}
+	}()
+	if *confidence <= 0 || *confidence >= 1 {
+		fatal(2, "invalid -confidence, want a level between 0 and 1", "value", *confidence)
+	}
+	if *maxHeapMB < 0 || *maxGoroutines < 0 {
+		fatal(2, "invalid resource cap, want 0 or more", "max-heap-mb", *maxHeapMB, "max-goroutines", *maxGoroutines)
+	}
+	caps := runner.Caps{MaxHeap: uint64(*maxHeapMB) << 20, MaxGoroutines: *maxGoroutines}
+	if caps.Enabled() && !*isolate && !*isolatedChild {
+		fatal(2, "-max-heap-mb and -max-goroutines need -isolate, since a suite over its cap can only be stopped by ending its process")
+	}
+	s := &session{bus: bus, exports: exports, cfg: cfg, confidence: *confidence, run: run}
+	// The child running a suite enforces its caps and exits when it goes
+	// over; the parent only waits
+	if *isolatedChild {
+		s.caps = caps
+	}
+	cfg.OnIteration = s.publishIteration
+	if s.bench, err = bench.NewRunner(cfg); err != nil {
+		fatal(2, "invalid benchmark config", "err", err)
+	}
+
+	if *antagonistWorker != "" {
+		runner.RunAntagonistWorker(*antagonistWorker, *antagonistCores, *antagonistMemMB)
+		return
+	}
+
+	meta := report.CollectMetadata(version)
+	meta.OrderSeed = cfg.OrderSeed
+
+	// An isolated child leaves the console summary to its parent, which
+	// sees every suite's results; -json and -csv are reporters too
+	reports := splitList(*formats)
+	if *isolatedChild {
+		reports = slices.DeleteFunc(reports, func(f string) bool { return f == "console" })
+	}
+	if *jsonOut != "" {
+		reports = append(reports, "json="+*jsonOut)
+	}
+	if *csvOut != "" {
+		reports = append(reports, "csv="+*csvOut)
+	}
+	if s.outputs, err = openOutputs(reports, style); err != nil {
+		fatal(2, "invalid -format", "err", err)
+	}
+	for _, o := range s.outputs {
+		if err := o.reporter.Start(meta); err != nil {
+			fatal(1, "starting report failed", "err", err)
+		}
+	}
+	s.clock = meta.Clock
+	coreClasses, coreClassesErr := sysinfo.DetectCoreClasses()
+
+	// An isolated child's parent has already printed the header
+	if !*isolatedChild {
+		fmt.Println("🚀 Goroutine Concurrency vs Parallelism Benchmark")
+		fmt.Println(strings.Repeat("=", 60))
+
+		// Show system info
+		fmt.Printf("Benchmark: %s\n", meta.Build)
+		fmt.Printf("CPU Model: %s\n", valueOr(meta.CPU.Model, "unknown"))
+		fmt.Printf("CPU Freq: %s\n", meta.CPU.Frequencies())
+		fmt.Printf("Caches: %s\n", meta.CPU.Caches())
+		fmt.Printf("CPU Cores: %d\n", runtime.NumCPU())
+		fmt.Printf("GOMAXPROCS: %d\n", runtime.GOMAXPROCS(0))
+		fmt.Printf("Go Version: %s\n", runtime.Version())
+		fmt.Printf("OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+		fmt.Printf("Environment: %s\n", meta.Env)
+		fmt.Printf("Monitors: %s\n", sysinfo.Capabilities())
+		fmt.Printf("Clock: %s\n", meta.Clock)
+		fmt.Printf("Config: %s\n", cfg)
+		if coreClassesErr != nil {
+			fmt.Printf("Core classes: unknown (%v)\n", coreClassesErr)
+		} else if len(coreClasses) > 1 {
+			fmt.Printf("Core classes: %s (hybrid; NumCPU-based efficiency understates scaling)\n",
+				sysinfo.DescribeCoreClasses(coreClasses))
+		}
+		fmt.Println()
+		if runtime.GOOS == "js" {
+			fmt.Println("Note: js/wasm runs on a single thread; GOMAXPROCS > 1 adds no parallelism")
+			fmt.Println()
+		}
+	}
+
+	if *raceLesson {
+		s.runRaceLesson()
+		return
+	}
+	if *chaos {
+		s.runChaosMode(*chaosSeed)
+		return
+	}
+	if *sweepProcs {
+		s.runProcsSweep()
+		return
+	}
+	if *expect {
+		s.runExpectations()
+		return
+	}
+	if *gcGrid {
+		args := append([]string{"-run", *runPattern}, configArgs(cfg)...)
+		if err := runGCGrid(strings.Join(selected, ","), splitList(*gogcValues), splitList(*memLimitValues), args); err != nil {
+			fatal(1, "gc grid failed", "err", err)
+		}
+		return
+	}
+	if *godebugGrid {
+		var settings []string
+		for _, setting := range strings.Split(*godebugSettings, ";") {
+			if setting = strings.TrimSpace(setting); setting != "" {
+				settings = append(settings, setting)
+			}
+		}
+		args := append([]string{"-run", *runPattern}, configArgs(cfg)...)
+		if err := runGODEBUGGrid(strings.Join(selected, ","), settings, args); err != nil {
+			fatal(1, "godebug grid failed", "err", err)
+		}
+		return
+	}
+
+	var counts []int
+	for _, c := range splitList(*footprintCounts) {
+		n, err := strconv.Atoi(c)
+		if err != nil || n <= 0 {
+			fatal(2, "invalid -footprint-counts", "value", c)
+		}
+		counts = append(counts, n)
+	}
+	if len(counts) == 0 {
+		fatal(2, "invalid -footprint-counts, want at least one count")
+	}
+
+	var gogc []int
+	for _, v := range splitList(*gcpauseGOGC) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			fatal(2, "invalid -gcpause-gogc, want positive GOGC values", "value", v)
+		}
+		gogc = append(gogc, n)
+	}
+	if len(gogc) == 0 {
+		fatal(2, "invalid -gcpause-gogc, want at least one GOGC value")
+	}
+
+	var buffers []int
+	for _, v := range splitList(*channelBuffers) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			fatal(2, "invalid -channel-buffers, want capacities of 0 or more", "value", v)
+		}
+		buffers = append(buffers, n)
+	}
+	if len(buffers) == 0 {
+		fatal(2, "invalid -channel-buffers, want at least one capacity")
+	}
+	for _, name := range splitList(*arrivals) {
+		i := slices.IndexFunc(workloads.ArrivalProcesses(), func(p workloads.ArrivalProcess) bool { return p.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -arrivals", "value", name, "want", "steady, poisson or bursts")
+		}
+		s.openLoop.processes = append(s.openLoop.processes, workloads.ArrivalProcesses()[i])
+	}
+	if *arrivalRate < 0 || *arrivalWorkers <= 0 || *arrivalRequests <= 0 {
+		fatal(2, "invalid -arrival-rate, -arrival-workers or -arrival-requests, want positive values")
+	}
+	s.openLoop.rate, s.openLoop.workers, s.openLoop.requests = *arrivalRate, *arrivalWorkers, *arrivalRequests
+	var widths []workloads.StageWidths
+	for _, v := range splitList(*pipelineWidths) {
+		w, err := workloads.ParseStageWidths(v)
+		if err != nil {
+			fatal(2, "invalid -pipeline-widths", "err", err)
+		}
+		widths = append(widths, w)
+	}
+	if len(widths) == 0 {
+		fatal(2, "invalid -pipeline-widths, want at least one width")
+	}
+	if *pipelineBudget != 0 && *pipelineBudget < len(workloads.PipelineStages) {
+		fatal(2, "invalid -pipeline-budget, want 0 or at least one goroutine per stage", "value", *pipelineBudget)
+	}
+	if *pipelineBudget == 0 {
+		*pipelineBudget = max(6, 2*cfg.Procs)
+	}
+	var stageBuffers []int
+	for _, v := range splitList(*pipelineBuffers) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			fatal(2, "invalid -pipeline-buffers, want capacities of 0 or more", "value", v)
+		}
+		stageBuffers = append(stageBuffers, n)
+	}
+	if len(stageBuffers) == 0 {
+		fatal(2, "invalid -pipeline-buffers, want at least one capacity")
+	}
+	var strategies []workloads.AppendStrategy
+	for _, name := range splitList(*appendStrategies) {
+		i := slices.IndexFunc(workloads.AppendStrategies(), func(a workloads.AppendStrategy) bool { return a.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -append-strategies", "value", name, "want", "mutex, indexed, merge or channel")
+		}
+		strategies = append(strategies, workloads.AppendStrategies()[i])
+	}
+	if len(strategies) == 0 {
+		fatal(2, "invalid -append-strategies, want at least one strategy")
+	}
+	var pools []workloads.PoolStrategy
+	for _, name := range splitList(*poolStrategies) {
+		i := slices.IndexFunc(workloads.PoolStrategies(), func(p workloads.PoolStrategy) bool { return p.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -pool-strategies", "value", name, "want", "per-task, pool or limited")
+		}
+		pools = append(pools, workloads.PoolStrategies()[i])
+	}
+	if len(pools) == 0 {
+		fatal(2, "invalid -pool-strategies, want at least one strategy")
+	}
+	if *poolLimit < 0 {
+		fatal(2, "invalid -pool-limit, want 0 or more", "value", *poolLimit)
+	}
+	if *poolLimit == 0 {
+		*poolLimit = max(8, cfg.Procs)
+	}
+	var collectors []workloads.ErrorStrategy
+	for _, name := range splitList(*errorStrategies) {
+		i := slices.IndexFunc(workloads.ErrorStrategies(), func(e workloads.ErrorStrategy) bool { return e.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -error-strategies", "value", name, "want", "errgroup, multierror or channel")
+		}
+		collectors = append(collectors, workloads.ErrorStrategies()[i])
+	}
+	if len(collectors) == 0 {
+		fatal(2, "invalid -error-strategies, want at least one strategy")
+	}
+	var builds []workloads.BuildStrategy
+	for _, name := range splitList(*buildStrategies) {
+		i := slices.IndexFunc(workloads.BuildStrategies(), func(b workloads.BuildStrategy) bool { return b.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -string-strategies", "value", name, "want", "join, locked or channel")
+		}
+		builds = append(builds, workloads.BuildStrategies()[i])
+	}
+	if len(builds) == 0 {
+		fatal(2, "invalid -string-strategies, want at least one strategy")
+	}
+	var spawns []int
+	for _, v := range splitList(*spawnCounts) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			fatal(2, "invalid -spawn-counts", "value", v)
+		}
+		spawns = append(spawns, n)
+	}
+	if len(spawns) == 0 {
+		fatal(2, "invalid -spawn-counts, want at least one count")
+	}
+	var impls []workloads.MapImpl
+	for _, name := range splitList(*mapImpls) {
+		i := slices.IndexFunc(workloads.MapImpls(), func(m workloads.MapImpl) bool { return m.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -map-impls", "value", name, "want", "syncmap, mutex or sharded")
+		}
+		impls = append(impls, workloads.MapImpls()[i])
+	}
+	if len(impls) == 0 {
+		fatal(2, "invalid -map-impls, want at least one implementation")
+	}
+	var patterns []workloads.MapPattern
+	for _, name := range splitList(*mapPatterns) {
+		i := slices.IndexFunc(workloads.MapPatterns(), func(p workloads.MapPattern) bool { return p.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -map-patterns", "value", name, "want", "read-heavy, mixed or write-heavy")
+		}
+		patterns = append(patterns, workloads.MapPatterns()[i])
+	}
+	if len(patterns) == 0 {
+		fatal(2, "invalid -map-patterns, want at least one pattern")
+	}
+	var primitives []workloads.CounterPrimitive
+	for _, name := range splitList(*counterPrimitives) {
+		i := slices.IndexFunc(workloads.CounterPrimitives(), func(p workloads.CounterPrimitive) bool { return p.Name == name })
+		if i < 0 {
+			fatal(2, "invalid -counter-primitives", "value", name, "want", "mutex, rwmutex, atomic or sharded")
+		}
+		primitives = append(primitives, workloads.CounterPrimitives()[i])
+	}
+	if len(primitives) == 0 {
+		fatal(2, "invalid -counter-primitives, want at least one primitive")
+	}
+	var reads []int
+	for _, v := range splitList(*counterReads) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 100 {
+			fatal(2, "invalid -counter-reads, want percentages from 0 to 100", "value", v)
+		}
+		reads = append(reads, n)
+	}
+	if len(reads) == 0 {
+		fatal(2, "invalid -counter-reads, want at least one percentage")
+	}
+
+	// Frequency before any load, for -cooldown-freq to recover to
+	baselineMHz, _ := sysinfo.CurrentCPUMHz()
+
+	// Warm up the system
+	slog.Info("warming up")
+	workloads.WarmUp()
+
+	if *antagonistKind != "" {
+		a, err := runner.StartAntagonist(*antagonistKind, *antagonistCores, *antagonistMemMB)
+		if err != nil {
+			fatal(2, "starting antagonist failed", "err", err)
+		}
+		slog.Info("running suites alongside antagonist", "antagonist", a.String())
+		defer a.Stop()
+	}
+
+	// Test different workload types
+	if *arith {
+		s.runSuite("arith", s.testArithmetic)
+	}
+	runs := []suiteRun{
+		{"cpu", s.testCPUWorkImproved},
+		{"io", s.testIOWorkImproved},
+		{"mixed", s.testMixedWorkload},
+		{"limits", func() { s.testConcurrencyLimits(*p99Budget) }},
+		{"threads", s.testThreadGrowth},
+		{"scalability", s.testScalability},
+		{"hybrid", func() {
+			if len(coreClasses) > 1 {
+				s.testHybridCores(coreClasses)
+			}
+		}},
+		{"classify", s.testClassification},
+		{"recommend", func() { s.recommendGOMAXPROCS(coreClasses) }},
+		{"ownership", s.testMessagePassing},
+		{"footprint", func() { testGoroutineFootprint(counts) }},
+		{"contention", s.testChannelContention},
+		{"sharding", s.testChannelSharding},
+		{"adaptive", s.testAdaptiveLimits},
+		{"broadcast", s.testBroadcast},
+		{"futures", s.testFutures},
+		{"barriers", s.testBarriers},
+		{"accumulate", s.testAccumulation},
+		{"eventloop", s.testEventLoop},
+		{"dag", s.testDAG},
+		{"bfs", s.testBFS},
+		{"stream", s.testStreaming},
+		{"dedup", s.testDedup},
+		{"cancel", s.testCancellation},
+		{"gcpause", func() { s.testGCPauses(gogc) }},
+		{"membw", s.testMemoryBandwidth},
+		{"counters", func() { s.testCounters(primitives, reads) }},
+		{"channels", func() { s.testChannelBuffers(buffers) }},
+		{"appends", func() { s.testAppends(strategies) }},
+		{"maps", func() { s.testMaps(impls, patterns) }},
+		{"spawn", func() { s.testSpawnCost(spawns) }},
+		{"strings", func() { s.testStringBuilding(builds) }},
+		{"errors", func() { s.testErrorCollection(collectors) }},
+		{"pools", func() { s.testPools(pools, *poolLimit) }},
+		{"loadgen", func() { s.testLoadGenerators(*stall) }},
+		{"pipeline", func() { s.testPipeline(widths, stageBuffers, *pipelineBudget) }},
+		{"placement", testPlacement},
+		{"custom", s.testCustom},
+	}
+	runs = slices.DeleteFunc(runs, func(r suiteRun) bool { return !slices.Contains(selected, r.name) })
+
+	// Shuffling removes the bias of a fixed order, e.g. the CPU suite
+	// always warming caches and clocks for the I/O suite
+	if *shuffleSuites {
+		rng := rand.New(rand.NewSource(*shuffleSeed))
+		rng.Shuffle(len(runs), func(i, j int) { runs[i], runs[j] = runs[j], runs[i] })
+		meta.ShuffleSeed = *shuffleSeed
+	}
+	for _, r := range runs {
+		meta.SuiteOrder = append(meta.SuiteOrder, r.name)
+	}
+	if *isolatedChild {
+		// The parent printed the order
+	} else if *shuffleSuites {
+		fmt.Printf("🔀 Suite order: %s (shuffled, seed %d)\n\n", strings.Join(meta.SuiteOrder, ", "), *shuffleSeed)
+	} else {
+		fmt.Printf("📊 Suite order: %s\n\n", strings.Join(meta.SuiteOrder, ", "))
+	}
+
+	// Children inherit the flags that shape their progress output and the
+	// suites' own settings
+	childArgs := append([]string{
+		"-log-format", *logFormat,
+		"-log-level", *logLevel,
+		"-p99-budget", p99Budget.String(),
+		"-confidence", strconv.FormatFloat(*confidence, 'g', -1, 64),
+		"-footprint-counts", *footprintCounts,
+		"-gcpause-gogc", *gcpauseGOGC,
+		"-channel-buffers", *channelBuffers,
+		"-arrivals", *arrivals,
+		"-arrival-rate", strconv.FormatFloat(*arrivalRate, 'g', -1, 64),
+		"-arrival-workers", strconv.Itoa(*arrivalWorkers),
+		"-arrival-requests", strconv.Itoa(*arrivalRequests),
+		"-stall", stall.String(),
+		"-pipeline-widths", *pipelineWidths,
+		"-pipeline-buffers", *pipelineBuffers,
+		"-pipeline-budget", strconv.Itoa(*pipelineBudget),
+		"-append-strategies", *appendStrategies,
+		"-spawn-counts", *spawnCounts,
+		"-string-strategies", *buildStrategies,
+		"-error-strategies", *errorStrategies,
+		"-pool-strategies", *poolStrategies,
+		"-pool-limit", strconv.Itoa(*poolLimit),
+		"-map-impls", *mapImpls,
+		"-map-patterns", *mapPatterns,
+		"-counter-primitives", *counterPrimitives,
+		"-counter-reads", *counterReads,
+		"-max-heap-mb", strconv.Itoa(*maxHeapMB),
+		"-max-goroutines", strconv.Itoa(*maxGoroutines),
+		"-run", *runPattern,
+		"-plugin", *plugins,
+	}, configArgs(cfg)...)
+	for i, r := range runs {
+		if i > 0 && *cooldown > 0 {
+			s.coolDown(r.name, *cooldown, *cooldownFreq, baselineMHz)
+		}
+		if *isolate {
+			s.runSuite(r.name, func() { s.runIsolated(r.name, childArgs) })
+			continue
+		}
+		s.runSuite(r.name, r.run)
+	}
+	if *cBaseline {
+		s.runSuite("c-baseline", s.testCBaseline)
+	}
+	if *roofline {
+		s.runSuite("roofline", testRoofline)
+	}
+
+	if *antagonistKind != "" {
+		s.runSuite("antagonist", func() {
+			s.runAntagonistSensitivity(*antagonistKind, *antagonistCores, *antagonistMemMB)
+		})
+	}
+
+	file := report.NewFile(meta, s.results, s.cooldowns, s.sched, s.failures, s.scaling)
+	for _, o := range s.outputs {
+		if err := o.reporter.Finish(report.Run{File: file, Ceilings: s.ceilings}); err != nil {
+			fatal(1, "writing report failed", "err", err)
+		}
+		if err := o.close(); err != nil {
+			fatal(1, "writing report failed", "err", err)
+		}
+		if o.path != "" && !*isolatedChild {
+			slog.Info("report written", "path", o.path)
+		}
+	}
+	if *bundleOut != "" {
+		dir, err := report.WriteBundle(*bundleOut, file, style)
+		if err != nil {
+			fatal(1, "writing report bundle failed", "err", err)
+		}
+		slog.Info("report bundle written", "path", filepath.Join(dir, "index.html"))
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
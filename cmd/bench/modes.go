@@ -0,0 +1,427 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"compare_process/internal/runner"
+	"compare_process/internal/stats"
+	"compare_process/internal/sysinfo"
+	"compare_process/internal/workloads"
+)
+
+func (s *session) runRaceLesson() {
+	fmt.Println("\n🏁 Race-Detector Lesson (Racy vs Corrected Workloads)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	if workloads.RaceEnabled {
+		fmt.Println("   Race detector: ENABLED - expect WARNING: DATA RACE reports below")
+	} else {
+		fmt.Println("   Race detector: disabled - rebuild with `go run -race ./cmd/bench -race-lesson`")
+		fmt.Println("   to see the detector reports; racy runs below only show lost updates")
+	}
+	fmt.Println()
+
+	numTasks := runtime.NumCPU() * 2
+	expected := numTasks * workloads.CounterOpsPerTask
+
+	// Racy versions first, so the detector output is grouped together
+	slog.Info("running racy counter")
+	racyCount, _ := workloads.RunCounterTasks(runtime.NumCPU(), numTasks, true)
+
+	// The racy map runs with GOMAXPROCS=1: with real parallelism the runtime's
+	// own concurrent-map-write check aborts the process before the detector
+	// gets to explain anything. The race is still reported under -race.
+	slog.Info("running racy map")
+	workloads.RunMapTasks(1, numTasks, true)
+
+	fmt.Printf("\n   Racy counter:   %d (expected %d, lost %d updates)\n\n",
+		racyCount, expected, expected-racyCount)
+
+	// Corrected versions for timing comparison
+	fmt.Println("   Corrected versions (mutex-protected):")
+	fmt.Printf("   Workload | GOMAXPROCS | Time\n")
+	fmt.Printf("   ---------|------------|---------\n")
+	for _, procs := range []int{1, runtime.NumCPU()} {
+		count, duration := workloads.RunCounterTasks(procs, numTasks, false)
+		if count != expected {
+			s.warn(fmt.Sprintf("corrected counter lost updates: %d/%d", count, expected), nil)
+		}
+		fmt.Printf("   %-8s | %-10d | %v\n", "counter", procs, duration)
+	}
+	for _, procs := range []int{1, runtime.NumCPU()} {
+		duration := workloads.RunMapTasks(procs, numTasks, false)
+		fmt.Printf("   %-8s | %-10d | %v\n", "map", procs, duration)
+	}
+	fmt.Println()
+}
+
+func (s *session) runChaosMode(seed int64) {
+	fmt.Println("\n🐒 Chaos Mode (Runtime Perturbation Robustness)")
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("   Seed: %d\n\n", seed)
+
+	iterations := s.cfg.Iterations
+	procs := s.cfg.Procs
+
+	type chaosRow struct {
+		name                  string
+		avgBase, avgChaos     time.Duration
+		baseRate, chaosRate   float64
+		slowdown, chaosSpread float64
+	}
+	var rows []chaosRow
+
+	for _, w := range s.workloads() {
+		var baseTimes, chaosTimes []time.Duration
+		onIteration := s.onIteration("chaos", w.Name(), iterations)
+
+		for i := 0; i < iterations; i++ {
+			runner.Settle()
+			baseTimes = append(baseTimes, w.Run(procs, nil))
+
+			runner.Settle()
+			monkey := runner.StartChaos(seed + int64(i))
+			chaosTimes = append(chaosTimes, w.Run(procs, nil))
+			monkey.Stop()
+			slog.Debug("chaos perturbations", "workload", w.Name(), "n", i+1,
+				"gcs", monkey.GCs, "procs_changes", monkey.ProcChanges, "spin_bursts", monkey.SpinBursts)
+			// Baseline and chaos take the concurrent/parallel slots here
+			onIteration(i, baseTimes[i], chaosTimes[i])
+		}
+
+		avgBase := stats.Average(baseTimes)
+		avgChaos := stats.Average(chaosTimes)
+		rows = append(rows, chaosRow{
+			name:        w.Name(),
+			avgBase:     avgBase,
+			avgChaos:    avgChaos,
+			baseRate:    float64(w.Tasks()) / avgBase.Seconds(),
+			chaosRate:   float64(w.Tasks()) / avgChaos.Seconds(),
+			slowdown:    float64(avgChaos) / float64(avgBase),
+			chaosSpread: stats.StdDev(chaosTimes).Seconds() * 1000,
+		})
+	}
+
+	fmt.Printf("\n   Pattern     | Baseline  | Chaos     | Tasks/s (base→chaos) | Slowdown | ±Chaos\n")
+	fmt.Printf("   ------------|-----------|-----------|----------------------|----------|--------\n")
+	for _, r := range rows {
+		fmt.Printf("   %-11s | %-9v | %-9v | %8.1f → %-9.1f | %.2fx    | %.1fms\n",
+			r.name, r.avgBase.Round(time.Microsecond), r.avgChaos.Round(time.Microsecond),
+			r.baseRate, r.chaosRate, r.slowdown, r.chaosSpread)
+	}
+	fmt.Printf("\n   Note: patterns with the smallest slowdown are most robust to runtime disturbance\n\n")
+}
+
+// runGCGrid re-executes the selected suites in a fresh child process per
+// grid cell, so each one starts with clean heap and GC pacer state. args
+// are passed to every child.
+func runGCGrid(suites string, gogcValues, memLimitValues, args []string) error {
+	fmt.Println("🧪 GOGC × GOMEMLIMIT Grid")
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("   Suites: %s\n\n", suites)
+
+	dir, err := os.MkdirTemp("", "gc-grid-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var cells []runner.GridCell
+	for _, gogc := range gogcValues {
+		for _, limit := range memLimitValues {
+			slog.Info("running grid cell", "gogc", gogc, "gomemlimit", limit)
+			cells = append(cells, runner.RunGridCell(dir, suites, gogc, limit, args...))
+		}
+	}
+
+	fmt.Printf("\n   GOGC | GOMEMLIMIT | Suite Time | Max RSS\n")
+	fmt.Printf("   -----|------------|------------|---------\n")
+
+	var fastest, leanest *runner.GridCell
+	for i := range cells {
+		c := &cells[i]
+		if c.Err != nil {
+			fmt.Printf("   %-4s | %-10s | error: %v\n", c.GOGC, c.MemLimit, c.Err)
+			continue
+		}
+		rss := "n/a"
+		if c.RSSOK {
+			rss = fmt.Sprintf("%.1f MB", float64(c.MaxRSS)/(1<<20))
+		}
+		fmt.Printf("   %-4s | %-10s | %-10v | %s\n", c.GOGC, c.MemLimit, c.Total.Round(time.Microsecond), rss)
+
+		if fastest == nil || c.Total < fastest.Total {
+			fastest = c
+		}
+		if c.RSSOK && (leanest == nil || c.MaxRSS < leanest.MaxRSS) {
+			leanest = c
+		}
+	}
+
+	fmt.Println()
+	if fastest != nil {
+		fmt.Printf("   ⚡ Best throughput: GOGC=%s GOMEMLIMIT=%s (%v)\n",
+			fastest.GOGC, fastest.MemLimit, fastest.Total.Round(time.Microsecond))
+	}
+	if leanest != nil {
+		fmt.Printf("   🪶 Least memory:    GOGC=%s GOMEMLIMIT=%s (%.1f MB)\n",
+			leanest.GOGC, leanest.MemLimit, float64(leanest.MaxRSS)/(1<<20))
+	}
+	fmt.Println()
+	return nil
+}
+
+// runGODEBUGGrid re-executes the selected suites in a fresh child process
+// per GODEBUG setting and once as is, reporting how much each setting
+// moves every workload's times against that baseline. args are passed to
+// every child.
+func runGODEBUGGrid(suites string, settings, args []string) error {
+	fmt.Println("🧪 GODEBUG Scheduler Settings")
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("   Suites: %s\n\n", suites)
+
+	dir, err := os.MkdirTemp("", "godebug-grid-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	slog.Info("running godebug baseline", "godebug", os.Getenv("GODEBUG"))
+	baseline := runner.RunDebugSetting(dir, 0, suites, "", args...)
+	if baseline.Err != nil {
+		return fmt.Errorf("baseline run: %w", baseline.Err)
+	}
+	var runs []runner.DebugRun
+	for i, setting := range settings {
+		slog.Info("running godebug setting", "godebug", setting)
+		runs = append(runs, runner.RunDebugSetting(dir, i+1, suites, setting, args...))
+	}
+
+	for _, r := range runs {
+		fmt.Printf("\n   GODEBUG=%s\n", r.Setting)
+		if r.Err != nil {
+			fmt.Printf("   error: %v\n", r.Err)
+			continue
+		}
+		if len(r.Timings) != len(baseline.Timings) {
+			fmt.Printf("   error: %d results against the baseline's %d\n", len(r.Timings), len(baseline.Timings))
+			continue
+		}
+		fmt.Printf("   %-24s | %-12s | %-12s\n", "Workload", "Concurrent", "Parallel")
+		fmt.Printf("   %s-|-%s-|-%s\n", strings.Repeat("-", 24), strings.Repeat("-", 12), strings.Repeat("-", 12))
+		for i, t := range r.Timings {
+			base := baseline.Timings[i]
+			fmt.Printf("   %-24s | %-12s | %-12s\n", t.Workload,
+				debugDelta(base.Concurrent, t.Concurrent), debugDelta(base.Parallel, t.Parallel))
+		}
+		fmt.Printf("   %-24s | %-12s | %-12s\n", "total", "", debugDelta(baseline.Total(), r.Total()))
+	}
+
+	fmt.Println()
+	fmt.Println("   Deltas are against the same suites run without the setting; positive is slower.")
+	fmt.Println()
+	return nil
+}
+
+// debugDelta renders how much slower got is than base, as a percentage.
+func debugDelta(base, got time.Duration) string {
+	if base <= 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (float64(got)/float64(base)-1)*100)
+}
+
+// amdahlOverlay draws measured as a bar width cells long at scale, with a
+// ◆ where the Amdahl model puts it.
+func amdahlOverlay(measured, model, scale float64, width int) string {
+	cells := []rune(strings.Repeat(" ", width))
+	for i := range max(1, int(measured/scale*float64(width))) {
+		cells[min(i, width-1)] = '█'
+	}
+	cells[min(max(int(model/scale*float64(width))-1, 0), width-1)] = '◆'
+	return strings.TrimRight(string(cells), " ")
+}
+
+// runAntagonistSensitivity measures each basic workload with and without
+// the antagonist running, reporting the slowdown each one suffers.
+func (s *session) runAntagonistSensitivity(kind string, cores, memMB int) {
+	fmt.Println("👿 Noisy-Neighbor Sensitivity")
+	fmt.Println(strings.Repeat("-", 60))
+
+	iterations := 3
+	procs := s.cfg.Procs
+
+	type row struct {
+		name         string
+		quiet, noisy time.Duration
+	}
+	var rows []row
+
+	for _, w := range s.workloads() {
+		var quietTimes, noisyTimes []time.Duration
+
+		for i := 0; i < iterations; i++ {
+			runner.Settle()
+			quietTimes = append(quietTimes, w.Run(procs, nil))
+		}
+
+		a, err := runner.StartAntagonist(kind, cores, memMB)
+		if err != nil {
+			s.warn("skipping noisy-neighbor sensitivity", err)
+			return
+		}
+		for i := 0; i < iterations; i++ {
+			runner.Settle()
+			noisyTimes = append(noisyTimes, w.Run(procs, nil))
+		}
+		a.Stop()
+
+		rows = append(rows, row{w.Name(), stats.Average(quietTimes), stats.Average(noisyTimes)})
+	}
+
+	fmt.Printf("   Antagonist: %s on %d cores\n\n", kind, cores)
+	fmt.Printf("   Pattern     | Quiet     | Noisy     | Slowdown\n")
+	fmt.Printf("   ------------|-----------|-----------|---------\n")
+	for _, r := range rows {
+		fmt.Printf("   %-11s | %-9v | %-9v | %.2fx\n",
+			r.name, r.quiet.Round(time.Microsecond), r.noisy.Round(time.Microsecond),
+			float64(r.noisy)/float64(r.quiet))
+	}
+	fmt.Printf("\n   Note: CPU-bound patterns suffer most from cpu antagonists, memory-heavy ones from mem\n\n")
+}
+
+// runProcsSweep times the CPU workload at every GOMAXPROCS from 1 to
+// NumCPU, not just the two ends, to show where adding Ps stops paying.
+func (s *session) runProcsSweep() {
+	fmt.Println("\n📈 GOMAXPROCS Sweep (CPU Workload)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	w := s.cfg.Sizes.CPU()
+	procs := make([]int, runtime.NumCPU())
+	for i := range procs {
+		procs[i] = i + 1
+	}
+	fmt.Printf("   %d goroutines, median of %d runs per GOMAXPROCS\n\n", w.Tasks(), s.cfg.Iterations)
+
+	slog.Info("sweeping GOMAXPROCS", "up_to", len(procs))
+	points := runner.SpeedupCurve(w, procs, s.cfg.Iterations)
+	serial, fitted := runner.FitAmdahl(points)
+	best, scale := 0.0, 0.0
+	for _, p := range points {
+		best = max(best, p.Speedup)
+		scale = max(scale, p.Speedup, runner.AmdahlSpeedup(serial, p.MaxSpeedup))
+	}
+
+	fmt.Printf("   GOMAXPROCS | Time      | Speedup | Amdahl  | Max     | Curve (█ measured, ◆ Amdahl)\n")
+	fmt.Printf("   -----------|-----------|---------|---------|---------|------------------------------\n")
+	for _, p := range points {
+		model := runner.AmdahlSpeedup(serial, p.MaxSpeedup)
+		fmt.Printf("   %-10d | %-9v | %6.2fx | %6.2fx | %6.2fx | %s\n", p.Procs, p.Elapsed.Round(time.Microsecond),
+			p.Speedup, model, p.MaxSpeedup, amdahlOverlay(p.Speedup, model, scale, 30))
+	}
+
+	if fitted {
+		fmt.Printf("\n   Amdahl fit: %.1f%% serial, %.1f%% parallel", serial*100, (1-serial)*100)
+		if serial > 0 {
+			fmt.Printf("; no number of cores gets past %.1fx", 1/serial)
+		}
+		fmt.Println()
+		fmt.Printf("   Max is the ideal for %d goroutines in waves of GOMAXPROCS, with no serial work;\n", w.Tasks())
+		fmt.Printf("   the gap between it and Amdahl is what the serial fraction costs\n")
+	}
+
+	knee := points[len(points)-1]
+	for _, p := range points {
+		if p.Speedup >= best*kneeThreshold {
+			knee = p
+			break
+		}
+	}
+	fmt.Printf("\n   Scaling flattens at GOMAXPROCS=%d: %.2fx, ≥%.0f%% of the best %.2fx\n",
+		knee.Procs, knee.Speedup, kneeThreshold*100, best)
+	if knee.Procs < len(procs) {
+		fmt.Printf("   Ps beyond %d add under %.0f%% for this workload; memory bandwidth, SMT\n", knee.Procs, (1-kneeThreshold)*100)
+		fmt.Printf("   siblings or slower cores usually explain the flat tail\n")
+	}
+	fmt.Println()
+}
+
+// runExpectations compares every registered workload -run selects and
+// checks the outcomes it declares, so a reader can tell whether this
+// machine shows the textbook behavior. A miss is flagged with whatever in
+// the environment might explain it.
+func (s *session) runExpectations() {
+	fmt.Println("🎓 Expected Outcomes")
+	fmt.Println(strings.Repeat("-", 60))
+
+	s.suite = "expect"
+	held, missed, skipped := 0, 0, 0
+	fmt.Printf("   Workload    | Expectation                  | Speedup | Result\n")
+	fmt.Printf("   ------------|------------------------------|---------|-------------------------\n")
+	for _, reg := range workloads.Registered(s.run) {
+		if len(reg.Expectations) == 0 {
+			continue
+		}
+		r := s.compare(reg.New(s.cfg.Sizes))
+		for _, e := range reg.Expectations {
+			result := "✅ held"
+			switch {
+			case !e.Applies(r.Procs):
+				result = fmt.Sprintf("⏭️  skipped (GOMAXPROCS %d)", r.Procs)
+				skipped++
+			case e.Holds(r.Speedup()):
+				held++
+			default:
+				result = "❌ surprising"
+				missed++
+			}
+			fmt.Printf("   %-11s | %-28s | %6.2fx | %s\n", r.Workload, e, r.Speedup(), result)
+		}
+	}
+	fmt.Printf("\n   %d held, %d surprising, %d skipped\n", held, missed, skipped)
+
+	if missed > 0 {
+		fmt.Println("\n   ⚠️  Surprising environment. Possible causes here:")
+		causes := expectationCauses(s.cfg.Procs)
+		if len(causes) == 0 {
+			causes = []string{"nothing detected; rerun with more -iterations and a -cooldown to rule out noise"}
+		}
+		for _, c := range causes {
+			fmt.Printf("   - %s\n", c)
+		}
+	}
+	fmt.Println()
+}
+
+// expectationCauses lists what about this host can bend speedups away
+// from the expected ones.
+func expectationCauses(procs int) []string {
+	var causes []string
+	if quota, ok := sysinfo.CPUQuota(); ok && quota < float64(procs) {
+		causes = append(causes, fmt.Sprintf("a CPU quota of %.1f cores caps GOMAXPROCS %d", quota, procs))
+	}
+	if procs > runtime.NumCPU() {
+		causes = append(causes, fmt.Sprintf("GOMAXPROCS %d is above the %d CPUs", procs, runtime.NumCPU()))
+	}
+	env := sysinfo.DetectEnvironment()
+	if env.Virtualized {
+		causes = append(causes, "a VM whose vCPUs may share physical cores with other guests")
+	}
+	if env.Container != "" {
+		causes = append(causes, "a "+env.Container+" container sharing the host's cores")
+	}
+	if env.Governor == "powersave" {
+		causes = append(causes, "the powersave CPU governor keeps clocks low")
+	}
+	for _, name := range []string{"GOMAXPROCS", "GODEBUG", "GOGC"} {
+		if value, ok := env.GoEnv[name]; ok {
+			causes = append(causes, name+"="+value+" is set in the environment")
+		}
+	}
+	return causes
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+
+	"compare_process/internal/report"
+)
+
+// numberFlags registers the report number-formatting flags on fs and
+// returns a function that builds the style once fs has been parsed.
+func numberFlags(fs *flag.FlagSet) func() (report.NumberStyle, error) {
+	unit := fs.String("unit", "", "fixed duration unit in reports: s, ms, us or ns (default: per value)")
+	precision := fs.Int("precision", 3, "decimals for fixed-unit durations")
+	locale := fs.String("locale", "", "number separators, e.g. de_DE or auto for $LC_ALL/$LC_NUMERIC/$LANG (default: 1234.5)")
+	return func() (report.NumberStyle, error) {
+		return report.NewNumberStyle(*unit, *precision, *locale)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"strings"
+
+	"compare_process/bench"
+	"compare_process/internal/workloads"
+)
+
+// loadPlugin opens a workload plugin built with -buildmode=plugin. Its
+// init may call bench.RegisterCustom itself; otherwise it exports
+//
+//	func Workload(ctx context.Context, shard int) error
+//
+// with optional Name string and Shards int variables, registered here.
+// The name defaults to the file's base name.
+func loadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("loading plugin: %w", err)
+	}
+	sym, err := p.Lookup("Workload")
+	if err != nil {
+		// Registered from its init, or nothing to run
+		return nil
+	}
+	fn, ok := sym.(func(context.Context, int) error)
+	if !ok {
+		return fmt.Errorf("plugin %s: Workload is %T, want func(context.Context, int) error", path, sym)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if sym, err := p.Lookup("Name"); err == nil {
+		if v, ok := sym.(*string); ok && *v != "" {
+			name = *v
+		}
+	}
+	shards := 0
+	if sym, err := p.Lookup("Shards"); err == nil {
+		if v, ok := sym.(*int); ok {
+			shards = *v
+		}
+	}
+	if _, ok := (workloads.Config{}).Lookup(name); ok {
+		return fmt.Errorf("plugin %s: a workload named %q is already registered", path, name)
+	}
+	bench.RegisterCustom(name, shards, fn)
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"compare_process/internal/report"
+)
+
+// runReportCommand implements `bench report`: it regenerates a report from
+// a saved -json file without re-running anything.
+func runReportCommand(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	from := fs.String("from", "", "result file written by -json")
+	format := fs.String("format", "text", "output format: "+strings.Join(report.FormatNames(), ", "))
+	out := fs.String("o", "", "write the report to this file instead of stdout")
+	numberStyle := numberFlags(fs)
+	fs.Parse(args)
+
+	style, err := numberStyle()
+	if err != nil {
+		return err
+	}
+
+	if *from == "" {
+		return fmt.Errorf("report: -from is required")
+	}
+	file, err := report.ReadJSON(*from)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return report.Render(os.Stdout, file, *format, style)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("creating report: %w", err)
+	}
+	if err := report.Render(f, file, *format, style); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// runAggregateCommand implements `bench aggregate`: it lines up result
+// files from several hosts, normalizing throughput so they compare.
+func runAggregateCommand(args []string) error {
+	fs := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	normalize := fs.String("normalize", "core", "divide throughput by: none, ghz, core or score")
+	numberStyle := numberFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: bench aggregate [-normalize mode] results.json...\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	n, err := report.ParseNormalization(*normalize)
+	if err != nil {
+		return err
+	}
+	style, err := numberStyle()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("aggregate: no result files given")
+	}
+
+	var files []report.File
+	var labels []string
+	for _, path := range fs.Args() {
+		file, err := report.ReadJSON(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		labels = append(labels, report.HostLabel(file, path))
+	}
+	report.PrintAggregate(os.Stdout, files, labels, n, style)
+	return nil
+}
+
+// runCompareCommand implements `bench compare`: it places a saved run
+// within the distribution of every run saved in a directory of baselines.
+func runCompareCommand(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	against := fs.String("against", "", "directory of baseline result files written by -json or -bundle, searched recursively")
+	numberStyle := numberFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: bench compare -against dir results.json\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	style, err := numberStyle()
+	if err != nil {
+		return err
+	}
+	if *against == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("compare: want -against and one result file")
+	}
+	current, err := report.ReadJSON(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	currentPath, _ := filepath.Abs(fs.Arg(0))
+
+	var history []report.File
+	err = filepath.WalkDir(*against, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		if abs, _ := filepath.Abs(path); abs == currentPath {
+			return nil
+		}
+		file, err := report.ReadJSON(path)
+		if err != nil {
+			return err
+		}
+		history = append(history, file)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("compare: reading baselines: %w", err)
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("compare: no result files under %s", *against)
+	}
+	report.PrintHistory(os.Stdout, report.CompareHistory(current, history), len(history), style)
+	return nil
+}
+
+// output is one reporter a run feeds, and the file it writes to, if any.
+type output struct {
+	reporter report.Reporter
+	path     string
+	file     *os.File
+}
+
+// openOutputs builds the reporters of a -format list. Each entry is a
+// reporter name, optionally with =path to write to that file instead of
+// stdout. A json file is replaced after every suite rather than held
+// open.
+func openOutputs(formats []string, style report.NumberStyle) ([]output, error) {
+	var outputs []output
+	for _, f := range formats {
+		name, path, _ := strings.Cut(f, "=")
+		o := output{path: path}
+		if name == "json" && path != "" {
+			o.reporter = report.NewJSONFileReporter(path)
+			outputs = append(outputs, o)
+			continue
+		}
+		w := io.Writer(os.Stdout)
+		if path != "" {
+			file, err := os.Create(path)
+			if err != nil {
+				closeOutputs(outputs)
+				return nil, fmt.Errorf("creating %s output: %w", name, err)
+			}
+			o.file, w = file, file
+		}
+		r, err := report.NewReporter(name, w, style)
+		if err != nil {
+			o.close()
+			closeOutputs(outputs)
+			return nil, err
+		}
+		o.reporter = r
+		outputs = append(outputs, o)
+	}
+	return outputs, nil
+}
+
+func (o output) close() error {
+	if o.file == nil {
+		return nil
+	}
+	return o.file.Close()
+}
+
+func closeOutputs(outputs []output) {
+	for _, o := range outputs {
+		o.close()
+	}
+}
@@ -0,0 +1,1949 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"compare_process/bench"
+	"compare_process/internal/events"
+	"compare_process/internal/report"
+	"compare_process/internal/runner"
+	"compare_process/internal/stats"
+	"compare_process/internal/sysinfo"
+	"compare_process/internal/workloads"
+)
+
+// session carries what the suites of one run share: the event bus and its
+// export-only part, the recorded results for the summary and export, the
+// arithmetic ceilings when -arith ran, the cooldowns taken between suites,
+// each suite's scheduler latency, the host's clock overhead, the
+// benchmark sizing the flags chose with the library runner built from it,
+// the suite running now, which the runner's iterations are published
+// under, the confidence level speedups are tested at, the resource caps
+// each suite runs under, the suites a panic or a cap stopped, the
+// scalability suite's matrix, the reporters the run feeds with how much
+// of all that they have been handed, and the -run pattern picking the
+// registered workloads the analysis suites compare.
+type session struct {
+	cfg        bench.Config
+	bench      *bench.Runner
+	suite      string
+	confidence float64
+	caps       runner.Caps
+	bus        *events.Bus
+	exports    *events.Bus
+	results    []runner.Result
+	ceilings   *workloads.Ceilings
+	cooldowns  []runner.CooldownEvent
+	sched      []runner.SuiteSched
+	clock      sysinfo.Clock
+	failures   []report.SuiteFailure
+	scaling    []runner.ScalingCell
+	outputs    []output
+	reported   struct{ results, cooldowns, sched, failures, scaling int }
+	run        *regexp.Regexp
+	openLoop   openLoop
+}
+
+// openLoop configures the open-loop arrivals the io and mixed suites run
+// after their closed-loop measurements. No processes turns it off.
+type openLoop struct {
+	processes []workloads.ArrivalProcess
+	rate      float64 // requests per second; 0 offers 80% of capacity
+	workers   int
+	requests  int
+}
+
+// workloads builds the registered workloads -run selects at the run's
+// sizes.
+func (s *session) workloads() []workloads.Workload {
+	return s.cfg.Sizes.Matching(s.run)
+}
+
+func (s *session) testCPUWorkImproved() {
+	fmt.Println("\n📊 CPU-Intensive Tasks (Prime Number Calculation)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	r := s.compare(s.cfg.Sizes.CPU())
+	s.results = append(s.results, r)
+
+	avgConcurrent := r.Center(r.Concurrent)
+	avgParallel := r.Center(r.Parallel)
+
+	fmt.Printf("\n📈 CPU-Intensive Results (%s):\n", runsLabel(r))
+	fmt.Printf("   Concurrent:  %v (±%v)\n", avgConcurrent, r.Spread(r.Concurrent).Round(time.Microsecond))
+	fmt.Printf("   Parallel:    %v (±%v)\n", avgParallel, r.Spread(r.Parallel).Round(time.Microsecond))
+	printSpread(r)
+	s.printOutliers(r)
+	s.printCI(r)
+	fmt.Printf("   Speedup:     %.2fx\n", r.Speedup())
+	s.printSignificance(r)
+	fmt.Printf("   Efficiency:  %.1f%%\n", r.Efficiency())
+	printRates(r)
+	fmt.Printf("   Theoretical Max: %.2fx (%d goroutines on %d cores, if none of the work were serial)\n", r.MaxSpeedup(), r.Tasks, r.Procs)
+	if r.MaxSpeedup() > 1 {
+		serial, _ := runner.FitAmdahl([]runner.CurvePoint{{Speedup: r.Speedup(), MaxSpeedup: r.MaxSpeedup()}})
+		fmt.Printf("   Amdahl:      %.1f%% serial from this one point; -sweep-procs fits the whole curve\n", serial*100)
+	}
+	s.printMicro(r, "primality test")
+	if s.ceilings != nil {
+		ops := s.cfg.Sizes.PrimeOps()
+		fmt.Printf("   vs ALU Ceiling: %.1f%% (1 core), %.1f%% (all cores)\n",
+			ops/avgConcurrent.Seconds()/s.ceilings.IntSingle*100,
+			ops/avgParallel.Seconds()/s.ceilings.IntAll*100)
+	}
+	fmt.Println()
+}
+
+func (s *session) testIOWorkImproved() {
+	if s.cfg.Sizes.Disk {
+		fmt.Println("💾 I/O-Intensive Tasks (Real File I/O)")
+	} else if s.cfg.Sizes.Net {
+		fmt.Println("💾 I/O-Intensive Tasks (Loopback TCP Requests)")
+	} else {
+		fmt.Println("💾 I/O-Intensive Tasks (Simulated Network Operations)")
+	}
+	fmt.Println(strings.Repeat("-", 60))
+	dist, _ := workloads.LatencyDistByName(s.cfg.Sizes.IODist)
+	if dist.Name != "constant" && !s.cfg.Sizes.Disk {
+		waits := s.cfg.Sizes.SampleIOWaits(10_000)
+		fmt.Printf("   Waits:       %s, %s\n", dist.Name, dist.Note)
+		fmt.Printf("                mean %v, sampled p50 %v / p99 %v / max %v (seed %d)\n", s.cfg.Sizes.IOSleep,
+			stats.Percentile(waits, 50).Round(time.Microsecond), stats.Percentile(waits, 99).Round(time.Microsecond),
+			stats.Max(waits).Round(time.Microsecond), s.cfg.Sizes.IOSeed)
+	}
+
+	r := s.compare(s.cfg.Sizes.IO())
+	threads := runner.MonitorThreads(s.cfg.Sizes.IO(), s.cfg.Procs)
+	recordThreads(&r, threads)
+	s.results = append(s.results, r)
+
+	fmt.Printf("\n📈 I/O-Intensive Results (%s):\n", runsLabel(r))
+	fmt.Printf("   Concurrent:  %v (±%v)\n", r.Center(r.Concurrent), r.Spread(r.Concurrent).Round(time.Microsecond))
+	fmt.Printf("   Parallel:    %v (±%v)\n", r.Center(r.Parallel), r.Spread(r.Parallel).Round(time.Microsecond))
+	printSpread(r)
+	s.printOutliers(r)
+	s.printCI(r)
+	fmt.Printf("   Speedup:     %.2fx\n", r.Speedup())
+	s.printSignificance(r)
+	fmt.Printf("   Efficiency:  %.1f%% of %.2fx max (%d goroutines on %d cores)\n", r.Efficiency(), r.MaxSpeedup(), r.Tasks, r.Procs)
+	fmt.Printf("   OS threads:  %s\n", threads)
+	printRates(r)
+	s.printMicro(r, "request")
+	if errors := r.Counters["errors"]; errors > 0 {
+		fmt.Printf("   Errors:      %.1f failed requests per run\n", errors)
+		s.warn("real I/O requests failed", nil)
+	}
+	if s.cfg.Sizes.Disk {
+		// Unlike a sleep, a read or write keeps its thread busy in the
+		// kernel, so how much parallelism helps depends on the device and
+		// the page cache
+		fmt.Printf("   Note: real file I/O runs in syscalls that hold an OS thread each; copies through the page cache are CPU work that parallelism can spread, fsync waits on the device\n\n")
+	} else if s.cfg.Sizes.Net {
+		// Both ends are in this process, so the server's goroutines and
+		// the loopback copies share the cores with the clients
+		fmt.Printf("   Note: waiting clients park in the netpoller and hold no thread, like sleepers; the reads, writes and server share the cores, so parallelism helps only with that overhead\n\n")
+	} else {
+		fmt.Printf("   Note: I/O tasks show minimal improvement with parallelism\n")
+		if dist.Name != "constant" {
+			// Sums of the same mean, but the wave waits for its slowest task
+			fmt.Printf("   Note: a wave ends with its slowest task, so with a wider spread of waits it\n")
+			fmt.Printf("         runs past -io-ops × -io-sleep; heavy tails make one straggler set the time\n")
+		}
+		fmt.Println()
+	}
+
+	ioKind, _, stop := s.requests()
+	defer stop()
+	slog.Info("tracing load curve", "requests", ioKind.Name)
+	points := runner.LoadCurve(ioKind, runner.ProcsSweep(s.requestClients(256, 32)), 8)
+	printLoadCurve(points)
+	s.checkClock("I/O load curve", points)
+	s.runOpenLoop(ioKind)
+}
+
+// requests returns the I/O and mixed requests the session's sizes
+// configure, and how to release them once served, warning of any that
+// failed.
+func (s *session) requests() (ioKind, mixedKind workloads.RequestKind, stop func()) {
+	ioKind, mixedKind, release := s.cfg.Sizes.Requests()
+	if s.cfg.Sizes.Virtual() {
+		fmt.Printf("   Note: requests are timed one by one, so they wait on the real clock, not the virtual one\n\n")
+	}
+	return ioKind, mixedKind, func() {
+		if err := release(); err != nil {
+			s.warn("serving requests failed", err)
+		}
+	}
+}
+
+// requestClients is the most concurrent requests a sweep offers: perCore
+// per core, or the I/O wave's goroutines when -goroutines asks for more,
+// and at least least, since waiting requests fill few cores.
+func (s *session) requestClients(least, perCore int) int {
+	return max(least, perCore*s.cfg.Procs, s.cfg.Sizes.IO().Tasks())
+}
+
+// runsLabel says how r's two timings were summarized, for a results
+// heading.
+func runsLabel(r runner.Result) string {
+	runs := fmt.Sprint(len(r.Concurrent))
+	if len(r.Parallel) != len(r.Concurrent) {
+		// Outlier rejection can leave the modes uneven
+		runs = fmt.Sprintf("%d/%d", len(r.Concurrent), len(r.Parallel))
+	}
+	if r.Robust {
+		return fmt.Sprintf("median ± MAD of %s runs", runs)
+	}
+	return fmt.Sprintf("avg of %s runs", runs)
+}
+
+// printOutliers says how many runs of each mode the outlier rule took out
+// of the numbers above, and how far off they were.
+func (s *session) printOutliers(r runner.Result) {
+	if s.cfg.Outliers == "" || s.cfg.Outliers == bench.NoOutliers {
+		return
+	}
+	line := func(kept, discarded []time.Duration) string {
+		if len(discarded) == 0 {
+			return fmt.Sprintf("0 of %d", len(kept))
+		}
+		median := stats.Median(kept)
+		furthest := slices.MaxFunc(discarded, func(a, b time.Duration) int {
+			return cmp.Compare(max(a-median, median-a), max(b-median, median-b))
+		})
+		return fmt.Sprintf("%d of %d (furthest %v)", len(discarded), len(kept)+len(discarded),
+			furthest.Round(time.Microsecond))
+	}
+	fmt.Printf("   Outliers:    %s concurrent, %s parallel discarded by %s\n",
+		line(r.Concurrent, r.ConcurrentOutliers), line(r.Parallel, r.ParallelOutliers), s.cfg.Outliers)
+}
+
+// printCI shows how tight each mode's mean is once a CI target is set,
+// and whether the iteration cap stopped the runs short of it.
+func (s *session) printCI(r runner.Result) {
+	if s.cfg.CIWidth == 0 {
+		return
+	}
+	c, p := r.RelativeCI(r.Concurrent), r.RelativeCI(r.Parallel)
+	capped := ""
+	if max(c, p) > s.cfg.CIWidth {
+		capped = ", stopped at the -max-iterations cap"
+	}
+	fmt.Printf("   %g%% CI:      ±%.1f%% / ±%.1f%% after %d runs, target ±%g%%%s\n",
+		runner.CILevel*100, c*100, p*100, len(r.Concurrent), s.cfg.CIWidth*100, capped)
+}
+
+// printSignificance says whether r's speedup is more than run-to-run
+// noise at the session's confidence level: by the paired t-test when each
+// iteration ran both modes back to back, else by Welch's t-test.
+func (s *session) printSignificance(r runner.Result) {
+	t, test := stats.WelchTTest(r.Concurrent, r.Parallel), "Welch's"
+	paired := r.Paired && len(r.Concurrent) == len(r.Parallel)
+	if paired {
+		t, test = stats.PairedTTest(r.Concurrent, r.Parallel), "paired"
+	}
+	level := s.confidence * 100
+	switch {
+	case math.IsNaN(t.P):
+		fmt.Printf("   Significant: untested, needs at least 2 runs per mode\n")
+	case t.Significant(s.confidence):
+		fmt.Printf("   Significant: yes at %g%% (%s t=%.2f, df=%.1f, p=%.2g)\n", level, test, t.T, t.DF, t.P)
+	default:
+		fmt.Printf("   Significant: no at %g%% (%s t=%.2f, df=%.1f, p=%.2g); the speedup may be noise\n",
+			level, test, t.T, t.DF, t.P)
+	}
+	if ratio, lo, hi := stats.PairedRatio(r.Concurrent, r.Parallel, s.confidence); paired && !math.IsNaN(lo) {
+		fmt.Printf("   Paired:      %.2fx per iteration (geometric mean, %g%% CI %.2fx-%.2fx)\n", ratio, level, lo, hi)
+	}
+}
+
+// printSpread shows the distribution behind each mode's average, since
+// one slow run moves the mean but not the median.
+func printSpread(r runner.Result) {
+	fmt.Printf("   Spread:      min / median / p90 / p99 / max, CV\n")
+	for _, mode := range []struct {
+		name string
+		runs []time.Duration
+	}{{"Concurrent", r.Concurrent}, {"Parallel", r.Parallel}} {
+		sum := stats.Summarize(mode.runs)
+		fmt.Printf("     %-11s %v / %v / %v / %v / %v, %.1f%%\n", mode.name+":",
+			sum.Min.Round(time.Microsecond), sum.Median.Round(time.Microsecond), sum.P90.Round(time.Microsecond),
+			sum.P99.Round(time.Microsecond), sum.Max.Round(time.Microsecond), sum.CV*100)
+	}
+}
+
+// printRates shows how much work each mode completed per second, and with
+// -benchtime, in each window.
+func printRates(r runner.Result) {
+	for _, rate := range r.Rates() {
+		fmt.Printf("   %-12s %s/s concurrent, %s/s parallel\n", "Throughput:",
+			formatRate(rate.Concurrent, rate.Unit), formatRate(rate.Parallel, rate.Unit))
+	}
+	if r.Window > 0 {
+		fmt.Printf("   %-12s %.0f runs concurrent, %.0f runs parallel\n", fmt.Sprintf("Per %v:", r.Window),
+			float64(r.Window)/float64(r.Center(r.Concurrent)), float64(r.Window)/float64(r.Center(r.Parallel)))
+	}
+}
+
+func formatRate(v float64, unit string) string {
+	switch {
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM %s", v/1e6, unit)
+	case v >= 1e3:
+		return fmt.Sprintf("%.2fk %s", v/1e3, unit)
+	}
+	return fmt.Sprintf("%.1f %s", v, unit)
+}
+
+// printMicro shows the per-operation timings -micro recorded inside r's
+// tasks next to the wall-clock speedup, since the two answer different
+// questions: how fast one op is, and how fast the whole wave finishes.
+func (s *session) printMicro(r runner.Result, op string) {
+	if r.ParallelMicro.Ops == 0 {
+		return
+	}
+	runs := float64(len(r.Parallel))
+	fmt.Printf("   Micro (per %s, %.0f ops/run):\n", op, r.ParallelMicro.Ops/runs)
+	fmt.Printf("     Concurrent:  %v/op\n", r.ConcurrentMicro.PerOp())
+	fmt.Printf("     Parallel:    %v/op\n", r.ParallelMicro.PerOp())
+	fmt.Printf("     Op speedup:  %.2fx (wall-clock %.2fx)\n", r.MicroSpeedup(), r.Speedup())
+	if r.Speedup() > r.MicroSpeedup()*1.2 {
+		fmt.Printf("     Parallelism ran more ops at once; it did not make each one faster\n")
+	}
+	if perOp := r.ParallelMicro.PerOp(); s.clock.Significant(perOp) {
+		fmt.Printf("     Clock reads: %.0f%% of each op, which they also slowed down\n",
+			float64(s.clock.Overhead())/float64(perOp)*100)
+	}
+}
+
+// checkClock warns when timing a request costs more than 1% of the
+// fastest one recorded, since those latencies are then partly clock reads.
+func (s *session) checkClock(what string, points []runner.LoadPoint) {
+	for _, p := range points {
+		if s.clock.Significant(p.Min) {
+			s.warn(fmt.Sprintf("clock overhead %v is over 1%% of the fastest %s latency %v",
+				s.clock.Overhead(), what, p.Min), nil)
+			return
+		}
+	}
+}
+
+// printLoadCurve shows throughput against latency as offered load grows,
+// the view capacity planning works from, and marks the knee.
+func printLoadCurve(points []runner.LoadPoint) {
+	peak := 0.0
+	for _, p := range points {
+		peak = max(peak, p.Throughput)
+	}
+
+	fmt.Printf("   Throughput vs latency as offered load grows:\n")
+	fmt.Printf("   Clients | Req/s    | p50       | p99       | Throughput\n")
+	fmt.Printf("   --------|----------|-----------|-----------|------------------------------\n")
+	for _, p := range points {
+		fmt.Printf("   %-7d | %8.0f | %-9v | %-9v | %s\n", p.Concurrency, p.Throughput,
+			p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond),
+			strings.Repeat("█", max(1, int(p.Throughput/peak*30))))
+	}
+
+	knee := runner.LoadKnee(points)
+	fmt.Printf("\n   Knee: %d clients at %.0f req/s, p99 %v\n", knee.Concurrency, knee.Throughput,
+		knee.P99.Round(time.Microsecond))
+	fmt.Printf("   Past the knee, extra load mostly buys latency; plan capacity below it\n\n")
+}
+
+// runOpenLoop offers kind's requests to a fixed pool of workers as they
+// arrive under each -arrivals process, all at the same mean rate, and
+// shows how long they queued. It does nothing without -arrivals.
+func (s *session) runOpenLoop(kind workloads.RequestKind) {
+	o := s.openLoop
+	if len(o.processes) == 0 {
+		return
+	}
+	fmt.Printf("   Open-loop arrivals (%s requests):\n", kind.Name)
+	rate := s.offeredRate(kind)
+	fmt.Printf("   %d requests at %.0f req/s to %d workers, GOMAXPROCS=%d\n\n", o.requests, rate, o.workers, s.cfg.Procs)
+
+	slog.Info("offering open-loop load", "requests", kind.Name, "rate", rate)
+	points := runner.OpenLoopSweep(kind, o.processes, rate, o.requests, o.workers, s.cfg.Procs)
+	fmt.Printf("   Arrivals | Req/s    | Queue p50 | Queue p99 | p50       | p99       | Uncorrected p50 | Uncorrected p99\n")
+	fmt.Printf("   ---------|----------|-----------|-----------|-----------|-----------|-----------------|----------------\n")
+	for _, p := range points {
+		fmt.Printf("   %-8s | %8.0f | %-9v | %-9v | %-9v | %-9v | %-15v | %v\n", p.Process, p.Throughput,
+			p.QueueP50.Round(time.Microsecond), p.QueueP99.Round(time.Microsecond),
+			p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond),
+			p.UncorrectedP50.Round(time.Microsecond), p.UncorrectedP99.Round(time.Microsecond))
+	}
+	fmt.Printf("\n   Latency is timed from each request's scheduled arrival; uncorrected\n")
+	fmt.Printf("   from when the generator sent it. They differ when the generator falls\n")
+	fmt.Printf("   behind its schedule, delay an uncorrected tool would never see.\n")
+
+	fmt.Println()
+	for _, p := range o.processes {
+		fmt.Printf("   %-8s %s\n", p.Name, p.Note)
+	}
+	fmt.Printf("\n   Requests arrive on schedule whether or not the server keeps up, so\n")
+	fmt.Printf("   a busy server builds a queue instead of slowing its clients the way\n")
+	fmt.Printf("   the closed-loop waves do. At the same mean rate, clumpier arrivals\n")
+	fmt.Printf("   find every worker busy more often: queueing, not service, is what\n")
+	fmt.Printf("   grows the tail.\n\n")
+}
+
+// offeredRate is -arrival-rate, or without it 80% of the capacity of
+// -arrival-workers serving kind, which it measures and prints.
+func (s *session) offeredRate(kind workloads.RequestKind) float64 {
+	if s.openLoop.rate > 0 {
+		return s.openLoop.rate
+	}
+	// As many closed-loop clients as workers find the server's capacity,
+	// CPU contention included
+	capacity := runner.LoadCurve(kind, []int{s.openLoop.workers}, 10)[0].Throughput
+	fmt.Printf("   Capacity %.0f req/s with %d closed-loop clients, offering 80%% of it\n", capacity, s.openLoop.workers)
+	return 0.8 * capacity
+}
+
+// testLoadGenerators drives the same server closed-loop and open-loop
+// through one stall and shows how differently the generators see it.
+func (s *session) testLoadGenerators(stall time.Duration) {
+	fmt.Println("🎯 Closed-Loop vs Open-Loop Load Generation")
+	fmt.Println(strings.Repeat("-", 60))
+
+	kind, _, stop := s.requests()
+	defer stop()
+	o := s.openLoop
+	rate := s.offeredRate(kind)
+	fmt.Printf("   %d %s requests at %.0f req/s to %d workers, GOMAXPROCS=%d, the server\n",
+		o.requests, kind.Name, rate, o.workers, s.cfg.Procs)
+	fmt.Printf("   frozen for %v halfway through each run\n\n", stall)
+
+	slog.Info("comparing load generators", "rate", rate, "stall", stall)
+	points := runner.CompareGenerators(kind, rate, o.requests, o.workers, s.cfg.Procs, stall)
+	fmt.Printf("   Generator, latency timed    | Req/s    | p50       | p99       | p99.9     | Max\n")
+	fmt.Printf("   ----------------------------|----------|-----------|-----------|-----------|----------\n")
+	for _, p := range points {
+		fmt.Printf("   %-27s | %8.0f | %-9v | %-9v | %-9v | %v\n", p.Generator, p.Throughput,
+			p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond),
+			p.P999.Round(time.Microsecond), p.Max.Round(time.Microsecond))
+	}
+
+	raw, open := points[0], points[len(points)-1]
+	if raw.P99 > 0 {
+		fmt.Printf("\n   The open loop's p99 is %.1fx what the closed-loop clients measured.\n", float64(open.P99)/float64(raw.P99))
+	}
+	fmt.Printf("\n   A closed-loop client waits for each reply before sending the next, so\n")
+	fmt.Printf("   during the stall each of the %d clients records one slow request and\n", o.workers)
+	fmt.Printf("   then sends nothing. Every request real users would have sent in that\n")
+	fmt.Printf("   window goes unmeasured: the generator coordinates with the server and\n")
+	fmt.Printf("   omits exactly the bad samples, so its percentiles look healthy. Timing\n")
+	fmt.Printf("   from when each request was meant to be sent puts them back, which is\n")
+	fmt.Printf("   what an open-loop generator measures by construction. HdrHistogram's\n")
+	fmt.Printf("   correction estimates the same from the send-timed samples alone: each\n")
+	fmt.Printf("   sample slower than the pace adds the ones the client held back.\n\n")
+}
+
+func (s *session) testMixedWorkload() {
+	fmt.Println("🔀 Mixed Workload (CPU + I/O)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	r := runner.RunOnce(s.cfg.Sizes.Mixed(), s.cfg.Procs)
+	threads := runner.MonitorThreads(s.cfg.Sizes.Mixed(), s.cfg.Procs)
+	recordThreads(&r, threads)
+	s.results = append(s.results, r)
+	s.onIteration("mixed", r.Workload, 1)(0, r.Concurrent[0], r.Parallel[0])
+
+	fmt.Printf("   Concurrent:  %v\n", r.Concurrent[0])
+	fmt.Printf("   Parallel:    %v\n", r.Parallel[0])
+	fmt.Printf("   Speedup:     %.2fx\n", r.Speedup())
+	fmt.Printf("   Efficiency:  %.1f%% of %.2fx max (%d goroutines on %d cores)\n", r.Efficiency(), r.MaxSpeedup(), r.Tasks, r.Procs)
+	fmt.Printf("   OS threads:  %s\n", threads)
+	printRates(r)
+	s.printMicro(r, "prime test or request, mixed")
+	fmt.Printf("   Note: Mixed workloads show moderate improvement\n\n")
+	if len(s.openLoop.processes) > 0 {
+		_, mixedKind, stop := s.requests()
+		defer stop()
+		s.runOpenLoop(mixedKind)
+	}
+}
+
+func (s *session) testConcurrencyLimits(budget time.Duration) {
+	fmt.Println("🎚️ Concurrency Limits (Throughput vs p99 Latency)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	// Enough requests that the widest limit still fills up twice
+	top := s.requestClients(64, 8)
+	requests := max(128, 2*top)
+	limits := runner.ProcsSweep(top)
+	fmt.Printf("   %d requests per limit, p99 budget %v\n", requests, budget)
+
+	ioKind, mixedKind, stop := s.requests()
+	defer stop()
+	for _, kind := range []workloads.RequestKind{ioKind, mixedKind} {
+		slog.Info("sweeping concurrency limits", "requests", kind.Name)
+		points := runner.LimitSweep(kind, limits, requests)
+
+		fmt.Printf("\n   %s requests:\n", kind.Name)
+		fmt.Printf("   Limit | Req/s    | p50       | p99       | Budget\n")
+		fmt.Printf("   ------|----------|-----------|-----------|-------\n")
+		for _, p := range points {
+			fits := "✓"
+			if p.P99 > budget {
+				fits = "✗"
+			}
+			fmt.Printf("   %-5d | %8.0f | %-9v | %-9v | %s\n",
+				p.Concurrency, p.Throughput, p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond), fits)
+		}
+
+		if best, ok := runner.RecommendLimit(points, budget); ok {
+			fmt.Printf("   💡 Limit %s to %d in flight: %.0f req/s at p99 %v\n",
+				kind.Name, best.Concurrency, best.Throughput, best.P99.Round(time.Microsecond))
+		} else {
+			fmt.Printf("   ⚠️  No limit keeps %s p99 within %v; raise -p99-budget or add capacity\n",
+				kind.Name, budget)
+		}
+		s.checkClock(kind.Name+" limit sweep", points)
+	}
+	fmt.Printf("\n   The recommendation is the smallest limit within 5%% of the best in-budget\n")
+	fmt.Printf("   throughput: beyond it, extra concurrency only adds queueing and memory.\n\n")
+}
+
+// recordThreads stores the peak OS thread count of a parallel run as a
+// workload gauge, so it appears in every report format.
+func recordThreads(r *runner.Result, t *runner.ThreadMonitor) {
+	if t.OK && r.Gauges != nil {
+		r.Gauges["threads"] = float64(t.Peak)
+	}
+}
+
+func (s *session) testThreadGrowth() {
+	fmt.Println("🧵 OS Thread Growth (Blocking Patterns)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	numTasks := s.cfg.Procs * 4
+	fmt.Printf("   %d goroutines, GOMAXPROCS=%d, 10 waits of 2ms each\n\n", numTasks, s.cfg.Procs)
+	fmt.Printf("   Pattern      | Time      | Threads         | Created | How it waits\n")
+	fmt.Printf("   -------------|-----------|-----------------|---------|------------------------------\n")
+
+	for _, p := range workloads.BlockingPatterns() {
+		runner.Settle()
+		threads := runner.StartThreadMonitor(time.Millisecond)
+		duration := workloads.RunBlockingTasks(p, s.cfg.Procs, numTasks, 10, 2*time.Millisecond)
+		threads.Stop()
+
+		live := "n/a"
+		if threads.OK {
+			live = fmt.Sprintf("%d → %d", threads.Start, threads.Peak)
+		}
+		fmt.Printf("   %-12s | %-9v | %-15s | %-7d | %s\n",
+			p.Name, duration.Round(time.Microsecond), live, threads.Created, p.Note)
+	}
+
+	fmt.Printf("\n   Goroutines waiting on timers, channels or the netpoller cost no thread.\n")
+	fmt.Printf("   Blocking syscalls and cgo calls pin one thread each, so the runtime adds\n")
+	fmt.Printf("   threads beyond GOMAXPROCS; each carries its own stack and scheduling cost.\n")
+	fmt.Printf("   Idle threads are kept for reuse, so later patterns may create fewer.\n\n")
+}
+
+func (s *session) testMessagePassing() {
+	fmt.Println("📨 Message Passing (Ownership Transfer vs Copy)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	pairs := s.cfg.Procs
+	messages := 2000
+	total := pairs * messages
+	fmt.Printf("   %d producer/consumer pairs, %d messages of %d KiB each, GOMAXPROCS=%d\n\n",
+		pairs, messages, workloads.MessageSize>>10, s.cfg.Procs)
+	fmt.Printf("   Mode       | Time      | Msgs/s     | Alloc/msg | GCs  | GC pause  | Corrupted\n")
+	fmt.Printf("   -----------|-----------|------------|-----------|------|-----------|----------\n")
+
+	for _, mode := range workloads.MessageModes() {
+		runner.Settle()
+		var duration time.Duration
+		var corrupted int
+		gc := runner.MeasureGC(func() {
+			duration, corrupted = workloads.RunMessagePassing(mode, s.cfg.Procs, pairs, messages)
+		})
+		fmt.Printf("   %-10s | %-9v | %10.0f | %7.1f K | %-4d | %-9v | %d\n",
+			mode.Name, duration.Round(time.Microsecond), float64(total)/duration.Seconds(),
+			float64(gc.Bytes)/float64(total)/1024, gc.Cycles, gc.Pauses.Sum().Round(time.Microsecond), corrupted)
+	}
+
+	fmt.Printf("\n   Passing a pointer moves ownership without copying, but only while the\n")
+	fmt.Printf("   sender never touches the buffer again; aliased shows what happens when it\n")
+	fmt.Printf("   does. Copies are safe by construction and pay for it in bandwidth and,\n")
+	fmt.Printf("   when they land on the heap, GC cycles; pooling recycles owned buffers.\n\n")
+}
+
+func testGoroutineFootprint(counts []int) {
+	fmt.Println("🪶 Goroutine Memory Footprint")
+	fmt.Println(strings.Repeat("-", 60))
+
+	fmt.Printf("   Goroutines | Mode   | Spawn      | Stack/g    | Heap/g     | Total/g    | Total\n")
+	fmt.Printf("   -----------|--------|------------|------------|------------|------------|----------\n")
+
+	var perGoroutine float64
+	for _, n := range counts {
+		for _, active := range []bool{false, true} {
+			slog.Info("parking goroutines", "count", n, "active", active)
+			f := runner.MeasureFootprint(n, active)
+			mode := "idle"
+			if active {
+				mode = "active"
+			} else {
+				perGoroutine = f.PerGoroutine()
+			}
+			fmt.Printf("   %-10d | %-6s | %-10v | %6.2f KiB | %6.2f KiB | %6.2f KiB | %.1f MiB\n",
+				n, mode, f.Spawn.Round(time.Microsecond),
+				float64(f.Stack)/float64(n)/1024, float64(f.Heap)/float64(n)/1024,
+				f.PerGoroutine()/1024, float64(f.Stack+f.Heap)/(1<<20))
+		}
+	}
+
+	fmt.Printf("\n   Idle goroutines park on a channel; active ones park ~4 KiB deep in a call\n")
+	fmt.Printf("   chain, so their stacks have grown. Stacks start at 2 KiB and double.\n")
+	if perGoroutine > 0 {
+		fmt.Printf("   At %.2f KiB each, 1 GiB holds about %.0f idle goroutines\n",
+			perGoroutine/1024, (1<<30)/perGoroutine)
+	}
+	fmt.Println()
+}
+
+func (s *session) testChannelContention() {
+	fmt.Println("🚦 Channel Contention (Senders:Receivers on One Channel)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	// The sweep runs at whatever GOMAXPROCS the process has
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(s.cfg.Procs))
+	messages := 200000
+	counts := runner.ProcsSweep(max(16, 4*s.cfg.Procs))
+	fmt.Printf("   %d messages through one channel (buffer %d), GOMAXPROCS=%d\n",
+		messages, workloads.ContentionBuffer, s.cfg.Procs)
+
+	series := []struct {
+		name  string
+		shape func(n int) workloads.ChannelShape
+	}{
+		{"N:1 (fan-in)", func(n int) workloads.ChannelShape { return workloads.ChannelShape{Senders: n, Receivers: 1} }},
+		{"1:N (fan-out)", func(n int) workloads.ChannelShape { return workloads.ChannelShape{Senders: 1, Receivers: n} }},
+		{"N:N", func(n int) workloads.ChannelShape { return workloads.ChannelShape{Senders: n, Receivers: n} }},
+	}
+	for _, sr := range series {
+		shapes := make([]workloads.ChannelShape, 0, len(counts))
+		for _, n := range counts {
+			shapes = append(shapes, sr.shape(n))
+		}
+		slog.Info("sweeping channel contention", "series", sr.name)
+		points := runner.ContentionSweep(shapes, messages)
+
+		peak := 0.0
+		for _, p := range points {
+			peak = max(peak, p.Throughput)
+		}
+		fmt.Printf("\n   %s:\n", sr.name)
+		fmt.Printf("   Shape     | Msgs/s     | Per msg   | Throughput\n")
+		fmt.Printf("   ----------|------------|-----------|------------------------------\n")
+		for _, p := range points {
+			fmt.Printf("   %-9s | %10.0f | %-9v | %s\n", fmt.Sprintf("%d:%d", p.Senders, p.Receivers),
+				p.Throughput, p.PerMessage, strings.Repeat("█", max(1, int(p.Throughput/peak*30))))
+		}
+		if c, ok := runner.ContentionCollapse(points); ok {
+			fmt.Printf("   Collapse: %d:%d at %.0f msgs/s, under half the peak\n", c.Senders, c.Receivers, c.Throughput)
+		} else {
+			fmt.Printf("   No collapse: throughput stays above half the peak\n")
+		}
+	}
+
+	fmt.Printf("\n   Every send and receive takes the channel's lock, so goroutines added on\n")
+	fmt.Printf("   either side queue on it instead of adding throughput. Once a shape\n")
+	fmt.Printf("   collapses, shard: give each group of senders its own channel and merge.\n\n")
+}
+
+func (s *session) testBarriers() {
+	fmt.Println("🚧 Barriers (Per-Phase Synchronization Cost)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	phases := 2000
+	workers := runner.ProcsSweep(max(16, 4*s.cfg.Procs))
+	modes := workloads.BarrierModes()
+	fmt.Printf("   %d phases of a few µs per worker, GOMAXPROCS=%d\n", phases, s.cfg.Procs)
+	fmt.Printf("   Synchronization cost per phase, after subtracting the work itself:\n\n")
+
+	costs := make([][]runner.BarrierCost, len(modes))
+	for i, mode := range modes {
+		slog.Info("timing barrier", "mode", mode.Name)
+		costs[i] = runner.BarrierSweep(mode, workers, phases, s.cfg.Procs)
+	}
+
+	header := "   Workers"
+	for _, mode := range modes {
+		header += fmt.Sprintf(" | %-10s", mode.Name)
+	}
+	fmt.Println(strings.TrimRight(header, " "))
+	fmt.Printf("   --------%s\n", strings.Repeat("|------------", len(modes)))
+	for j, n := range workers {
+		line := fmt.Sprintf("   %-7d", n)
+		for i := range modes {
+			line += fmt.Sprintf(" | %-10v", costs[i][j].PerPhase.Round(100*time.Nanosecond))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	fmt.Println()
+	for _, mode := range modes {
+		fmt.Printf("   %-10s %s\n", mode.Name, mode.Note)
+	}
+	fmt.Printf("\n   Every phase ends with the slowest worker, so a barrier costs at least\n")
+	fmt.Printf("   one wake-up per worker; spawning fresh goroutines adds their start-up,\n")
+	fmt.Printf("   and a shared lock or channel serializes the arrivals as workers grow.\n\n")
+}
+
+func (s *session) testAccumulation() {
+	fmt.Println("🧮 Local vs Global Accumulation")
+	fmt.Println(strings.Repeat("-", 60))
+
+	total := 8_000_000
+	workers := runner.ProcsSweep(max(16, 4*s.cfg.Procs))
+	modes := workloads.AccumulateModes()
+	fmt.Printf("   %d adds split across the workers, GOMAXPROCS=%d\n", total, s.cfg.Procs)
+	fmt.Printf("   Time per add:\n\n")
+
+	points := make([][]runner.AccumulatePoint, len(modes))
+	for i, mode := range modes {
+		slog.Info("timing accumulation", "mode", mode.Name)
+		points[i] = runner.AccumulateSweep(mode, workers, total, s.cfg.Procs)
+	}
+
+	header := "   Workers"
+	for _, mode := range modes {
+		header += fmt.Sprintf(" | %-8s", mode.Name)
+	}
+	fmt.Println(strings.TrimRight(header, " "))
+	fmt.Printf("   --------%s\n", strings.Repeat("|----------", len(modes)))
+	for j, n := range workers {
+		line := fmt.Sprintf("   %-7d", n)
+		for i := range modes {
+			line += fmt.Sprintf(" | %-8s", fmt.Sprintf("%.2fns", points[i][j].NsPerAdd))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	fmt.Println()
+	for _, mode := range modes {
+		fmt.Printf("   %-7s %s\n", mode.Name, mode.Note)
+	}
+
+	// modes[0] is the uncontended baseline the others pay a penalty over
+	last := len(workers) - 1
+	local := points[0][last].NsPerAdd
+	fmt.Printf("\n   Contention penalty at %d workers:", workers[last])
+	for i, mode := range modes[1:] {
+		fmt.Printf(" %s %.1fx", mode.Name, points[i+1][last].NsPerAdd/local)
+	}
+	fmt.Printf("\n\n   Shared state turns every add into cache-line traffic between cores; keeping\n")
+	fmt.Printf("   partial sums local and merging once removes it, and is the first fix to try.\n\n")
+}
+
+func (s *session) testFutures() {
+	fmt.Println("🔮 Futures (Collecting Async Results)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	calls := 100000
+	fmt.Printf("   %d async calls of ~200 ops each, GOMAXPROCS=%d\n\n", calls, s.cfg.Procs)
+	fmt.Printf("   Mode            | Time      | ns/call | B/call | GCs  | How results come back\n")
+	fmt.Printf("   ----------------|-----------|---------|--------|------|----------------------------------------\n")
+
+	for _, mode := range workloads.AsyncModes() {
+		runner.Settle()
+		var duration time.Duration
+		gc := runner.MeasureGC(func() {
+			duration = workloads.RunAsyncCalls(mode, s.cfg.Procs, calls)
+		})
+		fmt.Printf("   %-15s | %-9v | %7.0f | %6.0f | %-4d | %s\n",
+			mode.Name, duration.Round(time.Microsecond), float64(duration.Nanoseconds())/float64(calls),
+			float64(gc.Bytes)/float64(calls), gc.Cycles, mode.Note)
+	}
+
+	fmt.Printf("\n   All three start a goroutine per call, which dominates the cost; they\n")
+	fmt.Printf("   differ in what each result needs on top: a channel, a slot in a shared\n")
+	fmt.Printf("   slice, or a Future that any number of goroutines can await.\n\n")
+}
+
+func (s *session) testBroadcast() {
+	fmt.Println("📡 Pub/Sub Broadcast (Fan-Out to N Subscribers)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	// The sweep runs at whatever GOMAXPROCS the process has
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(s.cfg.Procs))
+	messages := 5000
+	subscribers := []int{1, 4, 16, 64}
+	fmt.Printf("   %d messages from one publisher, GOMAXPROCS=%d\n", messages, s.cfg.Procs)
+
+	for _, mode := range workloads.BroadcastModes() {
+		slog.Info("broadcasting", "mode", mode.Name)
+		points := runner.BroadcastSweep(mode, subscribers, messages)
+
+		fmt.Printf("\n   %s: %s\n", mode.Name, mode.Note)
+		fmt.Printf("   Subs | Deliveries/s | p50       | p99       | Spread p50 | Spread p99\n")
+		fmt.Printf("   -----|--------------|-----------|-----------|------------|-----------\n")
+		for _, p := range points {
+			fmt.Printf("   %-4d | %12.0f | %-9v | %-9v | %-10v | %v\n", p.Subscribers, p.Throughput,
+				p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond),
+				p.SpreadP50.Round(time.Microsecond), p.SpreadP99.Round(time.Microsecond))
+		}
+	}
+
+	fmt.Printf("\n   Latency is publish to receipt; spread is how far apart the first and\n")
+	fmt.Printf("   last subscriber saw the same message. Channels give each subscriber its\n")
+	fmt.Printf("   own queue but cost the publisher one send each; sync.Cond wakes everyone\n")
+	fmt.Printf("   at once but they all take the same lock; polling an atomic snapshot\n")
+	fmt.Printf("   takes no lock at all, but spends CPU whenever there is nothing new.\n")
+	fmt.Printf("   The log-based modes never block the publisher, so subscribers catch up\n")
+	fmt.Printf("   in batches: high throughput, but each batch waits for the slowest.\n\n")
+}
+
+func (s *session) testEventLoop() {
+	fmt.Println("🔁 Goroutine per Connection vs Event Loop")
+	fmt.Println(strings.Repeat("-", 60))
+
+	requests, gap := 20, 20*time.Millisecond
+	conns := []int{100, 1000, 10000}
+	fmt.Printf("   %d requests per connection, ~%v apart, GOMAXPROCS=%d\n", requests, gap, s.cfg.Procs)
+
+	for _, model := range workloads.ServeModels() {
+		slog.Info("serving connections", "model", model.Name)
+		points := runner.ServeSweep(model, conns, requests, gap, s.cfg.Procs)
+
+		fmt.Printf("\n   %s: %s\n", model.Name, model.Note)
+		fmt.Printf("   Conns | Req/s    | p50       | p99\n")
+		fmt.Printf("   ------|----------|-----------|----------\n")
+		for _, p := range points {
+			fmt.Printf("   %-5d | %8.0f | %-9v | %v\n", p.Conns, p.Throughput,
+				p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond))
+		}
+	}
+
+	fmt.Printf("\n   Latency runs from a request's arrival to its handler finishing. A\n")
+	fmt.Printf("   goroutine per connection keeps straight-line code and spreads over every\n")
+	fmt.Printf("   core, but each one needs its own timer and wake-up, and when thousands\n")
+	fmt.Printf("   are ready at once the scheduler runs them in no particular order, which\n")
+	fmt.Printf("   shows in the p99. The event loop serves strictly in arrival order with no\n")
+	fmt.Printf("   wake-ups, but on one core only, so it saturates once arrivals outpace it.\n\n")
+}
+
+func (s *session) testDAG() {
+	fmt.Println("🕸️ Dependency DAG (Build-Graph Scheduling)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	procs := s.cfg.Procs
+	fmt.Printf("   Same node count and work per node, different shapes; %d workers, GOMAXPROCS=%d\n\n", procs, procs)
+	fmt.Printf("   Shape    | Layers | Width | Serial    | Parallel  | Speedup | Bound   | of Bound\n")
+	fmt.Printf("   ---------|--------|-------|-----------|-----------|---------|---------|---------\n")
+
+	for _, shape := range workloads.DAGShapes() {
+		slog.Info("running dag", "shape", shape.Name)
+		r := runner.RunDAGShape(shape, procs, 3)
+		fmt.Printf("   %-8s | %-6d | %-5d | %-9v | %-9v | %6.2fx | %6.2fx | %5.1f%%\n",
+			shape.Name, shape.Layers, shape.Width, r.Serial.Round(time.Microsecond),
+			r.Parallel.Round(time.Microsecond), r.Speedup(), r.Bound, r.Speedup()/r.Bound*100)
+	}
+
+	fmt.Printf("\n   A node can't start before its dependencies finish, so the longest path\n")
+	fmt.Printf("   (one node per layer) runs serially whatever the core count: the bound is\n")
+	fmt.Printf("   nodes over depth, capped at the workers. Depth, not core count, caps a\n")
+	fmt.Printf("   narrow graph, and a chain gets nothing from more cores, the way a long\n")
+	fmt.Printf("   critical path slows a build however wide the machine.\n\n")
+}
+
+func (s *session) testBFS() {
+	fmt.Println("🌐 Parallel BFS (Frontier per Level)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	workers := runner.ProcsSweep(s.cfg.Procs)
+	graphs := []*workloads.Graph{workloads.RandomGraph(1<<20, 8), workloads.GridGraph(512)}
+
+	for _, g := range graphs {
+		slog.Info("traversing graph", "graph", g.Name)
+		run := runner.RunBFS(g, workers, 3)
+
+		fmt.Printf("\n   %s: %d vertices, %d edges, %d levels, serial %v\n", g.Name, g.Vertices(), g.Edges(),
+			run.Levels, run.Serial.Round(time.Microsecond))
+		fmt.Printf("   Workers | Time      | Speedup\n")
+		fmt.Printf("   --------|-----------|--------\n")
+		for _, p := range run.Parallel {
+			if !p.Agrees {
+				s.warn(fmt.Sprintf("%s BFS at %d workers disagrees with the serial traversal", g.Name, p.Workers), nil)
+			}
+			fmt.Printf("   %-7d | %-9v | %.2fx\n", p.Workers, p.Elapsed.Round(time.Microsecond),
+				float64(run.Serial)/float64(p.Elapsed))
+		}
+		fmt.Printf("   Overhead per level (1 worker vs serial): %v\n", run.LevelCost().Round(100*time.Nanosecond))
+	}
+
+	fmt.Printf("\n   The random graph finishes in a few levels of huge frontiers, so workers\n")
+	fmt.Printf("   stay busy and scaling is limited by cache misses on scattered edges. The\n")
+	fmt.Printf("   grid needs a level per step of its diameter with small frontiers, so the\n")
+	fmt.Printf("   fork, join and merge at every level eat into what parallelism saves.\n\n")
+}
+
+func (s *session) testStreaming() {
+	fmt.Println("🌊 Streaming Aggregation (Sliding Windows per Key)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	spec := workloads.DefaultStream()
+	fmt.Printf("   %d events over %d keys, %v windows sliding by %v, one source goroutine\n\n",
+		spec.Events, spec.Keys, spec.Window, spec.Slide)
+	fmt.Printf("   Shards | Events/s   | Speedup | Window p50 | Window p99\n")
+	fmt.Printf("   -------|------------|---------|------------|-----------\n")
+
+	slog.Info("aggregating stream", "events", spec.Events)
+	points := runner.StreamSweep(spec, runner.ProcsSweep(s.cfg.Procs), 3)
+	for _, p := range points {
+		if !p.Agrees {
+			s.warn(fmt.Sprintf("stream aggregation at %d shards disagrees with 1 shard", p.Shards), nil)
+		}
+		fmt.Printf("   %-6d | %10.0f | %6.2fx | %-10v | %v\n", p.Shards, p.EventsPerSec,
+			p.EventsPerSec/points[0].EventsPerSec, p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond))
+	}
+
+	fmt.Printf("\n   Each shard owns a slice of the keys, so aggregation needs no locks and\n")
+	fmt.Printf("   scales with shards until the single source routing every event becomes\n")
+	fmt.Printf("   the bottleneck. A window completes when the slowest shard emits it, so\n")
+	fmt.Printf("   its latency is set by the busiest shard's backlog, not the average one.\n\n")
+}
+
+func (s *session) testDedup() {
+	fmt.Println("🧾 Parallel Checksum and Dedup (Shared Seen-Set)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	blocks, unique := 2_000_000, 1_000_000
+	ids := workloads.DedupStream(blocks, unique)
+	workers := runner.ProcsSweep(max(8, 2*s.cfg.Procs))
+	modes := workloads.DedupModes()
+	fmt.Printf("   %d blocks of 256 bytes drawn from %d distinct ones, GOMAXPROCS=%d\n", blocks, unique, s.cfg.Procs)
+	fmt.Printf("   Blocks read, checksummed and looked up per second:\n\n")
+
+	points := make([][]runner.DedupPoint, len(modes))
+	for i, mode := range modes {
+		slog.Info("deduplicating blocks", "set", mode.Name)
+		points[i] = runner.DedupSweep(mode, workers, ids, s.cfg.Procs)
+	}
+
+	header := "   Workers"
+	for _, mode := range modes {
+		header += fmt.Sprintf(" | %-9s", mode.Name)
+	}
+	fmt.Println(strings.TrimRight(header, " "))
+	fmt.Printf("   --------%s\n", strings.Repeat("|-----------", len(modes)))
+	want := points[0][0].Unique
+	for j, n := range workers {
+		line := fmt.Sprintf("   %-7d", n)
+		for i, mode := range modes {
+			p := points[i][j]
+			if p.Unique != want {
+				s.warn(fmt.Sprintf("%s set at %d workers found %d distinct blocks, not %d", mode.Name, n, p.Unique, want), nil)
+			}
+			line += fmt.Sprintf(" | %-9s", fmt.Sprintf("%.2fM", p.BlocksPerSec/1e6))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	fmt.Println()
+	for _, mode := range modes {
+		fmt.Printf("   %-8s %s\n", mode.Name, mode.Note)
+	}
+	fmt.Printf("   %d distinct blocks found (%.0f%% duplicates)\n", want, 100*(1-float64(want)/float64(blocks)))
+
+	last := len(workers) - 1
+	fmt.Printf("\n   Scaling from 1 to %d workers:", workers[last])
+	for i, mode := range modes {
+		fmt.Printf(" %s %.2fx", mode.Name, points[i][last].BlocksPerSec/points[i][0].BlocksPerSec)
+	}
+	fmt.Printf("\n\n   Checksumming parallelizes perfectly; the seen-set is where workers meet.\n")
+	fmt.Printf("   One mutex serializes every lookup and sharding spreads them over 64\n")
+	fmt.Printf("   locks. sync.Map is tuned for keys written once and read often: the more\n")
+	fmt.Printf("   duplicates, the more lookups it answers without a lock, but every new\n")
+	fmt.Printf("   block costs it an allocation and a trip through its locked dirty map.\n\n")
+}
+
+func (s *session) testCancellation() {
+	fmt.Println("🛑 Cancellation Responsiveness")
+	fmt.Println(strings.Repeat("-", 60))
+
+	warmup := 20 * time.Millisecond
+	fmt.Printf("   Goroutines repeat their workload's task until cancelled %v in; time from\n", warmup)
+	fmt.Printf("   cancel to the last one returning, GOMAXPROCS=%d\n", s.cfg.Procs)
+	fmt.Printf("   Sizes: %s\n\n", s.cfg.Sizes)
+	fmt.Printf("   Workload | Style     | Latency   | Worst     | Score\n")
+	fmt.Printf("   ---------|-----------|-----------|-----------|------\n")
+
+	slog.Info("cancelling workloads")
+	points := runner.RunCancelCases(s.cfg.Sizes.CancelCases(), 5, warmup, s.cfg.Procs)
+	for _, p := range points {
+		fmt.Printf("   %-8s | %-9s | %-9v | %-9v | %5.0f\n", p.Workload, p.Style,
+			p.Latency.Round(time.Microsecond), p.Worst.Round(time.Microsecond), p.Score())
+	}
+
+	fmt.Printf("\n   unchecked  the task runs to its end; only the loop around it sees ctx\n")
+	fmt.Printf("   checked    prime loops test ctx.Err() every 1,024 numbers and I/O waits\n")
+	fmt.Printf("              select on ctx.Done() next to the timer\n")
+	fmt.Printf("   Score: 100 within 100µs, 25 points lost per tenfold slower, 0 at 1s\n")
+	fmt.Printf("\n   A goroutine can't be stopped from outside: it exits only when it looks\n")
+	fmt.Printf("   at its context. A tight loop that never does keeps its core, and anything\n")
+	fmt.Printf("   waiting for it to return, until its work is done, however long that is.\n\n")
+}
+
+func (s *session) testAdaptiveLimits() {
+	fmt.Println("🎛️ Adaptive Concurrency (AIMD vs Fixed Limits)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	// The clients run at whatever GOMAXPROCS the process has
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(s.cfg.Procs))
+	capacity, base := 8, 2*time.Millisecond
+	clients, duration := 64, time.Second
+	target := base * 3 / 2
+	fmt.Printf("   Backend serves %d calls at %v; latency grows with the square of any overload\n", capacity, base)
+	fmt.Printf("   %d clients calling back to back for %v per limiter, GOMAXPROCS=%d\n\n", clients, duration, s.cfg.Procs)
+
+	limiters := []workloads.Limiter{
+		workloads.NewFixedLimiter(capacity / 2),
+		workloads.NewFixedLimiter(capacity),
+		workloads.NewFixedLimiter(capacity * 2),
+		workloads.NewFixedLimiter(clients),
+		workloads.NewAIMDLimiter(1, target),
+	}
+
+	fmt.Printf("   Limiter           | Calls/s  | p50       | p99       | Backend p99 | Limit\n")
+	fmt.Printf("   ------------------|----------|-----------|-----------|-------------|-------\n")
+	var adaptive runner.LimiterRun
+	for _, l := range limiters {
+		slog.Info("driving backend", "limiter", l.String())
+		r := runner.RunLimiter(l, workloads.NewBackend(capacity, base), clients, duration)
+		fmt.Printf("   %-17s | %8.0f | %-9v | %-9v | %-11v | %.1f\n", r.Name, r.Throughput,
+			r.P50.Round(time.Microsecond), r.P99.Round(time.Microsecond),
+			r.ServiceP99.Round(time.Microsecond), r.Settled())
+		if _, ok := l.(*workloads.AIMDLimiter); ok {
+			adaptive = r
+		}
+	}
+
+	fmt.Printf("\n   AIMD limit over %v (%d samples, peak %.1f):\n   %s\n",
+		duration, len(adaptive.Trace), slices.Max(adaptive.Trace), sparkline(adaptive.Trace, 30))
+	if after, ok := adaptive.Converged(); ok {
+		fmt.Printf("   Converged to %.1f within %v and stayed within 20%%\n", adaptive.Settled(), after)
+	} else {
+		fmt.Printf("   Still swinging more than 20%% around %.1f at the end of the run\n", adaptive.Settled())
+	}
+
+	fmt.Printf("\n   A fixed limit is only right for one backend state: too low wastes\n")
+	fmt.Printf("   capacity, too high overloads it and every call pays. AIMD probes upward\n")
+	fmt.Printf("   while latency meets its %v target and backs off 10%% when it doesn't,\n", target)
+	fmt.Printf("   so it finds the knee without being told the capacity.\n\n")
+}
+
+// sparkline draws values as width block characters, each the mean of its
+// share of the values, scaled to the largest.
+func sparkline(values []float64, width int) string {
+	const marks = "▁▂▃▄▅▆▇█"
+	levels := []rune(marks)
+	if len(values) == 0 {
+		return ""
+	}
+
+	width = min(width, len(values))
+	means := make([]float64, width)
+	peak := 0.0
+	for i := range means {
+		chunk := values[i*len(values)/width : (i+1)*len(values)/width]
+		for _, v := range chunk {
+			means[i] += v / float64(len(chunk))
+		}
+		peak = max(peak, means[i])
+	}
+
+	var b strings.Builder
+	for _, m := range means {
+		b.WriteRune(levels[min(len(levels)-1, int(m/peak*float64(len(levels)-1)+0.5))])
+	}
+	return b.String()
+}
+
+func (s *session) testChannelSharding() {
+	fmt.Println("🧩 Sharded Channels vs One Shared Channel")
+	fmt.Println(strings.Repeat("-", 60))
+
+	// The sweep runs at whatever GOMAXPROCS the process has
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(s.cfg.Procs))
+	messages := 200000
+	senders := max(16, 4*s.cfg.Procs)
+	fmt.Printf("   %d senders, %d messages, GOMAXPROCS=%d\n\n", senders, messages, s.cfg.Procs)
+
+	slog.Info("sweeping channel shards", "senders", senders)
+	points := runner.ShardSweep(senders, runner.ProcsSweep(senders), messages)
+
+	fmt.Printf("   Shards | Msgs/s     | vs 1 channel\n")
+	fmt.Printf("   -------|------------|-------------\n")
+	for _, p := range points {
+		fmt.Printf("   %-6d | %10.0f | %.2fx\n", p.Shards, p.Throughput, p.Speedup)
+	}
+
+	if p, ok := runner.ShardPayoff(points); ok {
+		fmt.Printf("\n   💡 Sharding pays off from %d shards (%.2fx)\n", p.Shards, p.Speedup)
+	} else {
+		fmt.Printf("\n   Sharding never beat one channel by 10%% here; keep it simple\n")
+	}
+	fmt.Printf("   Each shard's drainer forwards batches, so the merge channel sees one\n")
+	fmt.Printf("   operation per batch. Sharding helps once senders queue on one lock and\n")
+	fmt.Printf("   there are cores to run the drainers; on few cores the extra stage costs.\n\n")
+}
+
+// testChannelBuffers sweeps channel capacity and producer/consumer pairs
+// at GOMAXPROCS=1 and at -gomaxprocs.
+func (s *session) testChannelBuffers(buffers []int) {
+	fmt.Println("📨 Channel Throughput by Buffer Size")
+	fmt.Println(strings.Repeat("-", 60))
+
+	messages := 200000
+	pairs := slices.Compact([]int{1, s.cfg.Procs, 4 * s.cfg.Procs})
+	fmt.Printf("   %d messages over producer/consumer pairs, one channel per pair, GOMAXPROCS 1 vs %d\n\n", messages, s.cfg.Procs)
+	slog.Info("sweeping channel buffers", "buffers", buffers, "pairs", pairs)
+	points := runner.BufferSweep(buffers, pairs, messages, s.cfg.Procs)
+
+	fmt.Printf("   Buffer | Pairs | Concurrent msgs/s | Parallel msgs/s | Parallel/concurrent\n")
+	fmt.Printf("   -------|-------|-------------------|-----------------|--------------------\n")
+	for _, p := range points {
+		fmt.Printf("   %6d | %5d | %17.0f | %15.0f | %6.2fx\n", p.Buffer, p.Pairs, p.Concurrent, p.Parallel, p.Ratio())
+	}
+
+	fmt.Println()
+	for _, n := range pairs {
+		var best [2]runner.BufferPoint
+		for _, p := range points {
+			if p.Pairs != n {
+				continue
+			}
+			if p.Concurrent > best[0].Concurrent {
+				best[0] = p
+			}
+			if p.Parallel > best[1].Parallel {
+				best[1] = p
+			}
+		}
+		fmt.Printf("   Pairs %d: fastest buffer %d at GOMAXPROCS=1, %d at GOMAXPROCS=%d\n",
+			n, best[0].Buffer, best[1].Buffer, s.cfg.Procs)
+	}
+
+	fmt.Printf("\n   At GOMAXPROCS=1 an unbuffered send hands the value straight to the\n")
+	fmt.Printf("   parked receiver and the scheduler runs it next, so a handoff stays on\n")
+	fmt.Printf("   one P. In parallel the receiver is woken on another P, and the sender\n")
+	fmt.Printf("   blocks until it runs: buffers let the producer run ahead and batch those\n")
+	fmt.Printf("   wakeups, which matters far more once the goroutines run at once.\n\n")
+}
+
+// testPipeline streams lines through a parse → transform → aggregate
+// pipeline at each set of stage widths and buffer size, names each run's
+// bottleneck stage, then tunes the stage widths within budget goroutines.
+func (s *session) testPipeline(widths []workloads.StageWidths, buffers []int, budget int) {
+	fmt.Println("🏭 Pipeline: Parse → Transform → Aggregate")
+	fmt.Println(strings.Repeat("-", 60))
+
+	items := 100_000
+	fmt.Printf("   %d CSV lines; Widths are parse/transform/aggregate goroutines.\n", items)
+	fmt.Printf("   GOMAXPROCS 1 vs %d\n\n", s.cfg.Procs)
+	slog.Info("sweeping pipeline", "widths", widths, "buffers", buffers)
+	points := runner.PipelineSweep(widths, buffers, items, s.cfg.Procs)
+
+	fmt.Printf("   Widths   | Buffer | Concurrent items/s | Parallel items/s | Speedup | Bottleneck (parallel)\n")
+	fmt.Printf("   ---------|--------|--------------------|------------------|---------|----------------------\n")
+	best := points[0]
+	for _, p := range points {
+		stage := p.Parallel.Bottleneck()
+		fmt.Printf("   %-8v | %6d | %18.0f | %16.0f | %6.2fx | %s %.0f%% busy\n", p.Widths, p.Buffer,
+			float64(items)/p.Concurrent.Elapsed.Seconds(), float64(items)/p.Parallel.Elapsed.Seconds(),
+			p.Speedup(), workloads.PipelineStages[stage], p.Parallel.Utilization(stage)*100)
+		if p.Parallel.Elapsed < best.Parallel.Elapsed {
+			best = p
+		}
+	}
+
+	fmt.Printf("\n   Fastest in parallel: widths %v, buffer %d. Stage utilization there:\n", best.Widths, best.Buffer)
+	printStageUtilization(best.Parallel)
+
+	fmt.Printf("\n   Utilization is the share of a stage's goroutines' time spent working\n")
+	fmt.Printf("   rather than waiting on a channel. A pipeline runs at the pace of its\n")
+	fmt.Printf("   busiest stage: widen it until another stage is busier. At GOMAXPROCS=1\n")
+	fmt.Printf("   every stage shares one core, so width only adds handoffs; in parallel\n")
+	fmt.Printf("   the fan-out stages spread over the cores until the single aggregate,\n")
+	fmt.Printf("   or the channels feeding it, becomes the limit. Buffers smooth bursts\n")
+	fmt.Printf("   between stages but can't raise the pace of the slowest one.\n\n")
+
+	buffer := slices.Max(buffers)
+	fmt.Printf("   Tuning %d goroutines across the stages, buffer %d, GOMAXPROCS %d\n", budget, buffer, s.cfg.Procs)
+	slog.Info("tuning pipeline", "budget", budget, "buffer", buffer)
+	t := runner.TunePipeline(budget, buffer, items, s.cfg.Procs)
+	fmt.Printf("   Try | Widths   | Items/s  | Bottleneck\n")
+	fmt.Printf("   ----|----------|----------|-----------\n")
+	for i, r := range t.Trials {
+		stage := r.Bottleneck()
+		marker := ""
+		if r.Widths == t.Best.Widths {
+			marker = " ← best"
+		}
+		fmt.Printf("   %3d | %-8v | %8.0f | %s %.0f%% busy%s\n", i+1, r.Widths,
+			float64(items)/r.Elapsed.Seconds(), workloads.PipelineStages[stage], r.Utilization(stage)*100, marker)
+	}
+
+	fmt.Printf("\n   Found: ")
+	for i, name := range workloads.PipelineStages {
+		if i > 0 {
+			fmt.Printf(", ")
+		}
+		fmt.Printf("%s %d", name, t.Best.Widths[i])
+	}
+	fmt.Printf(" (%d of %d goroutines), %.0f items/s,\n", t.Best.Widths.Total(), budget, float64(items)/t.Best.Elapsed.Seconds())
+	fmt.Printf("   %.2fx the even split %v. Stage utilization there:\n", float64(t.Even.Elapsed)/float64(t.Best.Elapsed), t.Even.Widths)
+	printStageUtilization(t.Best)
+	fmt.Printf("\n   The tuner follows the bottleneck: another goroutine helps only the\n")
+	fmt.Printf("   stage the others are waiting on. Goroutines beyond the cores a stage\n")
+	fmt.Printf("   can use just add handoffs, so the best split may leave budget unspent.\n\n")
+}
+
+// printStageUtilization draws a bar of each pipeline stage's utilization
+// in r.
+func printStageUtilization(r workloads.PipelineResult) {
+	for i, name := range workloads.PipelineStages {
+		u := r.Utilization(i)
+		fmt.Printf("   %-9s %5.1f%% %s\n", name, u*100, strings.Repeat("█", max(1, int(u*30))))
+	}
+}
+
+// testAppends builds one large slice with each append strategy at
+// GOMAXPROCS=1 and at -gomaxprocs.
+func (s *session) testAppends(strategies []workloads.AppendStrategy) {
+	fmt.Println("🧩 Building a Slice in Parallel: Mutex vs Indexed vs Merge vs Channel")
+	fmt.Println(strings.Repeat("-", 60))
+
+	workers, items := max(4, s.cfg.Procs), 1_000_000
+	fmt.Printf("   %d workers building a %d-item slice, GOMAXPROCS 1 vs %d\n\n", workers, items, s.cfg.Procs)
+	fmt.Printf("   Strategy | Concurrent | Parallel   | Speedup | ns/item (parallel)\n")
+	fmt.Printf("   ---------|------------|------------|---------|-------------------\n")
+
+	var best string
+	bestTime := time.Duration(math.MaxInt64)
+	for _, a := range strategies {
+		r := s.compare(workloads.AppendWorkload(a, workers, items))
+		parallel := r.Center(r.Parallel)
+		if parallel < bestTime {
+			best, bestTime = a.Name, parallel
+		}
+		fmt.Printf("   %-8s | %-10v | %-10v | %6.2fx | %.1f\n", a.Name,
+			r.Center(r.Concurrent).Round(time.Microsecond), parallel.Round(time.Microsecond),
+			r.Speedup(), float64(parallel)/float64(items))
+	}
+	fmt.Printf("\n   Fastest in parallel: %s\n\n", best)
+
+	for _, a := range strategies {
+		fmt.Printf("   %-8s %s\n", a.Name, a.Note)
+	}
+	fmt.Printf("\n   Indexed writes need the item count up front but share nothing, so they\n")
+	fmt.Printf("   scale with the cores and keep the items in order. Per-worker slices\n")
+	fmt.Printf("   don't need the count and pay one copy to merge. The mutex serializes\n")
+	fmt.Printf("   every append and grows the slice while holding the lock; the channel\n")
+	fmt.Printf("   funnels every item through one collector, so neither gets faster with\n")
+	fmt.Printf("   more cores, and both interleave the workers' items.\n\n")
+}
+
+// testMaps sweeps GOMAXPROCS for each map implementation under each
+// access pattern, in operations per second, so the implementations'
+// scaling curves sit side by side.
+func (s *session) testMaps(impls []workloads.MapImpl, patterns []workloads.MapPattern) {
+	fmt.Println("🗺️  Shared Maps: sync.Map vs Mutex vs Sharded")
+	fmt.Println(strings.Repeat("-", 60))
+
+	workers, ops := max(4, s.cfg.Procs), 1_000_000
+	procs := runner.ProcsSweep(s.cfg.Procs)
+	fmt.Printf("   %d workers sharing %d operations on %d keys, median of 3 runs per GOMAXPROCS\n",
+		workers, ops, workloads.MapKeys)
+	slog.Info("sweeping shared maps", "impls", len(impls), "patterns", len(patterns), "procs", len(procs))
+
+	for _, pattern := range patterns {
+		fmt.Printf("\n   %s (%d%% reads), Mops/s\n", pattern.Name, pattern.ReadPct)
+		header, rule := "   Map     ", "   --------"
+		for _, p := range procs {
+			header += fmt.Sprintf(" | P=%-5d", p)
+			rule += "-|--------"
+		}
+		fmt.Println(header + " | Speedup")
+		fmt.Println(rule + "-|--------")
+
+		var best string
+		bestRate := 0.0
+		for _, impl := range impls {
+			curve := runner.SpeedupCurve(workloads.MapWorkload(impl, pattern, workers, ops), procs, 3)
+			line := fmt.Sprintf("   %-8s", impl.Name)
+			for _, p := range curve {
+				line += fmt.Sprintf(" | %-7.2f", float64(ops)/p.Elapsed.Seconds()/1e6)
+			}
+			last := curve[len(curve)-1]
+			if rate := float64(ops) / last.Elapsed.Seconds(); rate > bestRate {
+				best, bestRate = impl.Name, rate
+			}
+			fmt.Printf("%s | %6.2fx\n", line, last.Speedup)
+		}
+		fmt.Printf("   Fastest at GOMAXPROCS=%d: %s\n", procs[len(procs)-1], best)
+	}
+
+	fmt.Println()
+	for _, impl := range impls {
+		fmt.Printf("   %-8s %s\n", impl.Name, impl.Note)
+	}
+	fmt.Printf("\n   sync.Map reads stored keys without a lock, so it scales when reads\n")
+	fmt.Printf("   dominate, but it is built for keys written once: every store boxes\n")
+	fmt.Printf("   its value into a fresh allocation. RWMutex readers still write the\n")
+	fmt.Printf("   lock's reader count, one cache line every core fights over. Sharding\n")
+	fmt.Printf("   spreads reads and writes over many locks, which is why it usually\n")
+	fmt.Printf("   wins once writes are more than a few percent of the traffic.\n\n")
+}
+
+// testSpawnCost times batches of goroutines that exit as soon as they
+// start, at GOMAXPROCS=1 and at -gomaxprocs, for the cost of a go
+// statement on its own.
+func (s *session) testSpawnCost(counts []int) {
+	fmt.Println("🐣 Goroutine Creation and Teardown")
+	fmt.Println(strings.Repeat("-", 60))
+
+	procs := slices.Compact([]int{1, s.cfg.Procs})
+	fmt.Printf("   Goroutines that exit at once, median of %d runs; start latency is from\n", s.cfg.Iterations)
+	fmt.Printf("   the go statement to the goroutine's first instruction\n\n")
+	fmt.Printf("   Goroutines | GOMAXPROCS | Total      | Goroutines/s | Per spawn | Start p50  | Start p99\n")
+	fmt.Printf("   -----------|------------|------------|--------------|-----------|------------|-----------\n")
+
+	var last [2]runner.SpawnPoint
+	for _, n := range counts {
+		for i, p := range procs {
+			slog.Info("spawning goroutines", "count", n, "procs", p)
+			pt := runner.MeasureSpawn(n, p, s.cfg.Iterations)
+			last[i] = pt
+			fmt.Printf("   %-10d | %-10d | %-10v | %12.0f | %-9v | %-10v | %v\n", n, p,
+				pt.Elapsed.Round(time.Microsecond), pt.Rate(), pt.PerSpawn(),
+				pt.P50.Round(time.Microsecond), pt.P99.Round(time.Microsecond))
+		}
+	}
+
+	if len(procs) > 1 {
+		fmt.Printf("\n   At %d goroutines, GOMAXPROCS=%d spawns %.2fx as many per second as\n",
+			last[1].Goroutines, procs[1], last[1].Rate()/last[0].Rate())
+		fmt.Printf("   GOMAXPROCS=1.")
+	} else {
+		fmt.Printf("\n  ")
+	}
+	fmt.Printf(" A go statement allocates or reuses a g with a 2 KiB stack and\n")
+	fmt.Printf("   queues it on the spawning P, so the spawning loop stays on one core;\n")
+	fmt.Printf("   other Ps only help by stealing the new goroutines to run and reap\n")
+	fmt.Printf("   them. On one P each new goroutine waits until the spawner yields, so\n")
+	fmt.Printf("   start latency grows with the batch. Per spawn is the cost a\n")
+	fmt.Printf("   goroutine-per-item design pays on every item.\n\n")
+}
+
+// testStringBuilding assembles one large output with each build strategy
+// at GOMAXPROCS=1 and at -gomaxprocs, with what each allocates per run.
+func (s *session) testStringBuilding(strategies []workloads.BuildStrategy) {
+	fmt.Println("🧵 Building a String in Parallel: Join vs Locked Builder vs Channel")
+	fmt.Println(strings.Repeat("-", 60))
+
+	workers, fragments := max(4, s.cfg.Procs), 500_000
+	fmt.Printf("   %d workers formatting %d lines into one string, GOMAXPROCS 1 vs %d\n\n", workers, fragments, s.cfg.Procs)
+	fmt.Printf("   Strategy | Concurrent | Parallel   | Speedup | MB/s (parallel) | MiB/run | Allocs/run\n")
+	fmt.Printf("   ---------|------------|------------|---------|-----------------|---------|-----------\n")
+
+	var best string
+	bestTime := time.Duration(math.MaxInt64)
+	for _, b := range strategies {
+		r := s.compare(workloads.BuildWorkload(b, workers, fragments))
+		parallel := r.Center(r.Parallel)
+		if parallel < bestTime {
+			best, bestTime = b.Name, parallel
+		}
+		// Allocs are per task, and each worker is one task
+		a := r.ParallelAllocs
+		fmt.Printf("   %-8s | %-10v | %-10v | %6.2fx | %15.1f | %7.1f | %.0f\n", b.Name,
+			r.Center(r.Concurrent).Round(time.Microsecond), parallel.Round(time.Microsecond), r.Speedup(),
+			r.Gauges["output_bytes"]/parallel.Seconds()/1e6,
+			a.BytesPerOp()*float64(workers)/(1<<20), a.AllocsPerOp()*float64(workers))
+	}
+	fmt.Printf("\n   Fastest in parallel: %s\n\n", best)
+
+	for _, b := range strategies {
+		fmt.Printf("   %-8s %s\n", b.Name, b.Note)
+	}
+	fmt.Printf("\n   Per-worker builders share nothing while formatting and pay one copy\n")
+	fmt.Printf("   to join, so they scale and allocate in a few large blocks. The\n")
+	fmt.Printf("   locked builder serializes every write, and the channel allocates a\n")
+	fmt.Printf("   string per fragment and funnels them all through one collector, so\n")
+	fmt.Printf("   both stay near one core's throughput however many cores there are.\n\n")
+}
+
+// testErrorCollection runs each error strategy with no failures, one
+// early failure and failures spread through the run, at GOMAXPROCS=1 and
+// at -gomaxprocs, showing its overhead and how much work it does once a
+// task has failed.
+func (s *session) testErrorCollection(strategies []workloads.ErrorStrategy) {
+	fmt.Println("🚨 Collecting Errors: errgroup vs Multierror vs Channel")
+	fmt.Println(strings.Repeat("-", 60))
+
+	workers, tasks := max(4, s.cfg.Procs), 20_000
+	fmt.Printf("   %d workers running %d tasks of a few µs, GOMAXPROCS 1 vs %d\n\n", workers, tasks, s.cfg.Procs)
+	fmt.Printf("   Failures | Strategy   | Concurrent | Parallel   | Speedup | Tasks run | Errors returned\n")
+	fmt.Printf("   ---------|------------|------------|------------|---------|-----------|----------------\n")
+
+	for _, scenario := range workloads.ErrorScenarios() {
+		for _, e := range strategies {
+			r := s.compare(workloads.ErrorWorkload(e, scenario, workers, tasks))
+			fmt.Printf("   %-8s | %-10s | %-10v | %-10v | %6.2fx | %9.0f | %.0f\n", scenario.Name, e.Name,
+				r.Center(r.Concurrent).Round(time.Microsecond), r.Center(r.Parallel).Round(time.Microsecond),
+				r.Speedup(), r.Gauges["tasks_run"], r.Gauges["errors_returned"])
+		}
+	}
+
+	fmt.Println()
+	for _, e := range strategies {
+		fmt.Printf("   %-10s %s\n", e.Name, e.Note)
+	}
+	fmt.Printf("\n   With no failures the three cost about the same: collecting errors is\n")
+	fmt.Printf("   free until there are some. On an early failure errgroup cancels the\n")
+	fmt.Printf("   other tasks and returns almost at once, with one error; the others run\n")
+	fmt.Printf("   every task and return every error. Pick errgroup when one failure\n")
+	fmt.Printf("   dooms the whole job, and a collector when the caller needs them all.\n\n")
+}
+
+// testPools runs each task set one goroutine per task, through a worker
+// pool and through an errgroup-style limit, at GOMAXPROCS=1 and at
+// -gomaxprocs, with the goroutines and memory each needs.
+func (s *session) testPools(strategies []workloads.PoolStrategy, limit int) {
+	fmt.Println("🏊 Worker Pool vs Goroutine per Task")
+	fmt.Println(strings.Repeat("-", 60))
+
+	fmt.Printf("   Pool and limit of %d goroutines, GOMAXPROCS 1 vs %d\n\n", limit, s.cfg.Procs)
+	fmt.Printf("   Tasks          | Strategy | Concurrent | Parallel   | Speedup | Peak goroutines | MiB/run\n")
+	fmt.Printf("   ---------------|----------|------------|------------|---------|-----------------|--------\n")
+
+	var verdicts []string
+	for _, kind := range workloads.PoolTasks() {
+		var best string
+		bestTime := time.Duration(math.MaxInt64)
+		for _, p := range strategies {
+			r := s.compare(workloads.PoolWorkload(p, kind, limit))
+			parallel := r.Center(r.Parallel)
+			if parallel < bestTime {
+				best, bestTime = p.Name, parallel
+			}
+			// Allocs are per task
+			a := r.ParallelAllocs
+			fmt.Printf("   %-7s %6d | %-8s | %-10v | %-10v | %6.2fx | %15.0f | %.2f\n", kind.Name, kind.Tasks, p.Name,
+				r.Center(r.Concurrent).Round(time.Microsecond), parallel.Round(time.Microsecond), r.Speedup(),
+				r.Gauges["peak_goroutines"], a.BytesPerOp()*float64(kind.Tasks)/(1<<20))
+		}
+		verdicts = append(verdicts, fmt.Sprintf("   %s tasks: %s is fastest in parallel", kind.Name, best))
+	}
+
+	fmt.Println()
+	for _, v := range verdicts {
+		fmt.Println(v)
+	}
+	fmt.Println()
+	for _, p := range strategies {
+		fmt.Printf("   %-8s %s\n", p.Name, p.Note)
+	}
+	fmt.Printf("\n   A pool pays for its goroutines once and hands each task over a\n")
+	fmt.Printf("   channel, so it wins when tasks are short and numerous: start-up and\n")
+	fmt.Printf("   stacks are a real share of each one. Goroutine per task holds one g\n")
+	fmt.Printf("   and stack per task at once, which is cheap for a few thousand and\n")
+	fmt.Printf("   costly for millions. For tasks that mostly wait, a pool sized to the\n")
+	fmt.Printf("   cores leaves them idle: the limit, not the CPU, sets the throughput.\n\n")
+}
+
+// testCounters compares each counter primitive at GOMAXPROCS=1 and at
+// -gomaxprocs for every read share, showing which ones lose throughput
+// when the workers really run at once.
+func (s *session) testCounters(primitives []workloads.CounterPrimitive, reads []int) {
+	fmt.Println("🔢 Shared Counter: Mutex vs RWMutex vs Atomic vs Sharded")
+	fmt.Println(strings.Repeat("-", 60))
+
+	workers, ops := max(4, s.cfg.Procs), 2_000_000
+	fmt.Printf("   %d workers sharing %d reads and increments, GOMAXPROCS 1 vs %d\n\n", workers, ops, s.cfg.Procs)
+	fmt.Printf("   Reads | Primitive | Concurrent | Parallel   | Speedup | ns/op (parallel)\n")
+	fmt.Printf("   ------|-----------|------------|------------|---------|-----------------\n")
+
+	for _, pct := range reads {
+		var best string
+		bestTime := time.Duration(math.MaxInt64)
+		for _, p := range primitives {
+			r := s.compare(workloads.CounterWorkload(p, workers, ops, pct))
+			parallel := r.Center(r.Parallel)
+			if parallel < bestTime {
+				best, bestTime = p.Name, parallel
+			}
+			fmt.Printf("   %4d%% | %-9s | %-10v | %-10v | %6.2fx | %.1f\n", pct, p.Name,
+				r.Center(r.Concurrent).Round(time.Microsecond), parallel.Round(time.Microsecond),
+				r.Speedup(), float64(parallel)/float64(ops))
+		}
+		fmt.Printf("   %4d%% | fastest in parallel: %s\n", pct, best)
+	}
+
+	fmt.Println()
+	for _, p := range primitives {
+		fmt.Printf("   %-8s %s\n", p.Name, p.Note)
+	}
+	fmt.Printf("\n   At GOMAXPROCS=1 no two workers touch the counter at once, so every\n")
+	fmt.Printf("   primitive runs uncontended. In parallel the shared cache line bounces\n")
+	fmt.Printf("   between cores on every write: a speedup below 1x is contention.\n")
+	fmt.Printf("   RWMutex only pays off when reads dominate; sharding makes increments\n")
+	fmt.Printf("   free of contention and moves the cost to reads, which visit every shard.\n\n")
+}
+
+// testMemoryBandwidth sweeps STREAM's kernels over GOMAXPROCS next to the
+// prime workload, so the memory-bound loops' early plateau shows against
+// the compute-bound one's climb.
+func (s *session) testMemoryBandwidth() {
+	fmt.Println("🧠 Memory Bandwidth (STREAM Copy, Scale, Add, Triad)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	kernels := s.cfg.Sizes.MemoryKernels()
+	procs := runner.ProcsSweep(s.cfg.Procs)
+	fmt.Printf("   %d MiB arrays split across %d goroutines, median of 3 runs per GOMAXPROCS\n\n",
+		workloads.StreamElems*8>>20, kernels[0].Tasks())
+
+	slog.Info("sweeping STREAM kernels", "kernels", len(kernels), "procs", len(procs))
+	curves := make([][]runner.CurvePoint, len(kernels))
+	for i, k := range kernels {
+		curves[i] = runner.SpeedupCurve(k, procs, 3)
+	}
+	prime := runner.SpeedupCurve(s.cfg.Sizes.CPU(), procs, 3)
+	triad := curves[len(curves)-1]
+
+	header, rule := "   GOMAXPROCS", "   ----------"
+	for _, k := range kernels {
+		header += fmt.Sprintf(" | %-10s", k.Name()+" GB/s")
+		rule += "-|-----------"
+	}
+	fmt.Println(header + " | Triad   | Prime")
+	fmt.Println(rule + "-|---------|--------")
+	for i, p := range procs {
+		line := fmt.Sprintf("   %-10d", p)
+		for j, k := range kernels {
+			line += fmt.Sprintf(" | %-10.2f", k.Bytes/curves[j][i].Elapsed.Seconds()/1e9)
+		}
+		fmt.Printf("%s | %6.2fx | %6.2fx\n", line, triad[i].Speedup, prime[i].Speedup)
+	}
+
+	best := 0.0
+	for _, p := range triad {
+		best = max(best, p.Speedup)
+	}
+	knee := triad[len(triad)-1]
+	for _, p := range triad {
+		if p.Speedup >= best*kneeThreshold {
+			knee = p
+			break
+		}
+	}
+	fmt.Printf("\n   Triad reaches %.0f%% of its best bandwidth at GOMAXPROCS=%d of %d,\n",
+		kneeThreshold*100, knee.Procs, procs[len(procs)-1])
+	fmt.Printf("   where the prime workload is at %.2fx of its %.2fx with every core.\n",
+		prime[slices.Index(procs, knee.Procs)].Speedup, prime[len(prime)-1].Speedup)
+	fmt.Printf("   These loops do one or two flops per 16-24 bytes, so a few cores fill\n")
+	fmt.Printf("   the memory channels and the rest wait on DRAM; extra goroutines add\n")
+	fmt.Printf("   nothing once bandwidth, not compute, is the ceiling.\n\n")
+
+	triadBytes := kernels[len(kernels)-1].Bytes
+	single := triadBytes / triad[0].Elapsed.Seconds()
+	all := triadBytes / triad[len(triad)-1].Elapsed.Seconds()
+	cores := procs[len(procs)-1]
+	fmt.Printf("   Bandwidth ceiling (triad):\n")
+	fmt.Printf("     %-18s %.2f GB/s\n", "Single core:", single/1e9)
+	fmt.Printf("     %-18s %.2f GB/s\n", fmt.Sprintf("All %d cores:", cores), all/1e9)
+	fmt.Printf("     %-18s %.2fx of a possible %dx\n\n", "Ratio:", all/single, cores)
+	fmt.Printf("   One core alone already draws 1/%.1f of what the memory system can\n", all/single)
+	fmt.Printf("   deliver, so any workload that streams more data than fits in cache\n")
+	fmt.Printf("   stops scaling near GOMAXPROCS=%.0f however many cores it is given.\n\n", math.Ceil(all/single))
+}
+
+// testGCPauses runs a 1ms ticker beside an allocating goroutine on every
+// other core at each GOGC setting and shows how late its ticks woke: the
+// stop-the-world phases and mark assists of each GC cycle land on it.
+func (s *session) testGCPauses(gogc []int) {
+	fmt.Println("🗑️  GC Pauses vs a Latency-Sensitive Ticker")
+	fmt.Println(strings.Repeat("-", 60))
+
+	period, duration := time.Millisecond, time.Second
+	fmt.Printf("   A %v ticker for %v beside %d allocating goroutines (1-64 KiB slices), GOMAXPROCS=%d\n\n",
+		period, duration, max(1, s.cfg.Procs-1), s.cfg.Procs)
+
+	header := "   GOGC  | GCs  | Alloc MiB/s | p50      | p99      | Max      | Missed"
+	rule := "   ------|------|-------------|----------|----------|----------|--------"
+	lower := time.Duration(0)
+	for _, bound := range runner.LatenessBuckets {
+		header += fmt.Sprintf(" | %-9s", lower.String()+"-"+bound.String())
+		rule += "|-----------"
+		lower = bound
+	}
+	header += fmt.Sprintf(" | ≥%v", lower)
+	rule += "|------"
+	fmt.Println(header)
+	fmt.Println(rule)
+
+	slog.Info("sweeping GOGC under a ticker", "settings", len(gogc))
+	for _, p := range runner.GCPauseSweep(gogc, period, duration, s.cfg.Procs) {
+		line := fmt.Sprintf("   %-5d | %-4d | %-11.0f | %-8v | %-8v | %-8v | %-6d",
+			p.GOGC, p.GCs, p.AllocRate/(1<<20), p.P50.Round(time.Microsecond), p.P99.Round(time.Microsecond),
+			p.Max.Round(time.Microsecond), p.Missed)
+		for i, n := range p.Buckets {
+			width := 9
+			if i == len(p.Buckets)-1 {
+				width = 0
+			}
+			line += fmt.Sprintf(" | %-*s", width, fmt.Sprintf("%.1f%%", float64(n)/float64(max(p.Ticks, 1))*100))
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\n   Missed deadlines passed while the ticker was still waiting to wake, so a\n")
+	fmt.Printf("   fixed-rate consumer would have dropped them; the buckets split the ticks\n")
+	fmt.Printf("   it did serve by lateness. A higher GOGC runs fewer cycles and disturbs\n")
+	fmt.Printf("   the ticker less often, at the cost of a larger heap. On one core the\n")
+	fmt.Printf("   ticker also waits for the allocator's time slice, GC or not.\n\n")
+}
+
+// customWorkloads returns the custom workloads run selects: all of them
+// when it matches the custom suite's own name, otherwise those whose
+// names it matches.
+func customWorkloads(run *regexp.Regexp) []workloads.Registration {
+	var custom []workloads.Registration
+	for _, r := range workloads.Registered(nil) {
+		if r.Custom && (run.MatchString("custom") || run.MatchString(r.Name)) {
+			custom = append(custom, r)
+		}
+	}
+	return custom
+}
+
+// testCustom runs the full comparison on every workload supplied with
+// -plugin or bench.RegisterCustom.
+func (s *session) testCustom() {
+	fmt.Println("🧩 Custom Workloads")
+	fmt.Println(strings.Repeat("-", 60))
+
+	regs := customWorkloads(s.run)
+	if len(regs) == 0 {
+		fmt.Printf("   No custom workloads registered; load one with -plugin or a build-tag hook\n\n")
+		return
+	}
+	for _, reg := range regs {
+		r := s.compare(reg.New(s.cfg.Sizes))
+		s.results = append(s.results, r)
+
+		fmt.Printf("\n📈 %s (%d shards, %s):\n", r.Workload, r.Tasks, runsLabel(r))
+		fmt.Printf("   Concurrent:  %v (±%v)\n", r.Center(r.Concurrent), r.Spread(r.Concurrent).Round(time.Microsecond))
+		fmt.Printf("   Parallel:    %v (±%v)\n", r.Center(r.Parallel), r.Spread(r.Parallel).Round(time.Microsecond))
+		printSpread(r)
+		s.printOutliers(r)
+		s.printCI(r)
+		fmt.Printf("   Speedup:     %.2fx\n", r.Speedup())
+		s.printSignificance(r)
+		fmt.Printf("   Efficiency:  %.1f%% of %.2fx max (%d shards on %d cores)\n", r.Efficiency(), r.MaxSpeedup(), r.Tasks, r.Procs)
+		printRates(r)
+		if errors := r.Counters["errors"]; errors > 0 {
+			fmt.Printf("   Errors:      %.1f of %d shards per run\n", errors, r.Tasks)
+			s.warn(fmt.Sprintf("custom workload %s returned errors", r.Workload), nil)
+		}
+	}
+	fmt.Println()
+}
+
+// testScalability times a fixed amount of work split across more and more
+// goroutines at every GOMAXPROCS from 1 up to the core count, rendering
+// the matrix as a heatmap. Counts past the cores show oversubscription.
+func (s *session) testScalability() {
+	fmt.Println("📈 Scalability Test (Goroutines × GOMAXPROCS)")
+	fmt.Println(strings.Repeat("-", 60))
+
+	goroutines := runner.ProcsSweep(max(16, s.cfg.Procs*4))
+	procs := runner.ProcsSweep(s.cfg.Procs)
+	slog.Info("sweeping goroutines and GOMAXPROCS", "goroutines", len(goroutines), "procs", len(procs))
+	cells := runner.ScalingMatrix(goroutines, procs, 3)
+	s.scaling = append(s.scaling, cells...)
+
+	report.PrintScalingHeatmap(os.Stdout, cells)
+	report.PrintScalingModel(os.Stdout, cells)
+	fmt.Println()
+	s.testUnevenSplits()
+}
+
+// testUnevenSplits cuts the scalability sum into tasks of growing size
+// and compares splitting them evenly up front with a shared queue,
+// showing when each goroutine ran out of work.
+func (s *session) testUnevenSplits() {
+	workers := max(4, s.cfg.Procs)
+	fmt.Printf("   Uneven tasks: the same sum in 256 tasks growing from 1× to 256×,\n")
+	fmt.Printf("   %d goroutines at GOMAXPROCS=%d, median of 3 runs\n\n", workers, s.cfg.Procs)
+	slog.Info("comparing static and queued splits", "goroutines", workers)
+	runs := runner.CompareUnevenSplits(workers, s.cfg.Procs, 3)
+
+	fmt.Printf("   Split  | Wall       | Imbalance | Idle\n")
+	fmt.Printf("   -------|------------|-----------|------\n")
+	var longest time.Duration
+	for _, r := range runs {
+		fmt.Printf("   %-6s | %-10v | %8.2fx | %4.1f%%\n", r.Mode(), r.Elapsed.Round(time.Microsecond), r.Imbalance(), r.Idle()*100)
+		longest = max(longest, stats.Max(r.Finished))
+	}
+	static, queue := runs[0], runs[1]
+	fmt.Printf("\n   The shared queue finishes %.2fx sooner\n\n", float64(static.Elapsed)/float64(queue.Elapsed))
+
+	bar := func(d time.Duration) string {
+		return fmt.Sprintf("%-20s %v", strings.Repeat("█", max(1, int(float64(d)/float64(longest)*20))), d.Round(time.Microsecond))
+	}
+	fmt.Printf("   When each goroutine ran out of work:\n")
+	fmt.Printf("   Goroutine | Static                         | Queue\n")
+	fmt.Printf("   ----------|--------------------------------|-------------------------------\n")
+	for g := range static.Finished {
+		fmt.Printf("   %-9d | %-30s | %s\n", g, bar(static.Finished[g]), bar(queue.Finished[g]))
+	}
+
+	fmt.Printf("\n   Splitting by count hands the last goroutine the biggest tasks, and the\n")
+	fmt.Printf("   rest sit idle waiting for it: imbalance is the slowest finish over the\n")
+	fmt.Printf("   mean. Pulling from a shared queue lets a goroutine that drew small\n")
+	fmt.Printf("   tasks take more, so they finish together, the same effect work\n")
+	fmt.Printf("   stealing has in the Go scheduler and in fork/join pools.\n\n")
+}
+
+func (s *session) testHybridCores(classes []sysinfo.CoreClass) {
+	fmt.Println("🧬 Heterogeneous Cores (Per-Class CPU Sweep)")
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("   Core classes: %s\n\n", sysinfo.DescribeCoreClasses(classes))
+
+	fmt.Printf("   Class        | Cores | 1 Core    | All Cores | Speedup | Efficiency\n")
+	fmt.Printf("   -------------|-------|-----------|-----------|---------|-----------\n")
+
+	w := s.cfg.Sizes.CPU()
+	var perfSingle time.Duration
+	for i, c := range classes {
+		if len(c.CPUs) == 0 {
+			fmt.Printf("   %-12s | %-5d | (pinning unsupported on %s)\n", c.Name, c.Count, runtime.GOOS)
+			continue
+		}
+
+		restore, err := sysinfo.PinProcess(c.CPUs)
+		if err != nil {
+			fmt.Printf("   %-12s | %-5d | (pinning failed: %v)\n", c.Name, c.Count, err)
+			continue
+		}
+		runtime.GC()
+		single := w.Run(1, nil)
+		all := w.Run(c.Count, nil)
+		restore()
+
+		if i == 0 {
+			perfSingle = single
+		}
+		speedup := float64(single) / float64(all)
+		fmt.Printf("   %-12s | %-5d | %-9v | %-9v | %6.2fx | %8.1f%%\n",
+			c.Name, c.Count, single.Round(time.Microsecond), all.Round(time.Microsecond),
+			speedup, speedup/float64(c.Count)*100)
+	}
+
+	if perfSingle > 0 {
+		runtime.GC()
+		all := w.Run(s.cfg.Procs, nil)
+		effective := float64(perfSingle) / float64(all)
+		fmt.Printf("\n   All %d cores together are worth %.1f %s cores for this workload\n",
+			s.cfg.Procs, effective, classes[0].Name)
+	}
+	fmt.Println()
+}
+
+// testPlacement pins pairs of workers running memory-bound kernels to
+// hyperthread siblings, separate cores and separate sockets, against one
+// worker pinned alone.
+func testPlacement() {
+	fmt.Println("🗺️  CPU Placement: Siblings vs Cores vs Sockets")
+	fmt.Println(strings.Repeat("-", 60))
+
+	places, err := sysinfo.ReadTopology()
+	if err != nil {
+		fmt.Printf("   Skipped: needs sysfs CPU topology and sched_setaffinity (%v)\n\n", err)
+		return
+	}
+	cores, sockets := map[[2]int]bool{}, map[int]bool{}
+	for _, p := range places {
+		cores[[2]int{p.Socket, p.Core}] = true
+		sockets[p.Socket] = true
+	}
+	fmt.Printf("   %d CPUs on %d cores across %d sockets\n", len(places), len(cores), len(sockets))
+	placements := sysinfo.Placements(places)
+	if len(placements) == 0 {
+		fmt.Printf("   Skipped: no two CPUs to pin a pair to\n\n")
+		return
+	}
+	for _, p := range placements {
+		fmt.Printf("   %-8s %s\n", p.Name, p.Note)
+	}
+
+	l2 := sysinfo.DetectCPUInfo().L2KB << 10
+	if l2 == 0 {
+		l2 = 256 << 10
+	}
+	kernels := workloads.PlacementKernels(l2)
+	slog.Info("comparing placements", "placements", len(placements), "kernels", len(kernels))
+	results, err := runner.ComparePlacements(kernels, placements)
+	if err != nil {
+		fmt.Printf("   (pinning failed: %v)\n\n", err)
+		return
+	}
+
+	fmt.Printf("\n   Kernel   | Alone      ")
+	for _, p := range placements {
+		fmt.Printf("| %-18s ", p.Name)
+	}
+	fmt.Printf("\n   ---------|------------")
+	for range placements {
+		fmt.Printf("|--------------------")
+	}
+	fmt.Println()
+	for _, r := range results {
+		fmt.Printf("   %-8s | %-10v ", r.Kernel, r.Solo.Round(time.Microsecond))
+		for i, d := range r.Pairs {
+			fmt.Printf("| %-10v %5.2fx  ", d.Round(time.Microsecond), r.Slowdown(i))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+	for _, k := range kernels {
+		fmt.Printf("   %-8s %s\n", k.Name, k.Note)
+	}
+
+	fmt.Printf("\n   Every worker does the same work alone or in a pair, so the factor is\n")
+	fmt.Printf("   the pair's slowdown from sharing hardware: 1.00x means none. Siblings\n")
+	fmt.Printf("   split one core's caches and execution units; separate cores share the\n")
+	fmt.Printf("   L3 and memory controller, so they only contend for bandwidth; separate\n")
+	fmt.Printf("   sockets double the bandwidth but move a shared cache line across the\n")
+	fmt.Printf("   interconnect. The Go scheduler places goroutines on whatever thread is\n")
+	fmt.Printf("   free and the OS moves threads as it likes, so an unpinned run lands on\n")
+	fmt.Printf("   a mix of these and its timings wander between them.\n\n")
+}
+
+func (s *session) testClassification() {
+	fmt.Println("🔬 Workload Classification")
+	fmt.Println(strings.Repeat("-", 60))
+
+	procs := s.cfg.Procs
+
+	fmt.Printf("   Workload    | CPU Util | Cache Miss | Lock Wait | Class\n")
+	fmt.Printf("   ------------|----------|------------|-----------|--------------\n")
+
+	var reasons []string
+	for _, w := range s.workloads() {
+		profile := runner.ProfileWorkload(w, procs)
+		class, reason := profile.Classify()
+
+		missRate := "n/a"
+		if profile.PerfOK {
+			missRate = fmt.Sprintf("%.1f%%", profile.Perf.MissRate()*100)
+		}
+		fmt.Printf("   %-11s | %7.1f%% | %-10s | %-9v | %s\n",
+			w.Name(), profile.Utilization()*100, missRate, profile.MutexWait.Round(time.Microsecond), class)
+		reasons = append(reasons, fmt.Sprintf("   %s: %s", w.Name(), reason))
+	}
+
+	fmt.Println()
+	for _, r := range reasons {
+		fmt.Println(r)
+	}
+	fmt.Println()
+}
+
+// kneeThreshold is the share of the best throughput a GOMAXPROCS value must
+// reach to count as "scaling has flattened out"
+const kneeThreshold = 0.95
+
+// recommendGOMAXPROCS combines the container quota, CPU affinity, core
+// classes and a measured scaling sweep into one suggested GOMAXPROCS,
+// printing the evidence behind it.
+func (s *session) recommendGOMAXPROCS(coreClasses []sysinfo.CoreClass) {
+	fmt.Println("🎛️  GOMAXPROCS Recommendation")
+	fmt.Println(strings.Repeat("-", 60))
+
+	l := runner.DetectProcsLimit(runtime.NumCPU())
+	fmt.Printf("   NumCPU:          %d\n", l.NumCPU)
+	if l.AffinityErr == nil {
+		fmt.Printf("   CPU affinity:    %d CPUs\n", l.Affinity)
+	} else {
+		fmt.Printf("   CPU affinity:    unknown (%v)\n", l.AffinityErr)
+	}
+	if l.QuotaOK {
+		fmt.Printf("   Container quota: %.2f CPUs → %d\n", l.Quota, l.QuotaProcs())
+	} else {
+		fmt.Printf("   Container quota: none\n")
+	}
+	if len(coreClasses) > 1 {
+		fmt.Printf("   Core classes:    %s\n", sysinfo.DescribeCoreClasses(coreClasses))
+	}
+
+	fmt.Printf("\n   GOMAXPROCS | Time      | Throughput\n")
+	fmt.Printf("   -----------|-----------|-----------\n")
+
+	limit := l.Limit
+	counts := runner.ProcsSweep(limit)
+	w := s.cfg.Sizes.CPU()
+	rates := make([]float64, len(counts))
+	best := 0.0
+	for i, procs := range counts {
+		runtime.GC()
+		duration := w.Run(procs, nil)
+		rates[i] = float64(w.Tasks()) / duration.Seconds()
+		best = max(best, rates[i])
+		fmt.Printf("   %-10d | %-9v | %.1f tasks/s\n", procs, duration.Round(time.Microsecond), rates[i])
+	}
+
+	knee := limit
+	for i, procs := range counts {
+		if rates[i] >= best*kneeThreshold {
+			knee = procs
+			break
+		}
+	}
+
+	fmt.Printf("\n   Scaling flattens at GOMAXPROCS=%d (≥%.0f%% of peak throughput)\n", knee, kneeThreshold*100)
+	if len(coreClasses) > 1 && knee <= coreClasses[0].Count {
+		fmt.Printf("   Knee is within the %d %s cores; the rest add little for CPU-bound work\n",
+			coreClasses[0].Count, coreClasses[0].Name)
+	}
+	fmt.Printf("   ✅ Recommended GOMAXPROCS: %d", knee)
+	if knee < limit {
+		fmt.Printf(" for CPU-bound work (going up to %d adds <%.0f%% throughput)", limit, (1-kneeThreshold)*100)
+	}
+	fmt.Println()
+	fmt.Printf("   Note: I/O-bound services rarely gain from exceeding the CPU limit (%d)\n\n", limit)
+}
+
+// coolDown idles before the next suite and records the pause with the
+// session's results.
+func (s *session) coolDown(nextSuite string, period time.Duration, checkFreq bool, baselineMHz float64) {
+	event := runner.CoolDown(nextSuite, period, checkFreq, baselineMHz)
+	s.cooldowns = append(s.cooldowns, event)
+
+	attrs := []any{"before", nextSuite, "slept", time.Duration(event.SleptNS).Round(time.Millisecond)}
+	if event.StartMHz > 0 {
+		attrs = append(attrs, "start_mhz", event.StartMHz, "end_mhz", event.EndMHz)
+	}
+	slog.Info("cooled down", attrs...)
+	if !event.Recovered {
+		s.warn("clock still below baseline after cooling down before "+nextSuite, nil)
+	}
+}
@@ -0,0 +1,6 @@
+package main
+
+// version is set at release time:
+//
+//	go build -ldflags "-X main.version=v1.2.0" ./cmd/bench
+var version = ""
@@ -0,0 +1,40 @@
+// Command benchmark is the bench library on its own: it compares the
+// built-in CPU, I/O and mixed workloads at GOMAXPROCS=1 and at
+// -gomaxprocs and prints one line per workload. The suites, reports and
+// analysis modes live in cmd/bench.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"compare_process/bench"
+)
+
+func main() {
+	cfg := bench.DefaultConfig()
+	flag.IntVar(&cfg.Iterations, "iterations", cfg.Iterations, "runs per mode")
+	flag.IntVar(&cfg.Procs, "gomaxprocs", cfg.Procs, "GOMAXPROCS for the parallel runs")
+	flag.IntVar(&cfg.Sizes.PrimeLimit, "prime-limit", cfg.Sizes.PrimeLimit, "how far each CPU task counts primes")
+	flag.DurationVar(&cfg.Sizes.IOSleep, "io-sleep", cfg.Sizes.IOSleep, "simulated wait of one I/O operation")
+	flag.IntVar(&cfg.Sizes.IOOps, "io-ops", cfg.Sizes.IOOps, "I/O operations per I/O task")
+	flag.IntVar(&cfg.Sizes.Goroutines, "goroutines", cfg.Sizes.Goroutines, "goroutines per workload wave (default: per core)")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Config: %s\n", cfg)
+	results, err := bench.Run(ctx, cfg)
+	for _, r := range results {
+		fmt.Printf("%-8s %6.2fx of %.2fx max, %.1f%% efficient (%d goroutines)\n",
+			r.Workload, r.Speedup(), r.MaxSpeedup(), r.Efficiency(), r.Tasks)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchmark:", err)
+		os.Exit(1)
+	}
+}
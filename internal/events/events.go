@@ -0,0 +1,109 @@
+// Package events carries typed progress events from a benchmark run to any
+// number of sinks, so the console log, JSONL files and remote dashboards
+// all see the same stream.
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Event is one thing that happened during a run. Kind names it in the
+// serialized stream.
+type Event interface {
+	Kind() string
+}
+
+type SuiteStarted struct {
+	Suite string `json:"suite"`
+}
+
+// IterationCompleted reports one concurrent/parallel pair of runs. Parallel
+// is zero for suites that only time one mode.
+type IterationCompleted struct {
+	Suite      string        `json:"suite"`
+	Workload   string        `json:"workload"`
+	Iteration  int           `json:"iteration"`
+	Of         int           `json:"of"`
+	Concurrent time.Duration `json:"concurrent_ns"`
+	Parallel   time.Duration `json:"parallel_ns,omitempty"`
+}
+
+type SuiteFinished struct {
+	Suite   string        `json:"suite"`
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+// SuiteFailed reports a suite stopped by a panic, with the stack of the
+// goroutine that raised it, or by a resource cap. SuiteFinished still
+// follows.
+type SuiteFailed struct {
+	Suite  string `json:"suite"`
+	Reason string `json:"reason"`
+	Stack  string `json:"stack,omitempty"`
+}
+
+type Warning struct {
+	Message string `json:"message"`
+	Err     string `json:"error,omitempty"`
+}
+
+func (SuiteStarted) Kind() string       { return "suite_started" }
+func (IterationCompleted) Kind() string { return "iteration_completed" }
+func (SuiteFinished) Kind() string      { return "suite_finished" }
+func (SuiteFailed) Kind() string        { return "suite_failed" }
+func (Warning) Kind() string            { return "warning" }
+
+// Sink consumes events. Emit is called from the publishing goroutine, in
+// publish order; sinks that do slow work should hand it off.
+type Sink interface {
+	Emit(at time.Time, e Event) error
+	Close() error
+}
+
+// Bus fans events out to its sinks. The zero value has no sinks and drops
+// everything.
+type Bus struct {
+	mu    sync.Mutex
+	sinks []Sink
+	errs  []error
+}
+
+func (b *Bus) Subscribe(s Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, s)
+}
+
+// Publish stamps e with the current time and hands it to every sink. Sink
+// errors don't stop the run; they are collected and returned by Close.
+func (b *Bus) Publish(e Event) {
+	b.Emit(time.Now(), e)
+}
+
+// Emit hands e to every sink with the given timestamp, which lets one bus
+// subscribe to another and lets events recorded elsewhere be replayed.
+func (b *Bus) Emit(at time.Time, e Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.sinks {
+		if err := s.Emit(at, e); err != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+	return nil
+}
+
+// Close flushes and closes every sink.
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.sinks {
+		if err := s.Close(); err != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+	b.sinks = nil
+	return errors.Join(b.errs...)
+}
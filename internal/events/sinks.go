@@ -0,0 +1,192 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ConsoleSink logs each event through a slog.Logger: warnings at warn
+// level, suite boundaries at info and iterations at info.
+type ConsoleSink struct {
+	Logger *slog.Logger
+}
+
+func (s ConsoleSink) Emit(_ time.Time, e Event) error {
+	switch e := e.(type) {
+	case SuiteStarted:
+		s.Logger.Info("suite started", "suite", e.Suite)
+	case IterationCompleted:
+		attrs := []any{"suite", e.Suite, "workload", e.Workload, "n", e.Iteration, "of", e.Of, "concurrent", e.Concurrent}
+		if e.Parallel > 0 {
+			attrs = append(attrs, "parallel", e.Parallel)
+		}
+		s.Logger.Info("iteration", attrs...)
+	case SuiteFinished:
+		s.Logger.Info("suite finished", "suite", e.Suite, "elapsed", e.Elapsed.Round(time.Millisecond))
+	case SuiteFailed:
+		s.Logger.Error("suite failed", "suite", e.Suite, "reason", e.Reason)
+	case Warning:
+		if e.Err != "" {
+			s.Logger.Warn(e.Message, "err", e.Err)
+		} else {
+			s.Logger.Warn(e.Message)
+		}
+	default:
+		s.Logger.Info(e.Kind())
+	}
+	return nil
+}
+
+func (ConsoleSink) Close() error { return nil }
+
+// envelope is the serialized form shared by the JSONL and HTTP sinks.
+type envelope struct {
+	Type  string    `json:"type"`
+	Time  time.Time `json:"time"`
+	Event Event     `json:"event"`
+}
+
+// JSONLSink writes one JSON object per line.
+type JSONLSink struct {
+	w      io.Writer
+	closer io.Closer
+}
+
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{w: w}
+}
+
+// CreateJSONLSink writes events to a new file at path.
+func CreateJSONLSink(path string) (*JSONLSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating event log: %w", err)
+	}
+	return &JSONLSink{w: f, closer: f}, nil
+}
+
+func (s *JSONLSink) Emit(at time.Time, e Event) error {
+	data, err := json.Marshal(envelope{e.Kind(), at.UTC(), e})
+	if err != nil {
+		return fmt.Errorf("encoding %s event: %w", e.Kind(), err)
+	}
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing event log: %w", err)
+	}
+	return nil
+}
+
+func (s *JSONLSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// ReadJSONL decodes a stream written by a JSONLSink, calling fn for each
+// event in order. Unknown event types are skipped, so newer writers stay
+// readable.
+func ReadJSONL(r io.Reader, fn func(at time.Time, e Event)) error {
+	dec := json.NewDecoder(r)
+	for {
+		var raw struct {
+			Type  string          `json:"type"`
+			Time  time.Time       `json:"time"`
+			Event json.RawMessage `json:"event"`
+		}
+		if err := dec.Decode(&raw); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("decoding event log: %w", err)
+		}
+
+		var e Event
+		var err error
+		switch raw.Type {
+		case SuiteStarted{}.Kind():
+			e, err = decode[SuiteStarted](raw.Event)
+		case IterationCompleted{}.Kind():
+			e, err = decode[IterationCompleted](raw.Event)
+		case SuiteFinished{}.Kind():
+			e, err = decode[SuiteFinished](raw.Event)
+		case SuiteFailed{}.Kind():
+			e, err = decode[SuiteFailed](raw.Event)
+		case Warning{}.Kind():
+			e, err = decode[Warning](raw.Event)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("decoding %s event: %w", raw.Type, err)
+		}
+		fn(raw.Time, e)
+	}
+}
+
+func decode[T Event](data []byte) (Event, error) {
+	var e T
+	err := json.Unmarshal(data, &e)
+	return e, err
+}
+
+// HTTPSink POSTs each event as JSON to a URL. Requests are sent from a
+// background goroutine so a slow endpoint doesn't stall the benchmark;
+// Close waits for the queue to drain.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+	queue  chan []byte
+	done   chan error
+}
+
+func NewHTTPSink(url string) *HTTPSink {
+	s := &HTTPSink{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		queue:  make(chan []byte, 256),
+		done:   make(chan error, 1),
+	}
+	go s.send()
+	return s
+}
+
+func (s *HTTPSink) Emit(at time.Time, e Event) error {
+	data, err := json.Marshal(envelope{e.Kind(), at.UTC(), e})
+	if err != nil {
+		return fmt.Errorf("encoding %s event: %w", e.Kind(), err)
+	}
+	select {
+	case s.queue <- data:
+		return nil
+	default:
+		return fmt.Errorf("event endpoint %s is falling behind; dropped %s event", s.url, e.Kind())
+	}
+}
+
+func (s *HTTPSink) send() {
+	var firstErr error
+	for data := range s.queue {
+		resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(data))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("posting event to %s: %s", s.url, resp.Status)
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.done <- firstErr
+}
+
+func (s *HTTPSink) Close() error {
+	close(s.queue)
+	return <-s.done
+}
@@ -0,0 +1,170 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"compare_process/internal/runner"
+	"compare_process/internal/stats"
+	"compare_process/internal/sysinfo"
+)
+
+// Anomaly is a result whose speedup shouldn't happen on paper, with the
+// likely causes the recorded data supports, most likely first.
+type Anomaly struct {
+	Workload string
+	Kind     string // "superlinear" or "slowdown"
+	Speedup  float64
+	Causes   []Cause
+}
+
+// Cause is one explanation for an Anomaly. Score ranks the causes of one
+// anomaly against each other; it is a weight of the evidence, not a
+// probability.
+type Cause struct {
+	Score  float64
+	Reason string
+}
+
+// Anomalies flags results faster than their MaxSpeedup allows or whose
+// parallel runs were slower than their concurrent ones, and ranks what
+// may explain each from the runs' timings, GC and scheduler metrics, and
+// cpu's caches and clocks.
+func Anomalies(results []runner.Result, cpu sysinfo.CPUInfo) []Anomaly {
+	var anomalies []Anomaly
+	for _, r := range results {
+		var a Anomaly
+		switch {
+		case r.Speedup() > r.MaxSpeedup()*1.05:
+			a = Anomaly{Workload: r.Workload, Kind: "superlinear", Speedup: r.Speedup(), Causes: superlinearCauses(r, cpu)}
+		case r.Speedup() < 0.95:
+			a = Anomaly{Workload: r.Workload, Kind: "slowdown", Speedup: r.Speedup(), Causes: slowdownCauses(r, cpu)}
+		default:
+			continue
+		}
+		if cv := r.CV(); cv > 10 {
+			a.Causes = append(a.Causes, Cause{min(cv/30, 1), fmt.Sprintf("the runs are noisy (CV %.1f%%), so the speedup itself is uncertain", cv)})
+		}
+		slices.SortStableFunc(a.Causes, func(x, y Cause) int { return cmp.Compare(y.Score, x.Score) })
+		anomalies = append(anomalies, a)
+	}
+	return anomalies
+}
+
+func superlinearCauses(r runner.Result, cpu sysinfo.CPUInfo) []Cause {
+	var causes []Cause
+
+	// Split across cores, each share of the working set may fit a cache
+	// the whole didn't
+	perTask := r.ParallelAllocs.BytesPerOp()
+	whole := perTask * float64(r.Tasks)
+	l2 := float64(cpu.L2KB) * 1024
+	if l2 > 0 && whole > l2 && whole/float64(r.Procs) <= l2 {
+		causes = append(causes, Cause{0.8, fmt.Sprintf("cache effects: the %s allocated per run overflows one core's %d KiB L2, but each core's share fits",
+			byteSize(whole), cpu.L2KB)})
+	} else {
+		causes = append(causes, Cause{0.3, "cache effects: each core works on a smaller share of the data, which may fit its private caches"})
+	}
+
+	if extra := gcPerRun(r.ConcurrentRuntime, len(r.Concurrent)) - gcPerRun(r.ParallelRuntime, len(r.Parallel)); extra >= 0.5 {
+		causes = append(causes, Cause{min(0.3+extra/10, 0.9), fmt.Sprintf("GC: %.1f more cycles per run at GOMAXPROCS=1, where the collector takes time from the only P", extra)})
+	}
+	if p99 := r.ConcurrentRuntime.SchedLatency.Quantile(0.99); p99 > time.Millisecond {
+		causes = append(causes, Cause{0.4, fmt.Sprintf("run-queue delay: at GOMAXPROCS=1 goroutines waited up to %v (p99) to run, inflating the baseline", p99)})
+	}
+	if d := drift(r.Concurrent); d < -0.05 {
+		causes = append(causes, Cause{min(-d*5, 1), fmt.Sprintf("warm-up or frequency ramp: the later concurrent runs were %.0f%% faster than the first", -d*100)})
+	}
+	return causes
+}
+
+func slowdownCauses(r runner.Result, cpu sysinfo.CPUInfo) []Cause {
+	var causes []Cause
+
+	switch {
+	case r.Procs <= 1:
+		causes = append(causes, Cause{0.9, "one P: parallel mode ran at GOMAXPROCS=1 as well, so the two modes differ only by noise"})
+	case r.MaxSpeedup() <= 1.05:
+		causes = append(causes, Cause{0.9, fmt.Sprintf("too few tasks: %d goroutines can't use more than one P, so parallel mode only adds overhead", r.Tasks)})
+	}
+	if cpu.BaseMHz > 0 && cpu.BoostMHz > cpu.BaseMHz*1.1 {
+		causes = append(causes, Cause{0.4, fmt.Sprintf("frequency scaling: with every core busy the clock falls from the %.0f MHz single-core boost toward the %.0f MHz base",
+			cpu.BoostMHz, cpu.BaseMHz)})
+	}
+	if d := drift(r.Parallel); d > 0.05 {
+		causes = append(causes, Cause{min(d*5, 1), fmt.Sprintf("thermal throttling: the later parallel runs were %.0f%% slower than the first", d*100)})
+	}
+	if extra := gcPerRun(r.ParallelRuntime, len(r.Parallel)) - gcPerRun(r.ConcurrentRuntime, len(r.Concurrent)); extra >= 0.5 {
+		causes = append(causes, Cause{min(0.3+extra/10, 0.9), fmt.Sprintf("GC: %.1f more cycles per run in parallel mode", extra)})
+	}
+	concurrent := r.ConcurrentRuntime.SchedLatency.Quantile(0.99)
+	if parallel := r.ParallelRuntime.SchedLatency.Quantile(0.99); parallel > time.Millisecond && parallel > concurrent {
+		causes = append(causes, Cause{0.6, fmt.Sprintf("contention: goroutines waited longer to run with more Ps (p99 %v against %v), a sign of lock or channel handoffs",
+			parallel, concurrent)})
+	}
+	if len(causes) == 0 {
+		causes = append(causes, Cause{0.2, "coordination overhead: the tasks are too short for the cost of spreading them over Ps to pay off"})
+	}
+	return causes
+}
+
+// gcPerRun is the average number of GC cycles in one of runs runs.
+func gcPerRun(s runner.RuntimeStats, runs int) float64 {
+	if runs == 0 {
+		return 0
+	}
+	return float64(s.GCCycles) / float64(runs)
+}
+
+// drift is how much slower the second half of durations ran than the
+// first, as a fraction; durations are in the order they ran. It is 0
+// with too few runs to split.
+func drift(durations []time.Duration) float64 {
+	if len(durations) < 4 {
+		return 0
+	}
+	half := len(durations) / 2
+	first, second := stats.Average(durations[:half]), stats.Average(durations[len(durations)-half:])
+	if first <= 0 {
+		return 0
+	}
+	return float64(second)/float64(first) - 1
+}
+
+func byteSize(b float64) string {
+	switch {
+	case b >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", b/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", b/(1<<10))
+	}
+	return fmt.Sprintf("%.0f B", b)
+}
+
+// PrintAnomalies explains every anomalous speedup in results with its
+// causes, most likely first.
+func PrintAnomalies(w io.Writer, results []runner.Result, cpu sysinfo.CPUInfo) {
+	anomalies := Anomalies(results, cpu)
+	if len(anomalies) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "🔍 Speedup Anomalies")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	for _, a := range anomalies {
+		switch a.Kind {
+		case "superlinear":
+			fmt.Fprintf(w, "   %s: %.2fx is more than its cores allow. Likely causes:\n", a.Workload, a.Speedup)
+		default:
+			fmt.Fprintf(w, "   %s: %.2fx, parallel ran slower than concurrent. Likely causes:\n", a.Workload, a.Speedup)
+		}
+		for i, c := range a.Causes {
+			fmt.Fprintf(w, "      %d. %s\n", i+1, c.Reason)
+		}
+	}
+	fmt.Fprintln(w)
+}
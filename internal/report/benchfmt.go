@@ -0,0 +1,62 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"compare_process/internal/runner"
+)
+
+// WriteBenchfmt writes results the way `go test -bench -benchmem` prints
+// them, so benchstat and other benchfmt tools can read them. One op is one
+// task; each mode is a sub-benchmark whose -N suffix is its GOMAXPROCS.
+// The NumberStyle is ignored, since benchfmt fixes its own number format.
+func WriteBenchfmt(w io.Writer, f File, _ NumberStyle) error {
+	m := f.Metadata
+	fmt.Fprintf(w, "goos: %s\n", m.OS)
+	fmt.Fprintf(w, "goarch: %s\n", m.Arch)
+	fmt.Fprintf(w, "pkg: compare_process\n")
+	if m.CPU.Model != "" {
+		fmt.Fprintf(w, "cpu: %s\n", m.CPU.Model)
+	}
+
+	for _, r := range f.RunnerResults() {
+		name := "Benchmark" + benchName(r.Workload)
+		writeBenchLine(w, name+"/concurrent-1", r.Concurrent, r.Tasks, r.ConcurrentAllocs)
+		writeBenchLine(w, fmt.Sprintf("%s/parallel-%d", name, r.Procs), r.Parallel, r.Tasks, r.ParallelAllocs)
+	}
+	return nil
+}
+
+func writeBenchLine(w io.Writer, name string, runs []time.Duration, tasksPerRun int, allocs runner.Allocs) {
+	tasks := len(runs) * tasksPerRun
+	if tasks == 0 {
+		return
+	}
+	var total time.Duration
+	for _, d := range runs {
+		total += d
+	}
+
+	fmt.Fprintf(w, "%s\t%d\t%d ns/op", name, tasks, total.Nanoseconds()/int64(tasks))
+	// Files from before allocations were recorded have no task count
+	if allocs.Tasks > 0 {
+		fmt.Fprintf(w, "\t%d B/op\t%d allocs/op",
+			allocs.Bytes/uint64(allocs.Tasks), allocs.Objects/uint64(allocs.Tasks))
+	}
+	fmt.Fprintln(w)
+}
+
+// benchName drops characters benchfmt treats specially, e.g. the slash in
+// "I/O" would otherwise start a sub-benchmark.
+func benchName(workload string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, workload)
+}
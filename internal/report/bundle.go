@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// bundleFiles are the files WriteBundle renders from the result file,
+// in the order the index lists them
+var bundleFiles = []struct {
+	name, title string
+	render      func(io.Writer, File, NumberStyle) error
+}{
+	{"report.html", "HTML report", WriteHTML},
+	{"report.md", "Markdown report", WriteMarkdown},
+	{"report.txt", "Text report", WriteText},
+	{"summary.csv", "Summary CSV", WriteCSV},
+	{"results.bench", "Go benchmark format (for benchstat)", WriteBenchfmt},
+	{"speedup.svg", "Speedup chart", func(w io.Writer, f File, _ NumberStyle) error {
+		return WriteSpeedupSVG(w, f.RunnerResults())
+	}},
+	{"timings.svg", "Timings chart", func(w io.Writer, f File, _ NumberStyle) error {
+		return WriteTimingsSVG(w, f.RunnerResults())
+	}},
+}
+
+var bundleIndex = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Benchmark run {{.Recorded}}</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2rem; }
+  img { display: block; margin: 1rem 0; max-width: 100%; }
+</style>
+</head>
+<body>
+<h1>Benchmark run {{.Recorded}}</h1>
+<p>{{.Build}}<br>{{.Machine}}</p>
+<img src="speedup.svg" alt="Speedup chart">
+<img src="timings.svg" alt="Timings chart">
+<h2>Files</h2>
+<ul>
+{{- range .Files}}
+<li><a href="{{.Name}}">{{.Name}}</a>: {{.Title}}</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+type bundleLink struct{ Name, Title string }
+
+// WriteBundle writes every format of f, the raw iterations and the charts
+// into a new directory under parent named for the run's timestamp, with
+// an index.html linking them, and returns the directory.
+func WriteBundle(parent string, f File, style NumberStyle) (string, error) {
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return "", fmt.Errorf("creating bundle: %w", err)
+	}
+	dir := filepath.Join(parent, f.Metadata.Timestamp.Format("20060102-150405"))
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating bundle: %w", err)
+	}
+
+	links := []bundleLink{{"results.json", "Results and run metadata (JSON)"}, {"iterations.csv", "Every iteration (CSV)"}}
+	if err := WriteJSON(filepath.Join(dir, "results.json"), f); err != nil {
+		return dir, err
+	}
+	if err := WriteIterationsCSV(filepath.Join(dir, "iterations.csv"), f.RunnerResults()); err != nil {
+		return dir, err
+	}
+	for _, b := range bundleFiles {
+		var buf bytes.Buffer
+		if err := b.render(&buf, f, style); err != nil {
+			return dir, fmt.Errorf("rendering %s: %w", b.name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, b.name), buf.Bytes(), 0o644); err != nil {
+			return dir, fmt.Errorf("writing %s: %w", b.name, err)
+		}
+		links = append(links, bundleLink{b.name, b.title})
+	}
+
+	var buf bytes.Buffer
+	err := bundleIndex.Execute(&buf, struct {
+		Recorded, Build, Machine string
+		Files                    []bundleLink
+	}{
+		Recorded: f.Metadata.Timestamp.Format("2006-01-02 15:04:05 MST"),
+		Build:    f.Metadata.Build.String(),
+		Machine:  machine(f.Metadata),
+		Files:    links,
+	})
+	if err != nil {
+		return dir, fmt.Errorf("rendering index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), buf.Bytes(), 0o644); err != nil {
+		return dir, fmt.Errorf("writing index: %w", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"compare_process/internal/runner"
+)
+
+// Chart geometry shared by the SVG charts: one row of bars per workload,
+// labels in a left margin
+const (
+	chartLabelWidth = 110
+	chartBarWidth   = 420
+	chartRowHeight  = 36
+	chartMargin     = 40
+)
+
+// WriteSpeedupSVG charts each workload's speedup as a bar against a tick
+// at the best speedup its cores allow.
+func WriteSpeedupSVG(w io.Writer, results []runner.Result) error {
+	top := 1.0
+	for _, r := range results {
+		top = max(top, r.Speedup(), r.MaxSpeedup())
+	}
+	scale := chartBarWidth / top
+
+	height := chartMargin*2 + chartRowHeight*len(results)
+	openSVG(w, "Speedup (bar) vs Max (tick)", height)
+	for i, r := range results {
+		y := chartMargin + i*chartRowHeight
+		fmt.Fprintf(w, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", chartLabelWidth-8, y+18, html.EscapeString(r.Workload))
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="%.1f" height="24" fill="#4c78a8"/>`+"\n", chartLabelWidth, y+2, r.Speedup()*scale)
+		tick := float64(chartLabelWidth) + r.MaxSpeedup()*scale
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#e45756" stroke-width="3"/>`+"\n", tick, y, tick, y+28)
+		fmt.Fprintf(w, `<text x="%.1f" y="%d">%.2fx</text>`+"\n", float64(chartLabelWidth)+max(r.Speedup(), r.MaxSpeedup())*scale+6, y+18, r.Speedup())
+	}
+	return closeSVG(w)
+}
+
+// WriteTimingsSVG charts each workload's concurrent and parallel times as
+// a pair of bars.
+func WriteTimingsSVG(w io.Writer, results []runner.Result) error {
+	var top time.Duration
+	for _, r := range results {
+		top = max(top, r.Center(r.Concurrent), r.Center(r.Parallel))
+	}
+	scale := 0.0
+	if top > 0 {
+		scale = chartBarWidth / float64(top)
+	}
+
+	height := chartMargin*2 + chartRowHeight*len(results)
+	openSVG(w, "Concurrent (top) vs Parallel (bottom) time", height)
+	for i, r := range results {
+		y := chartMargin + i*chartRowHeight
+		fmt.Fprintf(w, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", chartLabelWidth-8, y+18, html.EscapeString(r.Workload))
+		for j, d := range []time.Duration{r.Center(r.Concurrent), r.Center(r.Parallel)} {
+			fill := []string{"#f58518", "#54a24b"}[j]
+			by := y + 2 + j*13
+			fmt.Fprintf(w, `<rect x="%d" y="%d" width="%.1f" height="12" fill="%s"/>`+"\n", chartLabelWidth, by, float64(d)*scale, fill)
+			fmt.Fprintf(w, `<text x="%.1f" y="%d" font-size="10">%v</text>`+"\n", float64(chartLabelWidth)+float64(d)*scale+6, by+10, d.Round(time.Microsecond))
+		}
+	}
+	return closeSVG(w)
+}
+
+func openSVG(w io.Writer, title string, height int) {
+	width := chartLabelWidth + chartBarWidth + 100
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="13">`+"\n", width, height)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(w, `<text x="%d" y="24" font-weight="bold">%s</text>`+"\n", chartLabelWidth, html.EscapeString(title))
+}
+
+func closeSVG(w io.Writer) error {
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
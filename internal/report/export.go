@@ -0,0 +1,354 @@
+// Package report renders and persists benchmark results: the summary
+// table and findings, the composite score, and the JSON result files.
+package report
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
+
+	"compare_process/internal/runner"
+	"compare_process/internal/sysinfo"
+)
+
+// Metadata describes the machine and run, stored alongside exported
+// results so they can be compared across machines later.
+type Metadata struct {
+	Timestamp  time.Time           `json:"timestamp"`
+	Hostname   string              `json:"hostname,omitempty"`
+	Build      sysinfo.BuildInfo   `json:"build"`
+	GoVersion  string              `json:"go_version"`
+	OS         string              `json:"os"`
+	Arch       string              `json:"arch"`
+	NumCPU     int                 `json:"num_cpu"`
+	GOMAXPROCS int                 `json:"gomaxprocs"`
+	CPU        sysinfo.CPUInfo     `json:"cpu"`
+	Env        sysinfo.Environment `json:"environment"`
+	Clock      sysinfo.Clock       `json:"clock"`
+
+	// Order the suites actually ran in, and the seed when it was shuffled
+	SuiteOrder  []string `json:"suite_order,omitempty"`
+	ShuffleSeed int64    `json:"shuffle_seed,omitempty"`
+	// OrderSeed seeded the order of the modes in each A/B/B/A block
+	OrderSeed int64 `json:"order_seed,omitempty"`
+}
+
+// CollectMetadata snapshots the host; version is the -ldflags release
+// version, if any.
+func CollectMetadata(version string) Metadata {
+	hostname, _ := os.Hostname()
+	return Metadata{
+		Timestamp:  time.Now().UTC(),
+		Hostname:   hostname,
+		Build:      sysinfo.ReadBuildInfo(version),
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		CPU:        sysinfo.DetectCPUInfo(),
+		Env:        sysinfo.DetectEnvironment(),
+		Clock:      sysinfo.MeasureClock(),
+	}
+}
+
+// Result is a runner.Result in its on-disk form.
+type Result struct {
+	Workload     string  `json:"workload"`
+	Tasks        int     `json:"tasks,omitempty"`
+	Procs        int     `json:"procs,omitempty"`
+	ConcurrentNS []int64 `json:"concurrent_ns"`
+	ParallelNS   []int64 `json:"parallel_ns"`
+	Speedup      float64 `json:"speedup"`
+	MaxSpeedup   float64 `json:"max_speedup"`
+	Efficiency   float64 `json:"efficiency"`
+
+	Counters map[string]float64 `json:"counters,omitempty"`
+	Gauges   map[string]float64 `json:"gauges,omitempty"`
+
+	ConcurrentAllocs runner.Allocs `json:"concurrent_allocs"`
+	ParallelAllocs   runner.Allocs `json:"parallel_allocs"`
+
+	ConcurrentRuntime runner.RuntimeStats `json:"concurrent_runtime,omitzero"`
+	ParallelRuntime   runner.RuntimeStats `json:"parallel_runtime,omitzero"`
+
+	ConcurrentMicro runner.Micro `json:"concurrent_micro,omitzero"`
+	ParallelMicro   runner.Micro `json:"parallel_micro,omitzero"`
+
+	// Timings an outlier rule discarded, left out of the stats above, and
+	// the zero-based iteration each ran in
+	ConcurrentOutliersNS   []int64 `json:"concurrent_outliers_ns,omitempty"`
+	ParallelOutliersNS     []int64 `json:"parallel_outliers_ns,omitempty"`
+	ConcurrentOutlierIters []int   `json:"concurrent_outlier_iterations,omitempty"`
+	ParallelOutlierIters   []int   `json:"parallel_outlier_iterations,omitempty"`
+	Robust                 bool    `json:"robust,omitempty"`
+	// Paired says each iteration's two timings ran back to back
+	Paired bool `json:"paired,omitempty"`
+	// WindowNS is the -benchtime each timing averaged its runs over
+	WindowNS int64 `json:"window_ns,omitempty"`
+}
+
+type File struct {
+	Metadata  Metadata               `json:"metadata"`
+	Results   []Result               `json:"results"`
+	Cooldowns []runner.CooldownEvent `json:"cooldowns,omitempty"`
+
+	// Scheduler latency per suite, in the order they ran
+	Sched []runner.SuiteSched `json:"suite_sched,omitempty"`
+
+	// Suites a panic or resource cap stopped, whose results are missing
+	Failures []SuiteFailure `json:"failures,omitempty"`
+
+	// The scalability suite's goroutines × GOMAXPROCS matrix
+	Scaling []runner.ScalingCell `json:"scaling,omitempty"`
+}
+
+func nanos(durations []time.Duration) []int64 {
+	ns := make([]int64, len(durations))
+	for i, d := range durations {
+		ns[i] = d.Nanoseconds()
+	}
+	return ns
+}
+
+// NewFile assembles a run's results and records in their on-disk form.
+func NewFile(meta Metadata, results []runner.Result, cooldowns []runner.CooldownEvent, sched []runner.SuiteSched, failures []SuiteFailure, scaling []runner.ScalingCell) File {
+	file := File{Metadata: meta, Cooldowns: cooldowns, Sched: sched, Failures: failures, Scaling: scaling}
+	for _, r := range results {
+		file.Results = append(file.Results, Result{
+			Workload:     r.Workload,
+			Tasks:        r.Tasks,
+			Procs:        r.Procs,
+			ConcurrentNS: nanos(r.Concurrent),
+			ParallelNS:   nanos(r.Parallel),
+			Speedup:      r.Speedup(),
+			MaxSpeedup:   r.MaxSpeedup(),
+			Efficiency:   r.Efficiency(),
+			Counters:     r.Counters,
+			Gauges:       r.Gauges,
+
+			ConcurrentAllocs: r.ConcurrentAllocs,
+			ParallelAllocs:   r.ParallelAllocs,
+
+			ConcurrentRuntime: r.ConcurrentRuntime,
+			ParallelRuntime:   r.ParallelRuntime,
+
+			ConcurrentMicro: r.ConcurrentMicro,
+			ParallelMicro:   r.ParallelMicro,
+
+			ConcurrentOutliersNS:   nanos(r.ConcurrentOutliers),
+			ParallelOutliersNS:     nanos(r.ParallelOutliers),
+			ConcurrentOutlierIters: r.ConcurrentOutlierIters,
+			ParallelOutlierIters:   r.ParallelOutlierIters,
+			Robust:                 r.Robust,
+			Paired:                 r.Paired,
+			WindowNS:               r.Window.Nanoseconds(),
+		})
+	}
+	return file
+}
+
+// WriteJSON stores file at path atomically: it is written and synced
+// beside path, then renamed over it, so path holds either the old file or
+// the new one in full, even if the process or machine dies midway.
+func WriteJSON(path string, file File) error {
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, file); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := writeSynced(tmp, buf.Bytes()); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing results: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing results: %w", err)
+	}
+	return nil
+}
+
+func writeSynced(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// EncodeJSON writes file to w in the format WriteJSON stores.
+func EncodeJSON(w io.Writer, file File) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding results: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing results: %w", err)
+	}
+	return nil
+}
+
+// WriteIterationsCSV writes every iteration of every result to path, one
+// row per run in long format (test, mode, gomaxprocs, goroutines,
+// iteration, duration_ns, outlier), for analysis in tools that want raw
+// samples rather than the averages. Discarded outliers are marked true
+// and keep the iteration they ran in, so a paired result's rows still
+// line up by iteration.
+func WriteIterationsCSV(path string, results []runner.Result) error {
+	var buf bytes.Buffer
+	if err := EncodeIterationsCSV(&buf, results); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing iterations: %w", err)
+	}
+	return nil
+}
+
+// EncodeIterationsCSV writes the rows WriteIterationsCSV stores to w.
+func EncodeIterationsCSV(w io.Writer, results []runner.Result) error {
+	if err := encodeIterationRows(w, [][]string{iterationsHeader}); err != nil {
+		return err
+	}
+	return encodeIterationRows(w, iterationRows(results))
+}
+
+var iterationsHeader = []string{"test", "mode", "gomaxprocs", "goroutines", "iteration", "duration_ns", "outlier"}
+
+// iterationRows are the CSV rows of every iteration of results, without
+// the header.
+func iterationRows(results []runner.Result) [][]string {
+	var rows [][]string
+	for _, r := range results {
+		for _, m := range []struct {
+			mode     string
+			procs    int
+			runs     []time.Duration
+			outliers []time.Duration
+			iters    []int
+		}{
+			{"concurrent", 1, r.Concurrent, r.ConcurrentOutliers, r.ConcurrentOutlierIters},
+			{"parallel", r.Procs, r.Parallel, r.ParallelOutliers, r.ParallelOutlierIters},
+		} {
+			type sample struct {
+				iter    int
+				d       time.Duration
+				outlier bool
+			}
+			var samples []sample
+			for i, iter := range runner.KeptIterations(len(m.runs), m.iters) {
+				samples = append(samples, sample{iter, m.runs[i], false})
+			}
+			for i, d := range m.outliers {
+				// Exports from before the iterations were kept put
+				// the outliers after the kept runs
+				iter := len(m.runs) + i
+				if i < len(m.iters) {
+					iter = m.iters[i]
+				}
+				samples = append(samples, sample{iter, d, true})
+			}
+			slices.SortStableFunc(samples, func(a, b sample) int { return cmp.Compare(a.iter, b.iter) })
+			for _, s := range samples {
+				rows = append(rows, []string{
+					r.Workload,
+					m.mode,
+					strconv.Itoa(m.procs),
+					strconv.Itoa(r.Tasks),
+					strconv.Itoa(s.iter + 1),
+					strconv.FormatInt(s.d.Nanoseconds(), 10),
+					strconv.FormatBool(s.outlier),
+				})
+			}
+		}
+	}
+	return rows
+}
+
+// encodeIterationRows writes rows to w in a single Write, so a file opened
+// for appending never holds half of them.
+func encodeIterationRows(w io.Writer, rows [][]string) error {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.WriteAll(rows)
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("encoding iterations: %w", err)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing iterations: %w", err)
+	}
+	return nil
+}
+
+// RunnerResults converts the stored results back into runner form, so a
+// saved run can be summarized like a live one. Files from before tasks and
+// procs were recorded fall back to the machine's core count.
+func (f File) RunnerResults() []runner.Result {
+	results := make([]runner.Result, len(f.Results))
+	for i, r := range f.Results {
+		results[i] = runner.Result{
+			Workload:   r.Workload,
+			Tasks:      cmp.Or(r.Tasks, f.Metadata.NumCPU),
+			Procs:      cmp.Or(r.Procs, f.Metadata.NumCPU),
+			Concurrent: durations(r.ConcurrentNS),
+			Parallel:   durations(r.ParallelNS),
+			Counters:   r.Counters,
+			Gauges:     r.Gauges,
+
+			ConcurrentAllocs: r.ConcurrentAllocs,
+			ParallelAllocs:   r.ParallelAllocs,
+
+			ConcurrentRuntime: r.ConcurrentRuntime,
+			ParallelRuntime:   r.ParallelRuntime,
+
+			ConcurrentMicro: r.ConcurrentMicro,
+			ParallelMicro:   r.ParallelMicro,
+
+			ConcurrentOutliers:     durations(r.ConcurrentOutliersNS),
+			ParallelOutliers:       durations(r.ParallelOutliersNS),
+			ConcurrentOutlierIters: r.ConcurrentOutlierIters,
+			ParallelOutlierIters:   r.ParallelOutlierIters,
+			Robust:                 r.Robust,
+			Paired:                 r.Paired,
+			Window:                 time.Duration(r.WindowNS),
+		}
+	}
+	return results
+}
+
+func durations(ns []int64) []time.Duration {
+	d := make([]time.Duration, len(ns))
+	for i, n := range ns {
+		d[i] = time.Duration(n)
+	}
+	return d
+}
+
+func ReadJSON(path string) (File, error) {
+	var file File
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, fmt.Errorf("reading results: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return file, nil
+}
@@ -0,0 +1,172 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"compare_process/internal/runner"
+	"compare_process/internal/stats"
+)
+
+func ms(v ...float64) []time.Duration {
+	d := make([]time.Duration, len(v))
+	for i, x := range v {
+		d[i] = time.Duration(x * float64(time.Millisecond))
+	}
+	return d
+}
+
+// sampleResult has every field the export stores set.
+func sampleResult() runner.Result {
+	return runner.Result{
+		Workload:   "CPU",
+		Tasks:      8,
+		Procs:      4,
+		Concurrent: ms(40, 41, 42),
+		Parallel:   ms(11, 12, 10),
+		Counters:   map[string]float64{"primes": 9592},
+		Gauges:     map[string]float64{"goroutines": 8},
+
+		ConcurrentAllocs: runner.Allocs{Bytes: 1 << 20, Objects: 300, Tasks: 24},
+		ParallelAllocs:   runner.Allocs{Bytes: 1 << 19, Objects: 200, Tasks: 24},
+
+		ConcurrentRuntime: runner.RuntimeStats{GCCycles: 3, Goroutines: 2},
+		ParallelRuntime:   runner.RuntimeStats{GCCycles: 4, Goroutines: 2},
+
+		ConcurrentMicro: runner.Micro{Ops: 100, TotalNS: 5000},
+		ParallelMicro:   runner.Micro{Ops: 100, TotalNS: 6000},
+
+		ConcurrentOutliers:     ms(90),
+		ParallelOutliers:       ms(30),
+		ConcurrentOutlierIters: []int{1},
+		ParallelOutlierIters:   []int{1},
+		Robust:                 true,
+		Paired:                 true,
+		Window:                 50 * time.Millisecond,
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	meta := Metadata{Hostname: "bench-host", NumCPU: 4, GOMAXPROCS: 4, SuiteOrder: []string{"cpu", "io"}, ShuffleSeed: 3, OrderSeed: 5}
+	results := []runner.Result{sampleResult()}
+	cooldowns := []runner.CooldownEvent{{Before: "io", SleptNS: 1e9, StartMHz: 3000, EndMHz: 3600, Recovered: true}}
+	sched := []runner.SuiteSched{{Suite: "cpu"}}
+	failures := []SuiteFailure{{Suite: "io", Reason: "panic: boom", Stack: "goroutine 1"}}
+	scaling := []runner.ScalingCell{{Goroutines: 4, Procs: 2, Elapsed: time.Millisecond, Speedup: 1.9}}
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := WriteJSON(path, NewFile(meta, results, cooldowns, sched, failures, scaling)); err != nil {
+		t.Fatal(err)
+	}
+	file, err := ReadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := file.RunnerResults(); !reflect.DeepEqual(got, results) {
+		t.Errorf("results read back as\n%+v\nwant\n%+v", got, results)
+	}
+	if !reflect.DeepEqual(file.Metadata, meta) {
+		t.Errorf("metadata read back as %+v, want %+v", file.Metadata, meta)
+	}
+	if !reflect.DeepEqual(file.Cooldowns, cooldowns) || !reflect.DeepEqual(file.Sched, sched) ||
+		!reflect.DeepEqual(file.Failures, failures) || !reflect.DeepEqual(file.Scaling, scaling) {
+		t.Errorf("records read back as %+v %+v %+v %+v", file.Cooldowns, file.Sched, file.Failures, file.Scaling)
+	}
+	if r := file.Results[0]; r.Speedup != results[0].Speedup() || r.Efficiency != results[0].Efficiency() {
+		t.Errorf("stored speedup %g and efficiency %g, want %g and %g",
+			r.Speedup, r.Efficiency, results[0].Speedup(), results[0].Efficiency())
+	}
+}
+
+// TestRunnerResultsLegacy checks files from before tasks and procs were
+// stored fall back to the machine's core count.
+func TestRunnerResultsLegacy(t *testing.T) {
+	file := File{
+		Metadata: Metadata{NumCPU: 6},
+		Results:  []Result{{Workload: "I/O", ConcurrentNS: []int64{5e6}, ParallelNS: []int64{5e6}}},
+	}
+	r := file.RunnerResults()[0]
+	if r.Tasks != 6 || r.Procs != 6 {
+		t.Errorf("legacy result has tasks %d and procs %d, want 6 and 6", r.Tasks, r.Procs)
+	}
+	if !reflect.DeepEqual(r.Concurrent, ms(5)) || !reflect.DeepEqual(r.Parallel, ms(5)) {
+		t.Errorf("legacy timings read back as %v and %v", r.Concurrent, r.Parallel)
+	}
+}
+
+func TestIterationsCSV(t *testing.T) {
+	paired := sampleResult()
+	legacy := sampleResult()
+	legacy.Workload, legacy.Paired = "I/O", false
+	legacy.ConcurrentOutlierIters, legacy.ParallelOutlierIters = nil, nil
+
+	var buf bytes.Buffer
+	if err := EncodeIterationsCSV(&buf, []runner.Result{paired, legacy}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		iterationsHeader,
+		// The outlier keeps the iteration it ran in, between the kept runs
+		{"CPU", "concurrent", "1", "8", "1", "40000000", "false"},
+		{"CPU", "concurrent", "1", "8", "2", "90000000", "true"},
+		{"CPU", "concurrent", "1", "8", "3", "41000000", "false"},
+		{"CPU", "concurrent", "1", "8", "4", "42000000", "false"},
+		{"CPU", "parallel", "4", "8", "1", "11000000", "false"},
+		{"CPU", "parallel", "4", "8", "2", "30000000", "true"},
+		{"CPU", "parallel", "4", "8", "3", "12000000", "false"},
+		{"CPU", "parallel", "4", "8", "4", "10000000", "false"},
+		// Without their iterations, outliers follow the kept runs
+		{"I/O", "concurrent", "1", "8", "1", "40000000", "false"},
+		{"I/O", "concurrent", "1", "8", "2", "41000000", "false"},
+		{"I/O", "concurrent", "1", "8", "3", "42000000", "false"},
+		{"I/O", "concurrent", "1", "8", "4", "90000000", "true"},
+		{"I/O", "parallel", "4", "8", "1", "11000000", "false"},
+		{"I/O", "parallel", "4", "8", "2", "12000000", "false"},
+		{"I/O", "parallel", "4", "8", "3", "10000000", "false"},
+		{"I/O", "parallel", "4", "8", "4", "30000000", "true"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows =\n%v\nwant\n%v", rows, want)
+	}
+}
+
+// TestIterationsCSVRejected checks rows of a result whose pairs an outlier
+// rule split still line up by iteration across the modes.
+func TestIterationsCSVRejected(t *testing.T) {
+	r := runner.Result{
+		Workload:   "CPU",
+		Tasks:      2,
+		Procs:      2,
+		Concurrent: ms(20, 20, 21, 20, 80, 21),
+		Parallel:   ms(10, 11, 12, 10, 11, 12),
+		Paired:     true,
+	}.RejectOutliers(stats.Tukey)
+
+	var buf bytes.Buffer
+	if err := EncodeIterationsCSV(&buf, []runner.Result{r}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outliers := map[string][]string{}
+	for _, row := range rows[1:] {
+		if row[6] == "true" {
+			outliers[row[1]] = append(outliers[row[1]], row[4]+"="+row[5])
+		}
+	}
+	want := map[string][]string{"concurrent": {"5=80000000"}, "parallel": {"5=11000000"}}
+	if !reflect.DeepEqual(outliers, want) {
+		t.Errorf("outliers by mode = %v, want %v", outliers, want)
+	}
+}
@@ -0,0 +1,37 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SuiteFailure is a suite stopped by a panic, with the stack of the
+// goroutine that raised it, or by going over a resource cap.
+type SuiteFailure struct {
+	Suite  string `json:"suite"`
+	Reason string `json:"reason"`
+	Stack  string `json:"stack,omitempty"`
+}
+
+// PrintFailures lists the suites that failed, each with its stack if it
+// panicked, so the rest of the report can be read knowing which results
+// are missing.
+func PrintFailures(w io.Writer, failures []SuiteFailure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "💥 Failed Suites")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	for _, f := range failures {
+		fmt.Fprintf(w, "   %s: %s\n", f.Suite, f.Reason)
+		if f.Stack == "" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(f.Stack, "\n"), "\n") {
+			fmt.Fprintf(w, "      %s\n", line)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,271 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"compare_process/internal/runner"
+)
+
+// Renderers regenerate a report from a stored result file, keyed by the
+// name -format accepts.
+var Renderers = map[string]func(io.Writer, File, NumberStyle) error{
+	"text":  WriteText,
+	"md":    WriteMarkdown,
+	"csv":   WriteCSV,
+	"html":  WriteHTML,
+	"bench": WriteBenchfmt,
+}
+
+// FormatNames lists the Renderers keys in a stable order for help text.
+func FormatNames() []string {
+	names := make([]string, 0, len(Renderers))
+	for name := range Renderers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// row is one result with the derived numbers every human-readable format
+// shows, already formatted in the report's NumberStyle.
+type row struct {
+	Workload             string
+	Iterations           int
+	Concurrent, Parallel string
+	Speedup, MaxSpeedup  string
+	Efficiency           string
+	CV                   string
+	Metrics              string
+}
+
+func rows(results []runner.Result, style NumberStyle) []row {
+	out := make([]row, len(results))
+	for i, r := range results {
+		cv := "n/a"
+		if !math.IsNaN(r.CV()) {
+			cv = style.Number(r.CV(), 1) + "%"
+		}
+		out[i] = row{
+			Workload:   r.Workload,
+			Iterations: len(r.Concurrent),
+			Concurrent: style.Duration(r.Center(r.Concurrent)),
+			Parallel:   style.Duration(r.Center(r.Parallel)),
+			Speedup:    style.Number(r.Speedup(), 2),
+			MaxSpeedup: style.Number(r.MaxSpeedup(), 2),
+			Efficiency: style.Number(r.Efficiency(), 1),
+			CV:         cv,
+			Metrics:    metricsLine(r),
+		}
+	}
+	return out
+}
+
+// machine is the one-line description of where the results came from.
+func machine(m Metadata) string {
+	return fmt.Sprintf("%s, %d cores, %s/%s, %s", valueOr(m.CPU.Model, "unknown CPU"), m.NumCPU, m.OS, m.Arch, m.GoVersion)
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func WriteText(w io.Writer, f File, style NumberStyle) error {
+	fmt.Fprintf(w, "Benchmark: %s\n", f.Metadata.Build)
+	fmt.Fprintf(w, "Recorded: %s\n", f.Metadata.Timestamp.Format(time.RFC3339))
+	fmt.Fprintf(w, "Machine: %s\n\n", machine(f.Metadata))
+	results := f.RunnerResults()
+	PrintSummary(w, results, style)
+	PrintAnomalies(w, results, f.Metadata.CPU)
+	PrintSchedLatency(w, f.Sched, results)
+	if len(f.Scaling) > 0 {
+		fmt.Fprintln(w, "📈 Scalability (Goroutines × GOMAXPROCS)")
+		fmt.Fprintln(w, strings.Repeat("=", 60))
+		PrintScalingHeatmap(w, f.Scaling)
+		PrintScalingModel(w, f.Scaling)
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func WriteMarkdown(w io.Writer, f File, style NumberStyle) error {
+	results := f.RunnerResults()
+
+	fmt.Fprintf(w, "# Concurrency vs Parallelism Results\n\n")
+	fmt.Fprintf(w, "- **Benchmark:** %s\n", f.Metadata.Build)
+	fmt.Fprintf(w, "- **Recorded:** %s\n", f.Metadata.Timestamp.Format(time.RFC3339))
+	fmt.Fprintf(w, "- **Machine:** %s\n", machine(f.Metadata))
+	fmt.Fprintf(w, "- **Environment:** %s\n\n", f.Metadata.Env)
+
+	fmt.Fprintf(w, "| Workload | Runs | Concurrent | Parallel | Speedup | Max | Efficiency | CV | Metrics |\n")
+	fmt.Fprintf(w, "|---|---:|---:|---:|---:|---:|---:|---:|---|\n")
+	for _, r := range rows(results, style) {
+		fmt.Fprintf(w, "| %s | %d | %s | %s | %sx | %sx | %s%% | %s | %s |\n",
+			r.Workload, r.Iterations, r.Concurrent, r.Parallel, r.Speedup, r.MaxSpeedup, r.Efficiency, r.CV, r.Metrics)
+	}
+
+	if len(results) > 0 {
+		fmt.Fprintf(w, "\n## Findings\n\n")
+		for _, finding := range Findings(results) {
+			fmt.Fprintf(w, "- %s\n", finding)
+		}
+	}
+
+	if len(f.Scaling) > 0 {
+		goroutines, procs := scalingAxes(f.Scaling)
+		fmt.Fprintf(w, "\n## Scalability\n\n")
+		fmt.Fprintf(w, "Speedup over 1 goroutine at GOMAXPROCS=1, goroutines down and GOMAXPROCS across.\n\n")
+		fmt.Fprintf(w, "| Goroutines |")
+		for _, p := range procs {
+			fmt.Fprintf(w, " %d |", p)
+		}
+		fmt.Fprintf(w, "\n|---:|%s\n", strings.Repeat("---:|", len(procs)))
+		for _, g := range goroutines {
+			fmt.Fprintf(w, "| %d |", g)
+			for _, p := range procs {
+				if c, ok := scalingCell(f.Scaling, g, p); ok {
+					fmt.Fprintf(w, " %sx |", style.Number(c.Speedup, 2))
+				} else {
+					fmt.Fprintf(w, " |")
+				}
+			}
+			fmt.Fprintln(w)
+		}
+		if model, ok := runner.FitUSL(f.Scaling); ok {
+			fmt.Fprintf(w, "\nUSL fit: contention σ=%.4f, coherence κ=%.5f", model.Sigma, model.Kappa)
+			if peak, ok := model.Peak(); ok {
+				fmt.Fprintf(w, ", predicted optimal concurrency %d goroutines", max(1, int(math.Round(peak))))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
+
+// WriteCSV ignores the NumberStyle: CSV is for further processing, so it
+// always uses nanoseconds and plain numbers.
+func WriteCSV(w io.Writer, f File, _ NumberStyle) error {
+	results := f.RunnerResults()
+
+	// One column per metric any workload recorded, blank where it didn't
+	counterSet, gaugeSet := map[string]bool{}, map[string]bool{}
+	for _, r := range results {
+		for name := range r.Counters {
+			counterSet[name] = true
+		}
+		for name := range r.Gauges {
+			gaugeSet[name] = true
+		}
+	}
+	counters := slices.Sorted(maps.Keys(counterSet))
+	gauges := slices.Sorted(maps.Keys(gaugeSet))
+
+	cw := csv.NewWriter(w)
+	header := []string{"workload", "iterations", "concurrent_ns", "parallel_ns", "speedup", "max_speedup", "efficiency_pct"}
+	for _, name := range counters {
+		header = append(header, name+"_per_run")
+	}
+	cw.Write(append(header, gauges...))
+
+	// Unrounded averages
+	for _, r := range results {
+		record := []string{
+			r.Workload,
+			strconv.Itoa(len(r.Concurrent)),
+			strconv.FormatInt(r.Center(r.Concurrent).Nanoseconds(), 10),
+			strconv.FormatInt(r.Center(r.Parallel).Nanoseconds(), 10),
+			strconv.FormatFloat(r.Speedup(), 'f', 3, 64),
+			strconv.FormatFloat(r.MaxSpeedup(), 'f', 3, 64),
+			strconv.FormatFloat(r.Efficiency(), 'f', 1, 64),
+		}
+		for _, name := range counters {
+			record = append(record, csvMetric(r.Counters, name))
+		}
+		for _, name := range gauges {
+			record = append(record, csvMetric(r.Gauges, name))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvMetric(values map[string]float64, name string) string {
+	v, ok := values[name]
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Concurrency vs Parallelism Results</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2rem; }
+  table { border-collapse: collapse; }
+  th, td { border: 1px solid #ccc; padding: 0.3rem 0.7rem; text-align: right; }
+  th:first-child, td:first-child { text-align: left; }
+</style>
+</head>
+<body>
+<h1>Concurrency vs Parallelism Results</h1>
+<p>Benchmark {{.Build}}, recorded {{.Recorded}}<br>{{.Machine}}<br>{{.Env}}</p>
+<table>
+<tr><th>Workload</th><th>Runs</th><th>Concurrent</th><th>Parallel</th><th>Speedup</th><th>Max</th><th>Efficiency</th><th>CV</th><th>Metrics</th></tr>
+{{- range .Rows}}
+<tr><td>{{.Workload}}</td><td>{{.Iterations}}</td><td>{{.Concurrent}}</td><td>{{.Parallel}}</td><td>{{.Speedup}}x</td><td>{{.MaxSpeedup}}x</td><td>{{.Efficiency}}%</td><td>{{.CV}}</td><td>{{.Metrics}}</td></tr>
+{{- end}}
+</table>
+{{- if .Findings}}
+<h2>Findings</h2>
+<ul>
+{{- range .Findings}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+func WriteHTML(w io.Writer, f File, style NumberStyle) error {
+	results := f.RunnerResults()
+	data := struct {
+		Build, Recorded, Machine, Env string
+		Rows                          []row
+		Findings                      []string
+	}{
+		Build:    f.Metadata.Build.String(),
+		Recorded: f.Metadata.Timestamp.Format(time.RFC3339),
+		Machine:  machine(f.Metadata),
+		Env:      f.Metadata.Env.String(),
+		Rows:     rows(results, style),
+	}
+	if len(results) > 0 {
+		data.Findings = Findings(results)
+	}
+	return htmlReport.Execute(w, data)
+}
+
+// Render writes f in the named format.
+func Render(w io.Writer, f File, format string, style NumberStyle) error {
+	render, ok := Renderers[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(FormatNames(), ", "))
+	}
+	return render(w, f, style)
+}
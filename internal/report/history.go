@@ -0,0 +1,178 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"compare_process/internal/runner"
+)
+
+// historyZ is how many standard deviations from the historical mean a
+// value may sit and still count as within the distribution
+const historyZ = 2
+
+// historyMetric is one number compared against history, and how to print
+// it
+type historyMetric struct {
+	name   string
+	value  func(runner.Result) float64
+	format func(NumberStyle, float64) string
+}
+
+var historyMetrics = []historyMetric{
+	{"concurrent", func(r runner.Result) float64 { return float64(r.Center(r.Concurrent)) }, formatNanos},
+	{"parallel", func(r runner.Result) float64 { return float64(r.Center(r.Parallel)) }, formatNanos},
+	{"speedup", runner.Result.Speedup, func(s NumberStyle, v float64) string { return s.Number(v, 2) + "x" }},
+}
+
+func formatNanos(s NumberStyle, v float64) string { return s.Duration(time.Duration(v)) }
+
+// HistoryRow places one metric of one workload in the current run within
+// the same metric across historical runs.
+type HistoryRow struct {
+	Workload, Metric string
+	// N is how many historical runs had the workload at the same
+	// goroutine count and GOMAXPROCS, and Skipped how many had it at others
+	N, Skipped        int
+	Mean, StdDev      float64
+	Min, Max, Current float64
+	// Z is the current value's distance from the mean in standard
+	// deviations, NaN when history has no spread
+	Z float64
+	// Percentile is the share of historical values below the current one
+	Percentile float64
+	// Within is whether the current value fits the history: |Z| ≤ 2, or
+	// inside [Min, Max] when there are too few runs for a deviation
+	Within bool
+
+	format func(NumberStyle, float64) string
+}
+
+// CompareHistory compares every workload of current against the same
+// workload in each historical file, metric by metric. Only runs with the
+// same goroutine count and GOMAXPROCS compare; workloads no such run has
+// are left out.
+func CompareHistory(current File, history []File) []HistoryRow {
+	var rows []HistoryRow
+	for _, r := range current.RunnerResults() {
+		var past []runner.Result
+		skipped := 0
+		for _, f := range history {
+			for _, h := range f.RunnerResults() {
+				switch {
+				case h.Workload != r.Workload:
+				case h.Tasks != r.Tasks || h.Procs != r.Procs:
+					skipped++
+				default:
+					past = append(past, h)
+				}
+			}
+		}
+		if len(past) == 0 {
+			continue
+		}
+		for _, m := range historyMetrics {
+			values := make([]float64, len(past))
+			for i, h := range past {
+				values[i] = m.value(h)
+			}
+			row := historyRow(r.Workload, m, values, m.value(r))
+			row.Skipped = skipped
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func historyRow(workload string, m historyMetric, values []float64, current float64) HistoryRow {
+	row := HistoryRow{Workload: workload, Metric: m.name, N: len(values), Current: current,
+		Min: slices.Min(values), Max: slices.Max(values), Z: math.NaN(), format: m.format}
+	for _, v := range values {
+		row.Mean += v
+		if v < current {
+			row.Percentile++
+		}
+	}
+	row.Mean /= float64(len(values))
+	row.Percentile = row.Percentile / float64(len(values)) * 100
+	if len(values) > 1 {
+		for _, v := range values {
+			row.StdDev += (v - row.Mean) * (v - row.Mean)
+		}
+		row.StdDev = math.Sqrt(row.StdDev / float64(len(values)-1))
+	}
+
+	if row.StdDev > 0 {
+		row.Z = (current - row.Mean) / row.StdDev
+	}
+	if len(values) >= 3 && !math.IsNaN(row.Z) {
+		row.Within = math.Abs(row.Z) <= historyZ
+	} else {
+		row.Within = current >= row.Min && current <= row.Max
+	}
+	return row
+}
+
+// PrintHistory prints CompareHistory's rows as a table with a verdict per
+// row, then the rows outside their history.
+func PrintHistory(w io.Writer, rows []HistoryRow, runs int, style NumberStyle) {
+	fmt.Fprintln(w, "📚 Current Run vs History")
+	fmt.Fprintln(w, strings.Repeat("-", 60))
+	fmt.Fprintf(w, "   %d historical runs; within means |z| ≤ %d, or inside min-max under 3 runs\n\n", runs, historyZ)
+	if len(rows) == 0 {
+		fmt.Fprintf(w, "   No workload of the current run appears in the history\n\n")
+		return
+	}
+
+	fmt.Fprintf(w, "   Workload    | Metric     | N  | Historical mean ± sd     | Range                 | Current    | z     | Pct  | Verdict\n")
+	fmt.Fprintf(w, "   ------------|------------|----|--------------------------|-----------------------|------------|-------|------|--------\n")
+	var outside []HistoryRow
+	for _, r := range rows {
+		z := "n/a"
+		if !math.IsNaN(r.Z) {
+			z = style.Number(r.Z, 2)
+		}
+		verdict := "✅ within"
+		if !r.Within {
+			verdict = "⚠️  outside"
+			outside = append(outside, r)
+		}
+		fmt.Fprintf(w, "   %-11s | %-10s | %-2d | %-24s | %-21s | %-10s | %-5s | %3.0f%% | %s\n",
+			r.Workload, r.Metric, r.N,
+			r.format(style, r.Mean)+" ± "+r.format(style, r.StdDev),
+			r.format(style, r.Min)+" - "+r.format(style, r.Max),
+			r.format(style, r.Current), z, r.Percentile, verdict)
+	}
+
+	var skipped []string
+	for i, r := range rows {
+		if r.Skipped > 0 && (i == 0 || rows[i-1].Workload != r.Workload) {
+			skipped = append(skipped, fmt.Sprintf("%s %d", r.Workload, r.Skipped))
+		}
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "\n   Skipped for a different goroutine count or GOMAXPROCS: %s\n", strings.Join(skipped, ", "))
+	}
+	if len(outside) == 0 {
+		fmt.Fprintf(w, "\n   Every metric is within its historical distribution\n\n")
+		return
+	}
+	fmt.Fprintf(w, "\n   Outside the history:\n")
+	for _, r := range outside {
+		if math.IsNaN(r.Z) {
+			fmt.Fprintf(w, "   - %s %s: %s is outside the %d-run range\n", r.Workload, r.Metric, r.format(style, r.Current), r.N)
+			continue
+		}
+		direction := "above"
+		if r.Z < 0 {
+			direction = "below"
+		}
+		fmt.Fprintf(w, "   - %s %s: %s is %s sd %s the historical mean\n", r.Workload, r.Metric,
+			r.format(style, r.Current), style.Number(math.Abs(r.Z), 1), direction)
+	}
+	fmt.Fprintln(w)
+}
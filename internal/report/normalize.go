@@ -0,0 +1,127 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// Normalization divides a workload's parallel throughput by a property of
+// the machine it ran on, so a laptop and a 64-core server can be compared
+// on how well they use what they have rather than on raw durations.
+type Normalization string
+
+const (
+	NormalizeNone  Normalization = "none"
+	NormalizeGHz   Normalization = "ghz"   // per GHz of boost (or base) clock
+	NormalizeCore  Normalization = "core"  // per P used by the parallel runs
+	NormalizeScore Normalization = "score" // per 1000 points of all-core composite score
+)
+
+var normalizations = []Normalization{NormalizeNone, NormalizeGHz, NormalizeCore, NormalizeScore}
+
+func ParseNormalization(s string) (Normalization, error) {
+	for _, n := range normalizations {
+		if string(n) == s {
+			return n, nil
+		}
+	}
+	names := make([]string, len(normalizations))
+	for i, n := range normalizations {
+		names[i] = string(n)
+	}
+	return "", fmt.Errorf("unknown normalization %q (want %s)", s, strings.Join(names, ", "))
+}
+
+func (n Normalization) unit() string {
+	switch n {
+	case NormalizeGHz:
+		return "tasks/s/GHz"
+	case NormalizeCore:
+		return "tasks/s/core"
+	case NormalizeScore:
+		return "tasks/s/kpt"
+	}
+	return "tasks/s"
+}
+
+// divisor returns what f's throughputs are divided by, or false when f
+// lacks the data, e.g. no clock speed recorded.
+func (n Normalization) divisor(f File) (float64, bool) {
+	switch n {
+	case NormalizeGHz:
+		mhz := f.Metadata.CPU.BoostMHz
+		if mhz == 0 {
+			mhz = f.Metadata.CPU.BaseMHz
+		}
+		return mhz / 1000, mhz > 0
+	case NormalizeCore:
+		return float64(f.Metadata.NumCPU), f.Metadata.NumCPU > 0
+	case NormalizeScore:
+		components := ScoreComponents(f.RunnerResults(), nil)
+		if len(components) == 0 {
+			return 0, false
+		}
+		return CompositeScore(components, func(c ScoreComponent) float64 { return c.All }) / 1000, true
+	}
+	return 1, true
+}
+
+// HostLabel names the machine a result file came from: its hostname, or
+// the file name for files recorded before hostnames were.
+func HostLabel(f File, path string) string {
+	if f.Metadata.Hostname != "" {
+		return f.Metadata.Hostname
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// PrintAggregate lines up the same workloads from several hosts, with
+// parallel throughput normalized by n. labels name the hosts, one per file.
+func PrintAggregate(w io.Writer, files []File, labels []string, n Normalization, style NumberStyle) {
+	fmt.Fprintln(w, "🌐 Cross-Machine Comparison")
+	fmt.Fprintln(w, strings.Repeat("-", 60))
+	fmt.Fprintf(w, "   Normalization: %s\n\n", n)
+
+	fmt.Fprintf(w, "   Workload | Host             | Cores | Throughput             | Speedup | Efficiency\n")
+	fmt.Fprintf(w, "   ---------|------------------|-------|------------------------|---------|-----------\n")
+
+	// Workloads in first-seen order across files
+	var workloadOrder []string
+	seen := map[string]bool{}
+	for _, f := range files {
+		for _, r := range f.Results {
+			if !seen[r.Workload] {
+				seen[r.Workload] = true
+				workloadOrder = append(workloadOrder, r.Workload)
+			}
+		}
+	}
+
+	for _, workload := range workloadOrder {
+		for i, f := range files {
+			divisor, ok := n.divisor(f)
+			for _, r := range f.RunnerResults() {
+				if r.Workload != workload {
+					continue
+				}
+				throughput := "n/a"
+				if avg := r.Center(r.Parallel); ok && avg > 0 {
+					rate := float64(r.Tasks) / avg.Seconds() / divisor
+					throughput = style.Number(rate, 1) + " " + n.unit()
+				}
+				efficiency := r.Efficiency()
+				if math.IsNaN(efficiency) || math.IsInf(efficiency, 0) {
+					efficiency = 0
+				}
+				fmt.Fprintf(w, "   %-8s | %-16s | %-5d | %-22s | %6sx | %8s%%\n",
+					workload, labels[i], r.Procs, throughput, style.Number(r.Speedup(), 2), style.Number(efficiency, 1))
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n   Note: efficiency (speedup ÷ max speedup) is comparable across hosts as is;\n")
+	fmt.Fprintf(w, "   throughput only after normalization, and n/a means a host lacks the data\n\n")
+}
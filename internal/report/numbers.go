@@ -0,0 +1,143 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NumberStyle controls how durations and numbers are written in reports.
+// The zero value keeps Go's defaults: per-value duration units and plain
+// "1234.5" numbers.
+type NumberStyle struct {
+	// Unit fixes the duration unit (s, ms, us or ns) so columns align and
+	// parse the same on fast and slow machines; "" picks one per value.
+	Unit string
+	// Precision is the number of decimals for fixed-unit durations.
+	Precision int
+
+	group, decimal string
+}
+
+var durationUnits = map[string]struct {
+	size  time.Duration
+	label string
+}{
+	"s":  {time.Second, "s"},
+	"ms": {time.Millisecond, "ms"},
+	"us": {time.Microsecond, "µs"},
+	"µs": {time.Microsecond, "µs"},
+	"ns": {time.Nanosecond, "ns"},
+}
+
+// localeSeparators maps a language, or a language_REGION where it differs
+// from the language, to its digit group and decimal separators.
+var localeSeparators = map[string][2]string{
+	"en":    {",", "."},
+	"ja":    {",", "."},
+	"zh":    {",", "."},
+	"de":    {".", ","},
+	"es":    {".", ","},
+	"it":    {".", ","},
+	"nl":    {".", ","},
+	"pt":    {".", ","},
+	"fr":    {" ", ","},
+	"ru":    {" ", ","},
+	"de_CH": {"'", "."},
+}
+
+// NewNumberStyle validates unit and locale. locale is a POSIX-style name
+// such as "de_DE.UTF-8", "auto" to read LC_ALL/LC_NUMERIC/LANG, or "" for
+// no grouping and a "." decimal point.
+func NewNumberStyle(unit string, precision int, locale string) (NumberStyle, error) {
+	s := NumberStyle{Unit: unit, Precision: precision, decimal: "."}
+
+	if _, ok := durationUnits[unit]; unit != "" && !ok {
+		return s, fmt.Errorf("unknown duration unit %q (want s, ms, us or ns)", unit)
+	}
+	if precision < 0 {
+		return s, fmt.Errorf("precision must not be negative, got %d", precision)
+	}
+
+	if locale == "auto" {
+		locale = firstNonEmpty(os.Getenv("LC_ALL"), os.Getenv("LC_NUMERIC"), os.Getenv("LANG"))
+		if locale == "C" || locale == "POSIX" {
+			locale = ""
+		}
+	}
+	if locale == "" {
+		return s, nil
+	}
+
+	name, _, _ := strings.Cut(locale, ".")
+	lang, _, _ := strings.Cut(name, "_")
+	seps, ok := localeSeparators[name]
+	if !ok {
+		seps, ok = localeSeparators[lang]
+	}
+	if !ok {
+		return s, fmt.Errorf("unsupported locale %q", locale)
+	}
+	s.group, s.decimal = seps[0], seps[1]
+	return s, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Duration formats d in the fixed unit, or Go's own format rounded to the
+// microsecond when no unit is set.
+func (s NumberStyle) Duration(d time.Duration) string {
+	unit, ok := durationUnits[s.Unit]
+	if !ok {
+		str := d.Round(time.Microsecond).String()
+		if s.decimal != "" && s.decimal != "." {
+			str = strings.Replace(str, ".", s.decimal, 1)
+		}
+		return str
+	}
+	return s.Number(float64(d)/float64(unit.size), s.Precision) + unit.label
+}
+
+// Number formats v with prec decimals and the locale's separators.
+func (s NumberStyle) Number(v float64, prec int) string {
+	str := strconv.FormatFloat(v, 'f', prec, 64)
+
+	sign := ""
+	if strings.HasPrefix(str, "-") {
+		sign, str = "-", str[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(str, ".")
+
+	if s.group != "" && len(whole) > 3 {
+		var b strings.Builder
+		lead := len(whole) % 3
+		if lead > 0 {
+			b.WriteString(whole[:lead])
+		}
+		for i := lead; i < len(whole); i += 3 {
+			if b.Len() > 0 {
+				b.WriteString(s.group)
+			}
+			b.WriteString(whole[i : i+3])
+		}
+		whole = b.String()
+	}
+
+	if !hasFrac {
+		return sign + whole
+	}
+	decimal := s.decimal
+	if decimal == "" {
+		decimal = "."
+	}
+	return sign + whole + decimal + frac
+}
@@ -0,0 +1,219 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"compare_process/internal/runner"
+	"compare_process/internal/workloads"
+)
+
+// Reporter receives a benchmark run as it happens: Start before the first
+// suite, SuiteResult after each suite with what it added, and Finish with
+// the whole run once the suites are done.
+type Reporter interface {
+	Start(meta Metadata) error
+	SuiteResult(suite Suite) error
+	Finish(run Run) error
+}
+
+// Suite is what one suite added to a run: its results, the cooldown taken
+// before it, its scheduler latency, the failure that stopped it and its
+// scalability matrix, each empty when it had none. An isolated suite
+// brings whatever its child recorded.
+type Suite struct {
+	Name      string
+	Results   []runner.Result
+	Cooldowns []runner.CooldownEvent
+	Sched     []runner.SuiteSched
+	Failures  []SuiteFailure
+	Scaling   []runner.ScalingCell
+}
+
+// Run is a finished run: its result file, plus what only a live run has.
+type Run struct {
+	File
+	Ceilings *workloads.Ceilings
+}
+
+// Reporters build a Reporter writing to w, keyed by the name -format
+// accepts. A new output format only needs an entry here.
+var Reporters = map[string]func(w io.Writer, style NumberStyle) Reporter{
+	"console":    func(w io.Writer, style NumberStyle) Reporter { return consoleReporter{w, style} },
+	"json":       func(w io.Writer, _ NumberStyle) Reporter { return finishReporter{w, encodeJSON} },
+	"csv":        func(w io.Writer, _ NumberStyle) Reporter { return &csvReporter{w: w} },
+	"html":       func(w io.Writer, style NumberStyle) Reporter { return finishReporter{w, renderWith(WriteHTML, style)} },
+	"prometheus": func(w io.Writer, _ NumberStyle) Reporter { return finishReporter{w, encodePrometheus} },
+}
+
+// ReporterNames lists the Reporters keys in a stable order for help text.
+func ReporterNames() []string {
+	names := make([]string, 0, len(Reporters))
+	for name := range Reporters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func NewReporter(name string, w io.Writer, style NumberStyle) (Reporter, error) {
+	build, ok := Reporters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (want %s)", name, strings.Join(ReporterNames(), ", "))
+	}
+	return build(w, style), nil
+}
+
+// consoleReporter prints the end-of-run summary tables; the suites print
+// their own sections as they go.
+type consoleReporter struct {
+	w     io.Writer
+	style NumberStyle
+}
+
+func (consoleReporter) Start(Metadata) error    { return nil }
+func (consoleReporter) SuiteResult(Suite) error { return nil }
+
+func (c consoleReporter) Finish(run Run) error {
+	results := run.RunnerResults()
+	PrintSummary(c.w, results, c.style)
+	PrintAnomalies(c.w, results, run.Metadata.CPU)
+	if _, ok := runner.FitUSL(run.Scaling); ok {
+		fmt.Fprintln(c.w, "📈 Scalability Model (Universal Scalability Law)")
+		fmt.Fprintln(c.w, strings.Repeat("=", 60))
+		PrintScalingModel(c.w, run.Scaling)
+		fmt.Fprintln(c.w)
+	}
+	PrintCompositeScore(c.w, results, run.Ceilings)
+	PrintSchedLatency(c.w, run.Sched, results)
+	PrintFailures(c.w, run.Failures)
+	return nil
+}
+
+// finishReporter writes the whole run in one go once it is done.
+type finishReporter struct {
+	w      io.Writer
+	encode func(io.Writer, Run) error
+}
+
+func (finishReporter) Start(Metadata) error    { return nil }
+func (finishReporter) SuiteResult(Suite) error { return nil }
+func (r finishReporter) Finish(run Run) error  { return r.encode(r.w, run) }
+
+func renderWith(render func(io.Writer, File, NumberStyle) error, style NumberStyle) func(io.Writer, Run) error {
+	return func(w io.Writer, run Run) error { return render(w, run.File, style) }
+}
+
+func encodeJSON(w io.Writer, run Run) error { return EncodeJSON(w, run.File) }
+
+// csvReporter appends each suite's iterations as the suite finishes, one
+// write per suite, so a run that dies midway keeps every finished suite.
+// A file is synced after every suite.
+type csvReporter struct {
+	w io.Writer
+}
+
+func (r *csvReporter) Start(Metadata) error {
+	if err := encodeIterationRows(r.w, [][]string{iterationsHeader}); err != nil {
+		return err
+	}
+	return r.sync()
+}
+
+func (r *csvReporter) SuiteResult(suite Suite) error {
+	if err := encodeIterationRows(r.w, iterationRows(suite.Results)); err != nil {
+		return err
+	}
+	return r.sync()
+}
+
+func (r *csvReporter) Finish(Run) error { return nil }
+
+func (r *csvReporter) sync() error {
+	if f, ok := r.w.(*os.File); ok && f != os.Stdout {
+		return f.Sync()
+	}
+	return nil
+}
+
+// jsonFileReporter rewrites its file after every suite with the run so
+// far, atomically, so a run that dies midway leaves a readable file of
+// every finished suite. Finish writes the complete run.
+type jsonFileReporter struct {
+	path      string
+	meta      Metadata
+	results   []runner.Result
+	cooldowns []runner.CooldownEvent
+	sched     []runner.SuiteSched
+	failures  []SuiteFailure
+	scaling   []runner.ScalingCell
+}
+
+// NewJSONFileReporter returns the json reporter for a file at path. It
+// replaces the file rather than writing to an open one, so it needs the
+// path instead of a writer.
+func NewJSONFileReporter(path string) Reporter {
+	return &jsonFileReporter{path: path}
+}
+
+func (r *jsonFileReporter) Start(meta Metadata) error {
+	r.meta = meta
+	return nil
+}
+
+func (r *jsonFileReporter) SuiteResult(suite Suite) error {
+	r.results = append(r.results, suite.Results...)
+	r.cooldowns = append(r.cooldowns, suite.Cooldowns...)
+	r.sched = append(r.sched, suite.Sched...)
+	r.failures = append(r.failures, suite.Failures...)
+	r.scaling = append(r.scaling, suite.Scaling...)
+	return WriteJSON(r.path, NewFile(r.meta, r.results, r.cooldowns, r.sched, r.failures, r.scaling))
+}
+
+func (r *jsonFileReporter) Finish(run Run) error { return WriteJSON(r.path, run.File) }
+
+// encodePrometheus writes each result as gauges in the Prometheus text
+// exposition format, for a node exporter's textfile collector or a
+// Pushgateway.
+func encodePrometheus(w io.Writer, run Run) error {
+	bw := bufio.NewWriter(w)
+	gauge := func(name, help string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+	results := run.RunnerResults()
+
+	// -robust centers every result of a run on its median
+	center := "Average"
+	if slices.ContainsFunc(results, func(r runner.Result) bool { return r.Robust }) {
+		center = "Median"
+	}
+	gauge("bench_duration_seconds", center+" time of one run of the workload in each mode.")
+	for _, r := range results {
+		fmt.Fprintf(bw, "bench_duration_seconds{workload=%s,mode=\"concurrent\"} %g\n", promLabel(r.Workload), r.Center(r.Concurrent).Seconds())
+		fmt.Fprintf(bw, "bench_duration_seconds{workload=%s,mode=\"parallel\"} %g\n", promLabel(r.Workload), r.Center(r.Parallel).Seconds())
+	}
+	gauge("bench_speedup_ratio", "Concurrent time over parallel time.")
+	for _, r := range results {
+		fmt.Fprintf(bw, "bench_speedup_ratio{workload=%s} %g\n", promLabel(r.Workload), r.Speedup())
+	}
+	gauge("bench_efficiency_ratio", "Speedup as a fraction of the best the cores allow.")
+	for _, r := range results {
+		fmt.Fprintf(bw, "bench_efficiency_ratio{workload=%s} %g\n", promLabel(r.Workload), r.Efficiency()/100)
+	}
+	gauge("bench_gomaxprocs", "GOMAXPROCS of the parallel runs.")
+	for _, r := range results {
+		fmt.Fprintf(bw, "bench_gomaxprocs{workload=%s} %d\n", promLabel(r.Workload), r.Procs)
+	}
+	gauge("bench_suite_failures", "Suites a panic or resource cap stopped.")
+	fmt.Fprintf(bw, "bench_suite_failures %d\n", len(run.Failures))
+	return bw.Flush()
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promLabel quotes v as a Prometheus label value.
+func promLabel(v string) string { return `"` + promEscaper.Replace(v) + `"` }
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"compare_process/internal/runner"
+)
+
+func TestNewReporter(t *testing.T) {
+	for _, name := range ReporterNames() {
+		if _, err := NewReporter(name, &bytes.Buffer{}, NumberStyle{}); err != nil {
+			t.Errorf("NewReporter(%q): %v", name, err)
+		}
+	}
+	if _, err := NewReporter("xml", &bytes.Buffer{}, NumberStyle{}); err == nil {
+		t.Error("NewReporter accepted an unknown format")
+	}
+}
+
+func TestPrometheus(t *testing.T) {
+	r := runner.Result{Workload: `CPU "primes"`, Tasks: 4, Procs: 4, Concurrent: ms(40, 40), Parallel: ms(10, 10)}
+	run := Run{File: NewFile(Metadata{}, []runner.Result{r}, nil, nil, []SuiteFailure{{Suite: "io"}}, nil)}
+
+	var buf bytes.Buffer
+	if err := encodePrometheus(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# HELP bench_duration_seconds Average time of one run of the workload in each mode.",
+		"# TYPE bench_duration_seconds gauge",
+		`bench_duration_seconds{workload="CPU \"primes\"",mode="concurrent"} 0.04`,
+		`bench_duration_seconds{workload="CPU \"primes\"",mode="parallel"} 0.01`,
+		`bench_speedup_ratio{workload="CPU \"primes\""} 4`,
+		`bench_efficiency_ratio{workload="CPU \"primes\""} 1`,
+		`bench_gomaxprocs{workload="CPU \"primes\""} 4`,
+		"bench_suite_failures 1",
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("output lacks %q:\n%s", line, buf.String())
+		}
+	}
+
+	// -robust reports medians, and says so
+	r.Robust = true
+	r.Concurrent = ms(40, 40, 100)
+	run.File = NewFile(Metadata{}, []runner.Result{r}, nil, nil, nil, nil)
+	buf.Reset()
+	if err := encodePrometheus(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# HELP bench_duration_seconds Median time of one run of the workload in each mode.",
+		`bench_duration_seconds{workload="CPU \"primes\"",mode="concurrent"} 0.04`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("robust output lacks %q:\n%s", line, buf.String())
+		}
+	}
+}
+
+// TestCSVReporter checks each suite's rows follow the header as the suite
+// finishes.
+func TestCSVReporter(t *testing.T) {
+	var buf bytes.Buffer
+	rep, err := NewReporter("csv", &buf, NumberStyle{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rep.Start(Metadata{}); err != nil {
+		t.Fatal(err)
+	}
+	first := runner.Result{Workload: "CPU", Tasks: 1, Procs: 2, Concurrent: ms(2), Parallel: ms(1)}
+	if err := rep.SuiteResult(Suite{Name: "cpu", Results: []runner.Result{first}}); err != nil {
+		t.Fatal(err)
+	}
+	second := first
+	second.Workload = "I/O"
+	if err := rep.SuiteResult(Suite{Name: "io", Results: []runner.Result{second}}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		iterationsHeader,
+		{"CPU", "concurrent", "1", "1", "1", "2000000", "false"},
+		{"CPU", "parallel", "2", "1", "1", "1000000", "false"},
+		{"I/O", "concurrent", "1", "1", "1", "2000000", "false"},
+		{"I/O", "parallel", "2", "1", "1", "1000000", "false"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows =\n%v\nwant\n%v", rows, want)
+	}
+}
+
+// TestJSONFileReporter checks the file holds every finished suite after
+// each one, so a run that dies midway leaves them readable.
+func TestJSONFileReporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	rep := NewJSONFileReporter(path)
+	meta := Metadata{Hostname: "bench-host", NumCPU: 2}
+	if err := rep.Start(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := sampleResult()
+	if err := rep.SuiteResult(Suite{
+		Name:      "cpu",
+		Results:   []runner.Result{cpu},
+		Cooldowns: []runner.CooldownEvent{{Before: "cpu", SleptNS: 1e9}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	file, err := ReadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Results) != 1 || len(file.Cooldowns) != 1 || file.Metadata.Hostname != "bench-host" {
+		t.Fatalf("after the first suite the file has %d results, %d cooldowns and host %q",
+			len(file.Results), len(file.Cooldowns), file.Metadata.Hostname)
+	}
+
+	failure := SuiteFailure{Suite: "io", Reason: "panic: boom"}
+	if err := rep.SuiteResult(Suite{Name: "io", Failures: []SuiteFailure{failure}}); err != nil {
+		t.Fatal(err)
+	}
+	if file, err = ReadJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := file.RunnerResults(); !reflect.DeepEqual(got, []runner.Result{cpu}) {
+		t.Errorf("after the second suite results read back as %+v", got)
+	}
+	if !reflect.DeepEqual(file.Failures, []SuiteFailure{failure}) || len(file.Cooldowns) != 1 {
+		t.Errorf("after the second suite the file has failures %+v and %d cooldowns", file.Failures, len(file.Cooldowns))
+	}
+
+	// Finish writes the run it is given
+	done := NewFile(meta, nil, nil, nil, nil, nil)
+	if err := rep.Finish(Run{File: done}); err != nil {
+		t.Fatal(err)
+	}
+	if file, err = ReadJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Results) != 0 || len(file.Failures) != 0 {
+		t.Errorf("Finish left %d results and %d failures in the file", len(file.Results), len(file.Failures))
+	}
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"compare_process/internal/runner"
+)
+
+// heatShades go from the slowest cell to the fastest
+var heatShades = []string{"  ", "░░", "▒▒", "▓▓", "██"}
+
+// PrintScalingHeatmap lays the scalability matrix out with goroutine
+// counts down and GOMAXPROCS across, each cell its speedup shaded by how
+// close it comes to the best one. Oversubscription shows up as rows that
+// stop brightening, or darken, to the right of the core count.
+func PrintScalingHeatmap(w io.Writer, cells []runner.ScalingCell) {
+	if len(cells) == 0 {
+		return
+	}
+	goroutines, procs := scalingAxes(cells)
+	best := 0.0
+	for _, c := range cells {
+		best = max(best, c.Speedup)
+	}
+
+	fmt.Fprintf(w, "   Goroutines \\ GOMAXPROCS\n")
+	fmt.Fprintf(w, "   %-10s", "")
+	for _, p := range procs {
+		fmt.Fprintf(w, " | %-9d", p)
+	}
+	fmt.Fprintf(w, "\n   %s", strings.Repeat("-", 10))
+	for range procs {
+		fmt.Fprintf(w, "-|-%s", strings.Repeat("-", 9))
+	}
+	fmt.Fprintln(w)
+	for _, g := range goroutines {
+		fmt.Fprintf(w, "   %-10d", g)
+		for _, p := range procs {
+			c, ok := scalingCell(cells, g, p)
+			if !ok {
+				fmt.Fprintf(w, " | %-9s", "")
+				continue
+			}
+			fmt.Fprintf(w, " | %s %5.2fx", heatShade(c.Speedup, best), c.Speedup)
+		}
+		fmt.Fprintln(w)
+	}
+
+	base := cells[0]
+	fmt.Fprintf(w, "\n   Speedup over %d goroutine at GOMAXPROCS=%d (%v); fuller is faster\n",
+		base.Goroutines, base.Procs, base.Elapsed.Round(time.Microsecond))
+}
+
+// scalingAxes returns the distinct goroutine counts and GOMAXPROCS
+// values of cells, in ascending order.
+func scalingAxes(cells []runner.ScalingCell) (goroutines, procs []int) {
+	for _, c := range cells {
+		if !slices.Contains(goroutines, c.Goroutines) {
+			goroutines = append(goroutines, c.Goroutines)
+		}
+		if !slices.Contains(procs, c.Procs) {
+			procs = append(procs, c.Procs)
+		}
+	}
+	slices.Sort(goroutines)
+	slices.Sort(procs)
+	return goroutines, procs
+}
+
+func scalingCell(cells []runner.ScalingCell, goroutines, procs int) (runner.ScalingCell, bool) {
+	for _, c := range cells {
+		if c.Goroutines == goroutines && c.Procs == procs {
+			return c, true
+		}
+	}
+	return runner.ScalingCell{}, false
+}
+
+func heatShade(speedup, best float64) string {
+	if best <= 0 {
+		return heatShades[0]
+	}
+	i := int(speedup / best * float64(len(heatShades)-1))
+	return heatShades[min(max(i, 0), len(heatShades)-1)]
+}
+
+// PrintScalingModel fits the Universal Scalability Law to the scalability
+// matrix and says whether contention or crosstalk limits the workload,
+// with the goroutine count the model predicts is fastest.
+func PrintScalingModel(w io.Writer, cells []runner.ScalingCell) {
+	model, ok := runner.FitUSL(cells)
+	if !ok {
+		return
+	}
+	procs := 0
+	for _, c := range cells {
+		procs = max(procs, c.Procs)
+	}
+
+	fmt.Fprintf(w, "   USL fit at GOMAXPROCS=%d: contention σ=%.4f, coherence κ=%.5f\n", procs, model.Sigma, model.Kappa)
+	switch {
+	case model.Sigma == 0 && model.Kappa == 0:
+		fmt.Fprintf(w, "   No contention or crosstalk measured: throughput grows with goroutines\n")
+	case model.Kappa*float64(procs) > model.Sigma:
+		fmt.Fprintf(w, "   Limited mostly by crosstalk: goroutines pay to keep shared state coherent\n")
+	default:
+		fmt.Fprintf(w, "   Limited mostly by contention: goroutines queue for a shared resource\n")
+	}
+	if peak, ok := model.Peak(); ok {
+		n := max(1, int(math.Round(peak)))
+		fmt.Fprintf(w, "   Predicted optimal concurrency: %d goroutines (%.2fx)\n", n, model.Speedup(float64(n)))
+	} else if model.Sigma > 0 {
+		fmt.Fprintf(w, "   Throughput levels off toward %.2fx without turning down; more goroutines only add memory\n", 1/model.Sigma)
+	}
+}
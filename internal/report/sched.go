@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"compare_process/internal/runner"
+)
+
+// schedBands are the upper bounds of the latency bands the scheduler
+// histograms are folded into; the last band is open-ended
+var schedBands = []time.Duration{time.Microsecond, 10 * time.Microsecond, 100 * time.Microsecond, time.Millisecond, 10 * time.Millisecond}
+
+const (
+	schedHeader = "<1µs  | 1-10µs | 10-100µs | 0.1-1ms | 1-10ms | ≥10ms  | p99"
+	schedRule   = "------|--------|----------|---------|--------|--------|----------"
+)
+
+// schedWidths are the widths of the band columns in schedHeader
+var schedWidths = []int{5, 6, 8, 7, 6, 6}
+
+// PrintSchedLatency shows how long goroutines waited runnable before they
+// ran: per suite, then per GOMAXPROCS setting across every recorded
+// result, which is where run-queue delay at GOMAXPROCS=1 shows up.
+func PrintSchedLatency(w io.Writer, suites []runner.SuiteSched, results []runner.Result) {
+	var concurrent, parallel runner.Histogram
+	procs := 0
+	for _, r := range results {
+		concurrent = concurrent.Merge(r.ConcurrentRuntime.SchedLatency)
+		parallel = parallel.Merge(r.ParallelRuntime.SchedLatency)
+		procs = max(procs, r.Procs)
+	}
+	if len(suites) == 0 && concurrent.Total()+parallel.Total() == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "⏱️ Scheduler Latency (/sched/latencies:seconds)")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "   Share of samples by time spent runnable before running:")
+
+	if len(suites) > 0 {
+		fmt.Fprintf(w, "\n   Suite        | %s\n", schedHeader)
+		fmt.Fprintf(w, "   -------------|-%s\n", schedRule)
+		for _, s := range suites {
+			fmt.Fprintf(w, "   %-12s | %s\n", s.Suite, schedRow(s.SchedLatency))
+		}
+	}
+
+	if concurrent.Total()+parallel.Total() > 0 {
+		fmt.Fprintf(w, "\n   GOMAXPROCS   | %s\n", schedHeader)
+		fmt.Fprintf(w, "   -------------|-%s\n", schedRule)
+		fmt.Fprintf(w, "   %-12d | %s\n", 1, schedRow(concurrent))
+		fmt.Fprintf(w, "   %-12d | %s\n", procs, schedRow(parallel))
+		fmt.Fprintln(w, "\n   With one P, every runnable goroutine queues behind the running one;")
+		fmt.Fprintln(w, "   more Ps drain the run queues in parallel and shift samples left.")
+	}
+	fmt.Fprintln(w)
+}
+
+// schedRow is one histogram folded into schedBands as percentages, with
+// its p99 and sample count.
+func schedRow(h runner.Histogram) string {
+	total := h.Total()
+	if total == 0 {
+		return "no samples"
+	}
+
+	counts := make([]uint64, len(schedBands)+1)
+	for _, b := range h {
+		band := len(schedBands)
+		for i, bound := range schedBands {
+			if time.Duration(b.LoNS) < bound {
+				band = i
+				break
+			}
+		}
+		counts[band] += b.Count
+	}
+
+	cells := make([]string, len(counts))
+	for i, c := range counts {
+		cells[i] = fmt.Sprintf("%*.0f%%", schedWidths[i]-1, float64(c)/float64(total)*100)
+	}
+	return fmt.Sprintf("%s | %v (%d)", strings.Join(cells, " | "), h.Quantile(0.99).Round(time.Microsecond), total)
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+
+	"compare_process/internal/runner"
+	"compare_process/internal/workloads"
+)
+
+// Reference single-core rates that score 1000 points. They come from one
+// core of the x86-64 cloud VM the defaults were calibrated on.
+const (
+	refPrimeTasksPerSec = 90.0
+	refIntOpsPerSec     = 4.7e9
+	refFloatOpsPerSec   = 2.9e9
+	refVectorOpsPerSec  = 1.9e9
+)
+
+// ScoreComponent is one throughput measurement that feeds the composite
+// score, taken on one core and on all cores.
+type ScoreComponent struct {
+	Name        string
+	Single, All float64
+	Reference   float64
+}
+
+// ScoreComponents gathers the compute-bound results of a run. I/O and
+// mixed workloads are left out: their throughput follows the goroutine
+// count rather than the hardware. ceilings may be nil.
+func ScoreComponents(results []runner.Result, ceilings *workloads.Ceilings) []ScoreComponent {
+	var components []ScoreComponent
+
+	for _, r := range results {
+		if r.Workload == "CPU" {
+			tasks := float64(r.Tasks)
+			components = append(components, ScoreComponent{
+				Name:      "Prime",
+				Single:    tasks / r.Center(r.Concurrent).Seconds(),
+				All:       tasks / r.Center(r.Parallel).Seconds(),
+				Reference: refPrimeTasksPerSec,
+			})
+		}
+	}
+
+	if c := ceilings; c != nil {
+		components = append(components,
+			ScoreComponent{"Integer", c.IntSingle, c.IntAll, refIntOpsPerSec},
+			ScoreComponent{"Float", c.FloatSingle, c.FloatAll, refFloatOpsPerSec},
+			ScoreComponent{"Saxpy", c.VectorSingle, c.VectorAll, refVectorOpsPerSec},
+		)
+	}
+	return components
+}
+
+// CompositeScore is 1000 × the geometric mean of each component's rate
+// relative to its reference, like Geekbench's presentation.
+func CompositeScore(components []ScoreComponent, rate func(ScoreComponent) float64) float64 {
+	logSum := 0.0
+	for _, c := range components {
+		logSum += math.Log(rate(c) / c.Reference)
+	}
+	return 1000 * math.Exp(logSum/float64(len(components)))
+}
+
+func PrintCompositeScore(w io.Writer, results []runner.Result, ceilings *workloads.Ceilings) {
+	procs := runtime.NumCPU()
+	if len(results) > 0 {
+		procs = results[0].Procs
+	}
+
+	components := ScoreComponents(results, ceilings)
+	if len(components) == 0 {
+		return
+	}
+
+	single := CompositeScore(components, func(c ScoreComponent) float64 { return c.Single })
+	all := CompositeScore(components, func(c ScoreComponent) float64 { return c.All })
+
+	fmt.Fprintln(w, "🏆 Composite Machine Score")
+	fmt.Fprintln(w, strings.Repeat("-", 60))
+	fmt.Fprintf(w, "   Per-core score:  %.0f\n", single)
+	fmt.Fprintf(w, "   All-core score:  %.0f (%.2fx per-core on %d cores)\n", all, all/single, procs)
+
+	names := make([]string, len(components))
+	for i, c := range components {
+		names[i] = c.Name
+	}
+	fmt.Fprintf(w, "   Based on: %s\n", strings.Join(names, ", "))
+	if ceilings == nil {
+		fmt.Fprintf(w, "   Note: run with -arith to include the integer/float/saxpy ceilings\n")
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,211 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"compare_process/internal/runner"
+)
+
+func PrintSummary(w io.Writer, results []runner.Result, style NumberStyle) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "📋 Summary")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// Durations get a column as wide as the longest one; with a fixed unit
+	// they're right-aligned so the decimal separators line up
+	table := rows(results, style)
+	width := 10
+	for _, r := range table {
+		width = max(width, utf8.RuneCountInString(r.Concurrent), utf8.RuneCountInString(r.Parallel))
+	}
+	pad := func(s string) string {
+		gap := strings.Repeat(" ", width-utf8.RuneCountInString(s))
+		if style.Unit != "" {
+			return gap + s
+		}
+		return s + gap
+	}
+
+	fmt.Fprintf(w, "   Workload | %s | %s | Speedup | Max     | Efficiency | CV\n", pad("Concurrent"), pad("Parallel"))
+	fmt.Fprintf(w, "   ---------|-%s-|-%s-|---------|---------|------------|-------\n",
+		strings.Repeat("-", width), strings.Repeat("-", width))
+	for _, r := range table {
+		fmt.Fprintf(w, "   %-8s | %s | %s | %6sx | %6sx | %9s%% | %s\n",
+			r.Workload, pad(r.Concurrent), pad(r.Parallel), r.Speedup, r.MaxSpeedup, r.Efficiency, r.CV)
+	}
+
+	fmt.Fprintln(w, "\n   Throughput per second:")
+	fmt.Fprintf(w, "   Workload | Work         | Concurrent   | Parallel\n")
+	fmt.Fprintf(w, "   ---------|--------------|--------------|-------------\n")
+	for _, r := range results {
+		for i, rate := range r.Rates() {
+			name := r.Workload
+			if i > 0 {
+				name = ""
+			}
+			fmt.Fprintf(w, "   %-8s | %-12s | %12s | %12s\n", name, rate.Unit, style.Number(rate.Concurrent, 1), style.Number(rate.Parallel, 1))
+		}
+	}
+
+	if hasAllocs(results) {
+		fmt.Fprintln(w, "\n   Allocations per task:")
+		fmt.Fprintf(w, "   Workload | Concurrent               | Parallel\n")
+		fmt.Fprintf(w, "   ---------|--------------------------|--------------------------\n")
+		for _, r := range results {
+			fmt.Fprintf(w, "   %-8s | %-24s | %s\n", r.Workload,
+				allocsCell(r.ConcurrentAllocs, style), allocsCell(r.ParallelAllocs, style))
+		}
+	}
+
+	if hasRuntime(results) {
+		fmt.Fprintln(w, "\n   Runtime histograms (runtime/metrics; p50/p99/max as bucket bounds, samples):")
+		fmt.Fprintf(w, "   Workload | Mode       | Scheduler latency                | GC pauses\n")
+		fmt.Fprintf(w, "   ---------|------------|----------------------------------|----------------------------------\n")
+		for _, r := range results {
+			fmt.Fprintf(w, "   %-8s | concurrent | %-32s | %s\n", r.Workload,
+				histogramCell(r.ConcurrentRuntime.SchedLatency), histogramCell(r.ConcurrentRuntime.GCPauses))
+			fmt.Fprintf(w, "   %-8s | parallel   | %-32s | %s\n", "",
+				histogramCell(r.ParallelRuntime.SchedLatency), histogramCell(r.ParallelRuntime.GCPauses))
+		}
+	}
+
+	if hasMicro(results) {
+		fmt.Fprintln(w, "\n   Macro vs micro timing (wall time per wave vs mean time per operation):")
+		fmt.Fprintf(w, "   Workload | Wall speedup | ns/op concurrent | ns/op parallel | Op speedup\n")
+		fmt.Fprintf(w, "   ---------|--------------|------------------|----------------|-----------\n")
+		for _, r := range results {
+			if r.ParallelMicro.Ops == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "   %-8s | %11sx | %16s | %14s | %sx\n", r.Workload,
+				style.Number(r.Speedup(), 2), style.Number(float64(r.ConcurrentMicro.PerOp()), 0),
+				style.Number(float64(r.ParallelMicro.PerOp()), 0), style.Number(r.MicroSpeedup(), 2))
+		}
+	}
+
+	if hasMetrics(results) {
+		fmt.Fprintln(w, "\n   Workload metrics:")
+		for _, r := range results {
+			if line := metricsLine(r); line != "" {
+				fmt.Fprintf(w, "   %-8s   %s\n", r.Workload, line)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "\n   Findings:")
+	for _, f := range Findings(results) {
+		fmt.Fprintf(w, "   • %s\n", f)
+	}
+	fmt.Fprintln(w)
+}
+
+func hasAllocs(results []runner.Result) bool {
+	for _, r := range results {
+		if r.ConcurrentAllocs.Tasks > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMicro(results []runner.Result) bool {
+	for _, r := range results {
+		if r.ParallelMicro.Ops > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRuntime(results []runner.Result) bool {
+	for _, r := range results {
+		if r.ConcurrentRuntime.SchedLatency.Total() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func histogramCell(h runner.Histogram) string {
+	if h.Total() == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%v/%v/%v (%d)", h.Quantile(0.5), h.Quantile(0.99), h.Quantile(1), h.Total())
+}
+
+func allocsCell(a runner.Allocs, style NumberStyle) string {
+	if a.Tasks == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%s B/op, %s allocs/op", style.Number(a.BytesPerOp(), 0), style.Number(a.AllocsPerOp(), 1))
+}
+
+func hasMetrics(results []runner.Result) bool {
+	for _, r := range results {
+		if len(r.Counters)+len(r.Gauges) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// metricsLine lists a result's counters (per run) and gauges, sorted by
+// name.
+func metricsLine(r runner.Result) string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(r.Counters)) {
+		parts = append(parts, fmt.Sprintf("%s %s/run", name, formatMetric(r.Counters[name])))
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Gauges)) {
+		parts = append(parts, fmt.Sprintf("%s %s", name, formatMetric(r.Gauges[name])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatMetric(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// Findings picks out the few things a reader should take away from the
+// summary table.
+func Findings(results []runner.Result) []string {
+	var findings []string
+
+	best, worst := results[0], results[0]
+	for _, r := range results[1:] {
+		if r.Speedup() > best.Speedup() {
+			best = r
+		}
+		if r.Speedup() < worst.Speedup() {
+			worst = r
+		}
+	}
+	findings = append(findings, fmt.Sprintf("%s benefits most from parallelism (%.2fx on %d cores)",
+		best.Workload, best.Speedup(), best.Procs))
+	if worst.Workload != best.Workload {
+		findings = append(findings, fmt.Sprintf("%s benefits least (%.2fx)", worst.Workload, worst.Speedup()))
+	}
+
+	for _, r := range results {
+		if r.Speedup() < 1.2 {
+			findings = append(findings, fmt.Sprintf("%s gains little from parallelism: concurrency alone is enough", r.Workload))
+		}
+		if cv := r.CV(); cv > 10 {
+			findings = append(findings, fmt.Sprintf("%s timings are noisy (CV %.1f%%); treat its speedup with care", r.Workload, cv))
+		}
+	}
+	return findings
+}
@@ -0,0 +1,22 @@
+package runner
+
+import "compare_process/internal/workloads"
+
+// AccumulatePoint is one accumulation mode's cost at one worker count.
+type AccumulatePoint struct {
+	Workers  int
+	NsPerAdd float64
+}
+
+// AccumulateSweep sums total adds through mode at each worker count, with
+// GOMAXPROCS at procs, so every point does the same work split more ways.
+func AccumulateSweep(mode workloads.AccumulateMode, workers []int, total, procs int) []AccumulatePoint {
+	points := make([]AccumulatePoint, 0, len(workers))
+	for _, n := range workers {
+		Settle()
+		elapsed := workloads.RunAccumulate(mode, procs, n, total)
+		adds := total / n * n
+		points = append(points, AccumulatePoint{Workers: n, NsPerAdd: float64(elapsed.Nanoseconds()) / float64(adds)})
+	}
+	return points
+}
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+const (
+	// limitSampleInterval is how often RunLimiter samples the limit
+	limitSampleInterval = 20 * time.Millisecond
+	// convergenceBand is how close to its settled value the limit must
+	// stay to count as converged
+	convergenceBand = 0.2
+)
+
+// LimiterRun is one limiter's result in front of a degrading backend.
+type LimiterRun struct {
+	Name       string
+	Throughput float64 // calls per second
+	P50        time.Duration
+	P99        time.Duration
+	// ServiceP99 is the backend's own p99, without time held back
+	ServiceP99 time.Duration
+	// Trace is the limit sampled every limitSampleInterval
+	Trace []float64
+}
+
+// RunLimiter has clients call b through l for duration, sampling the
+// limit as it goes.
+func RunLimiter(l workloads.Limiter, b *workloads.Backend, clients int, duration time.Duration) LimiterRun {
+	Settle()
+
+	stop := make(chan struct{})
+	traced := make(chan []float64)
+	go func() {
+		var trace []float64
+		defer func() { traced <- trace }()
+		defer workloads.Guard()
+		trace = append(trace, l.Limit())
+		ticker := time.NewTicker(limitSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				trace = append(trace, l.Limit())
+			}
+		}
+	}()
+
+	latencies, service := workloads.RunLimited(l, b, clients, duration)
+	close(stop)
+
+	return LimiterRun{
+		Name:       l.String(),
+		Throughput: float64(len(latencies)) / duration.Seconds(),
+		P50:        stats.Percentile(latencies, 50),
+		P99:        stats.Percentile(latencies, 99),
+		ServiceP99: stats.Percentile(service, 99),
+		Trace:      <-traced,
+	}
+}
+
+// Settled is the mean limit over the last quarter of the trace.
+func (r LimiterRun) Settled() float64 {
+	tail := r.Trace[len(r.Trace)*3/4:]
+	sum := 0.0
+	for _, v := range tail {
+		sum += v
+	}
+	return sum / float64(len(tail))
+}
+
+// Converged returns how long the limit took to enter, and stay within,
+// 20% of its settled value. ok is false if it was still outside the band
+// in the last quarter of the run.
+func (r LimiterRun) Converged() (time.Duration, bool) {
+	settled := r.Settled()
+	last := -1
+	for i, v := range r.Trace {
+		if v < settled*(1-convergenceBand) || v > settled*(1+convergenceBand) {
+			last = i
+		}
+	}
+	if last >= len(r.Trace)*3/4 {
+		return 0, false
+	}
+	return time.Duration(last+1) * limitSampleInterval, true
+}
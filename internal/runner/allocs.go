@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/workloads"
+)
+
+// Allocs is the heap allocation done by one mode's runs, summed over runs,
+// with the number of tasks those runs completed.
+type Allocs struct {
+	Bytes   uint64 `json:"bytes"`
+	Objects uint64 `json:"allocs"`
+	Tasks   int    `json:"tasks"`
+}
+
+// BytesPerOp is B/op in -benchmem terms, where an op is one task.
+func (a Allocs) BytesPerOp() float64 {
+	if a.Tasks == 0 {
+		return 0
+	}
+	return float64(a.Bytes) / float64(a.Tasks)
+}
+
+// AllocsPerOp is allocs/op in -benchmem terms, where an op is one task.
+func (a Allocs) AllocsPerOp() float64 {
+	if a.Tasks == 0 {
+		return 0
+	}
+	return float64(a.Objects) / float64(a.Tasks)
+}
+
+func (a *Allocs) add(b Allocs) {
+	a.Bytes += b.Bytes
+	a.Objects += b.Objects
+	a.Tasks += b.Tasks
+}
+
+// measure runs one wave of w and reports its wall time, allocations and
+// what the scheduler and collector recorded meanwhile. The metrics are read
+// outside the timed region, the same way testing.B brackets a benchmark.
+func measure(w workloads.Workload, procs int, m *workloads.Metrics) (time.Duration, Allocs, RuntimeStats) {
+	before := readRuntime()
+	elapsed := w.Run(procs, m)
+	after := readRuntime()
+
+	return elapsed, Allocs{
+		Bytes:   after.allocBytes - before.allocBytes,
+		Objects: after.allocObjects - before.allocObjects,
+		Tasks:   w.Tasks(),
+	}, runtimeDelta(after, before)
+}
+
+// measureWindow is measure repeated until window has passed, returning
+// the average run, what all the runs allocated and cost the runtime, and
+// how many there were. Without a window it runs w once.
+func measureWindow(w workloads.Workload, procs int, m *workloads.Metrics, window time.Duration) (time.Duration, Allocs, RuntimeStats, int) {
+	var total time.Duration
+	var allocs Allocs
+	var rt RuntimeStats
+	runs := 0
+	for runs == 0 || total < window {
+		elapsed, a, r := measure(w, procs, m)
+		total += elapsed
+		allocs.add(a)
+		rt.add(r)
+		runs++
+	}
+	return total / time.Duration(runs), allocs, rt, runs
+}
+
+// GCCost is the heap allocation and collector work one run caused.
+type GCCost struct {
+	Bytes  uint64
+	Cycles uint64
+	Pauses Histogram
+}
+
+// MeasureGC runs run and reports what it cost the garbage collector.
+func MeasureGC(run func()) GCCost {
+	before := readRuntime()
+	run()
+	after := readRuntime()
+
+	return GCCost{
+		Bytes:  after.allocBytes - before.allocBytes,
+		Cycles: after.gcCycles - before.gcCycles,
+		Pauses: histogramDelta(after.gcPauses, before.gcPauses),
+	}
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"compare_process/internal/workloads"
+)
+
+// Antagonist is a noisy neighbor running in a separate child process, so it
+// competes for cores and memory bandwidth like another tenant on a shared
+// host rather than for this process's Ps.
+type Antagonist struct {
+	kind  string
+	cores int
+	cmd   *exec.Cmd
+}
+
+// StartAntagonist re-executes the current binary with -antagonist-worker,
+// which the child's main hands to RunAntagonistWorker.
+func StartAntagonist(kind string, cores, memMB int) (*Antagonist, error) {
+	if kind != "cpu" && kind != "mem" {
+		return nil, fmt.Errorf("unknown antagonist %q (want cpu or mem)", kind)
+	}
+	if runtime.GOOS == "js" {
+		return nil, fmt.Errorf("antagonist processes are unavailable in js/wasm")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating executable: %w", err)
+	}
+
+	cmd := exec.Command(exe,
+		"-antagonist-worker="+kind,
+		"-antagonist-cores="+strconv.Itoa(cores),
+		"-antagonist-mem-mb="+strconv.Itoa(memMB))
+	cmd.Stderr = os.Stderr
+	// The child exits when this pipe closes, so it can't outlive us even if
+	// we're killed before Stop runs
+	if _, err := cmd.StdinPipe(); err != nil {
+		return nil, fmt.Errorf("creating antagonist pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting antagonist: %w", err)
+	}
+
+	// Give the child time to spin up its workers before measuring
+	time.Sleep(100 * time.Millisecond)
+	return &Antagonist{kind: kind, cores: cores, cmd: cmd}, nil
+}
+
+func (a *Antagonist) Stop() {
+	a.cmd.Process.Kill()
+	a.cmd.Wait()
+}
+
+func (a *Antagonist) String() string {
+	return fmt.Sprintf("%s antagonist on %d cores (pid %d)", a.kind, a.cores, a.cmd.Process.Pid)
+}
+
+// RunAntagonistWorker is the body of the child process. It never returns;
+// the parent kills it when the suites are done, or it exits on its own once
+// the parent's end of stdin goes away.
+func RunAntagonistWorker(kind string, cores, memMB int) {
+	runtime.GOMAXPROCS(cores)
+
+	for i := 0; i < cores; i++ {
+		if kind == "mem" {
+			go workloads.ThrashMemory(memMB / cores)
+		} else {
+			go workloads.BurnCPU()
+		}
+	}
+	io.Copy(io.Discard, os.Stdin)
+	os.Exit(0)
+}
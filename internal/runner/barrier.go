@@ -0,0 +1,32 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/workloads"
+)
+
+// BarrierCost is what synchronizing each phase cost one barrier mode at
+// one worker count.
+type BarrierCost struct {
+	Workers  int
+	PerPhase time.Duration // wall time per phase beyond the work itself
+}
+
+// BarrierSweep runs phases phases with mode at each worker count, with
+// GOMAXPROCS at procs, and subtracts the work's own time spread over the
+// cores it could use, leaving the synchronization cost per phase.
+func BarrierSweep(mode workloads.BarrierMode, workers []int, phases, procs int) []BarrierCost {
+	costs := make([]BarrierCost, 0, len(workers))
+	for _, n := range workers {
+		Settle()
+		work := workloads.RunPhaseWork(n, phases) / time.Duration(min(n, procs))
+		Settle()
+		elapsed := workloads.RunBarrier(mode, procs, n, phases)
+		costs = append(costs, BarrierCost{
+			Workers:  n,
+			PerPhase: max(0, elapsed-work) / time.Duration(phases),
+		})
+	}
+	return costs
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// BFSRun is one graph traversed serially and level-parallel at several
+// worker counts.
+type BFSRun struct {
+	Graph    *workloads.Graph
+	Levels   int
+	Reached  int
+	Serial   time.Duration
+	Parallel []BFSPoint
+}
+
+// BFSPoint is the level-parallel traversal at one worker count, with
+// GOMAXPROCS set to match.
+type BFSPoint struct {
+	Workers int
+	Elapsed time.Duration
+	// Agrees is whether it reached as many vertices over as many levels
+	// as the serial traversal
+	Agrees bool
+}
+
+// LevelCost is what the level-parallel traversal pays per level over the
+// serial one at one worker, where there is nothing to gain: spawning and
+// joining the workers, atomic claims and merging the frontier.
+func (r BFSRun) LevelCost() time.Duration {
+	if len(r.Parallel) == 0 || r.Parallel[0].Workers != 1 {
+		return 0
+	}
+	return max(0, r.Parallel[0].Elapsed-r.Serial) / time.Duration(r.Levels)
+}
+
+// RunBFS times g's traversals, taking the median of iterations runs of
+// each.
+func RunBFS(g *workloads.Graph, workers []int, iterations int) BFSRun {
+	var serial []time.Duration
+	var base workloads.BFSResult
+	for i := 0; i < iterations; i++ {
+		Settle()
+		base = workloads.SerialBFS(g)
+		serial = append(serial, base.Elapsed)
+	}
+	run := BFSRun{Graph: g, Levels: base.Levels, Reached: base.Reached, Serial: stats.Median(serial)}
+
+	for _, n := range workers {
+		var times []time.Duration
+		agrees := true
+		for i := 0; i < iterations; i++ {
+			Settle()
+			r := workloads.ParallelBFS(g, n, n)
+			times = append(times, r.Elapsed)
+			agrees = agrees && r.Levels == base.Levels && r.Reached == base.Reached
+		}
+		run.Parallel = append(run.Parallel, BFSPoint{Workers: n, Elapsed: stats.Median(times), Agrees: agrees})
+	}
+	return run
+}
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// BroadcastPoint is one mode and subscriber count of a broadcast run.
+type BroadcastPoint struct {
+	Subscribers int
+	Throughput  float64 // deliveries (messages × subscribers) per second
+	P50         time.Duration
+	P99         time.Duration
+	// Spread is how far apart the first and last subscriber saw the same
+	// message, at the median and the 99th percentile message
+	SpreadP50 time.Duration
+	SpreadP99 time.Duration
+}
+
+// BroadcastSweep publishes messages events through mode at each
+// subscriber count.
+func BroadcastSweep(mode workloads.BroadcastMode, subscribers []int, messages int) []BroadcastPoint {
+	points := make([]BroadcastPoint, 0, len(subscribers))
+	for _, n := range subscribers {
+		Settle()
+		elapsed, delays := workloads.RunBroadcast(mode, n, messages)
+
+		spreads := make([]time.Duration, messages)
+		for seq := range spreads {
+			lo, hi := delays[seq], delays[seq]
+			for s := 1; s < n; s++ {
+				d := delays[s*messages+seq]
+				lo, hi = min(lo, d), max(hi, d)
+			}
+			spreads[seq] = hi - lo
+		}
+
+		points = append(points, BroadcastPoint{
+			Subscribers: n,
+			Throughput:  float64(len(delays)) / elapsed.Seconds(),
+			P50:         stats.Percentile(delays, 50),
+			P99:         stats.Percentile(delays, 99),
+			SpreadP50:   stats.Percentile(spreads, 50),
+			SpreadP99:   stats.Percentile(spreads, 99),
+		})
+	}
+	return points
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"math"
+	"time"
+
+	"compare_process/internal/workloads"
+)
+
+// Calibration is what Calibrate chose for each workload and how long one
+// single-goroutine run took at that size.
+type Calibration struct {
+	Target     time.Duration
+	PrimeLimit int
+	CPU        time.Duration
+	IOOps      int
+	IO         time.Duration
+}
+
+// maxPrimeLimit keeps the calibrated limit well inside an int on 32-bit
+// platforms, whatever the target
+const maxPrimeLimit = 1 << 30
+
+// Calibrate sizes c's prime limit and I/O op count so a run of one CPU or
+// one I/O goroutine at GOMAXPROCS=1 takes about target on this machine.
+// The prime search grows the limit fourfold until a run takes a quarter
+// of target, then extrapolates with the growth rate the last two runs
+// showed; the I/O count divides target by the time one op took.
+func Calibrate(c workloads.Config, target time.Duration) (workloads.Config, Calibration) {
+	single := c
+	single.Goroutines = 1
+	single.Micro = false
+	cal := Calibration{Target: target}
+
+	timeCPU := func(limit int) time.Duration {
+		single.PrimeLimit = limit
+		Settle()
+		return single.CPU().Run(1, nil)
+	}
+	prevLimit, limit := 0, 10_000
+	var prev, elapsed time.Duration
+	for {
+		elapsed = timeCPU(limit)
+		if elapsed >= target/4 || limit >= maxPrimeLimit {
+			break
+		}
+		prevLimit, prev = limit, elapsed
+		limit = min(limit*4, maxPrimeLimit)
+	}
+	// Counting primes to n costs about n^1.5, but the measured rate also
+	// covers caches and the clock
+	exponent := 1.5
+	if prevLimit > 0 && prev > 0 && elapsed > prev {
+		exponent = math.Log(float64(elapsed)/float64(prev)) / math.Log(float64(limit)/float64(prevLimit))
+	}
+	scaled := float64(limit) * math.Pow(float64(target)/float64(elapsed), 1/exponent)
+	cal.PrimeLimit = int(min(max(scaled, 2), maxPrimeLimit))
+	cal.CPU = timeCPU(cal.PrimeLimit)
+
+	const probeOps = 5
+	single.IOOps = probeOps
+	Settle()
+	perOp := single.IO().Run(1, nil) / probeOps
+	cal.IOOps = max(1, int(math.Round(float64(target)/float64(max(perOp, 1)))))
+	single.IOOps = cal.IOOps
+	Settle()
+	cal.IO = single.IO().Run(1, nil)
+
+	c.PrimeLimit, c.IOOps = cal.PrimeLimit, cal.IOOps
+	return c, cal
+}
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"math"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// CancelPoint is how quickly one cancellation case stops.
+type CancelPoint struct {
+	Workload string
+	Style    string
+	// Latency is the median time from cancel to the last goroutine
+	// returning
+	Latency time.Duration
+	// Worst is the slowest of the runs
+	Worst time.Duration
+}
+
+// Score rates the latency from 100, gone within 100µs, down to 0 at a
+// second or more, losing 25 points per tenfold slower.
+func (p CancelPoint) Score() float64 {
+	if p.Latency <= 0 {
+		return 100
+	}
+	score := 100 * (1 - math.Log10(float64(p.Latency)/float64(100*time.Microsecond))/4)
+	return min(100, max(0, score))
+}
+
+// RunCancelCases cancels each of cases iterations times at GOMAXPROCS
+// procs, after warmup lets every goroutine get into its task.
+func RunCancelCases(cases []workloads.CancelCase, iterations int, warmup time.Duration, procs int) []CancelPoint {
+	points := make([]CancelPoint, 0, len(cases))
+	for _, cc := range cases {
+		var latencies []time.Duration
+		for i := 0; i < iterations; i++ {
+			Settle()
+			latencies = append(latencies, workloads.RunCancel(cc, procs, warmup))
+		}
+		points = append(points, CancelPoint{
+			Workload: cc.Workload,
+			Style:    cc.Style(),
+			Latency:  stats.Median(latencies),
+			Worst:    stats.Max(latencies),
+		})
+	}
+	return points
+}
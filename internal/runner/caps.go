@@ -0,0 +1,99 @@
+package runner
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"time"
+)
+
+// Caps bound what one suite may use; a zero field is no cap.
+type Caps struct {
+	// MaxHeap is in-use heap spans in bytes: live objects plus their free
+	// slots, what the suite's allocations keep from the OS
+	MaxHeap uint64
+	// MaxGoroutines counts every goroutine in the process, the harness's
+	// few included
+	MaxGoroutines int
+}
+
+func (c Caps) Enabled() bool { return c.MaxHeap > 0 || c.MaxGoroutines > 0 }
+
+// CapExceeded is a cap a suite went over.
+type CapExceeded struct {
+	Resource string // "heap" or "goroutines"
+	Limit    uint64
+	Seen     uint64
+}
+
+func (e *CapExceeded) Error() string {
+	if e.Resource == "heap" {
+		return fmt.Sprintf("heap reached %.1f MiB, over the %.1f MiB cap", float64(e.Seen)/(1<<20), float64(e.Limit)/(1<<20))
+	}
+	return fmt.Sprintf("%s reached %d, over the %d cap", e.Resource, e.Seen, e.Limit)
+}
+
+var capMetrics = []string{metricHeapObjects, metricHeapUnused, metricGoroutines}
+
+// Exceeded returns the first cap usage is over right now, or nil.
+func (c Caps) Exceeded() *CapExceeded {
+	samples := make([]metrics.Sample, len(capMetrics))
+	for i, name := range capMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	var heap, goroutines uint64
+	for _, s := range samples {
+		if s.Value.Kind() != metrics.KindUint64 {
+			continue
+		}
+		if s.Name == metricGoroutines {
+			goroutines = s.Value.Uint64()
+		} else {
+			heap += s.Value.Uint64()
+		}
+	}
+	switch {
+	case c.MaxHeap > 0 && heap > c.MaxHeap:
+		return &CapExceeded{Resource: "heap", Limit: c.MaxHeap, Seen: heap}
+	case c.MaxGoroutines > 0 && goroutines > uint64(c.MaxGoroutines):
+		return &CapExceeded{Resource: "goroutines", Limit: uint64(c.MaxGoroutines), Seen: goroutines}
+	}
+	return nil
+}
+
+// CapWatch polls Caps in the background and delivers the first cap
+// exceeded on C.
+type CapWatch struct {
+	C    <-chan *CapExceeded
+	stop chan struct{}
+}
+
+// WatchCaps checks caps every interval until Stop. Without caps it never
+// checks, and C never delivers.
+func WatchCaps(caps Caps, interval time.Duration) *CapWatch {
+	exceeded := make(chan *CapExceeded, 1)
+	w := &CapWatch{C: exceeded, stop: make(chan struct{})}
+	if !caps.Enabled() {
+		return w
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if e := caps.Exceeded(); e != nil {
+					exceeded <- e
+					return
+				}
+			}
+		}
+	}()
+	return w
+}
+
+func (w *CapWatch) Stop() { close(w.stop) }
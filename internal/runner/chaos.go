@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"compare_process/internal/workloads"
+)
+
+// Chaos perturbs the runtime from a background goroutine while a workload
+// runs: forced GCs, GOMAXPROCS changes and short spin bursts.
+type Chaos struct {
+	rng      *rand.Rand
+	stop     chan struct{}
+	done     chan struct{}
+	maxProcs int
+
+	GCs         int
+	ProcChanges int
+	SpinBursts  int
+}
+
+// StartChaos starts perturbing the runtime until Stop: every 5-25ms it
+// forces a GC, sets GOMAXPROCS to between 1 and NumCPU, or spins up to
+// NumCPU goroutines for 1-5ms. The seed picks the delays, the
+// perturbations and their sizes, so a seed replays the same sequence.
+func StartChaos(seed int64) *Chaos {
+	c := &Chaos{
+		rng:      rand.New(rand.NewSource(seed)),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		maxProcs: runtime.GOMAXPROCS(0),
+	}
+	go c.loop()
+	return c
+}
+
+func (c *Chaos) loop() {
+	defer close(c.done)
+	defer workloads.Guard()
+
+	for {
+		// Perturb every 5-25ms
+		delay := time.Duration(5+c.rng.Intn(20)) * time.Millisecond
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		switch c.rng.Intn(3) {
+		case 0:
+			runtime.GC()
+			c.GCs++
+		case 1:
+			runtime.GOMAXPROCS(1 + c.rng.Intn(runtime.NumCPU()))
+			c.ProcChanges++
+		case 2:
+			spinBurst(1+c.rng.Intn(runtime.NumCPU()), time.Duration(1+c.rng.Intn(5))*time.Millisecond)
+			c.SpinBursts++
+		}
+	}
+}
+
+// Stop ends the perturbations and restores the GOMAXPROCS value that was
+// active when the monkey started.
+func (c *Chaos) Stop() {
+	close(c.stop)
+	<-c.done
+	runtime.GOMAXPROCS(c.maxProcs)
+}
+
+func (c *Chaos) String() string {
+	return fmt.Sprintf("%d GCs, %d GOMAXPROCS changes, %d spin bursts", c.GCs, c.ProcChanges, c.SpinBursts)
+}
+
+// spinBurst keeps n goroutines busy for d, stealing CPU from the workload.
+func spinBurst(n int, d time.Duration) {
+	var wg sync.WaitGroup
+	deadline := time.Now().Add(d)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer workloads.Guard()
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,222 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/workloads"
+)
+
+// ContentionPoint is one sender:receiver shape of a ContentionSweep.
+type ContentionPoint struct {
+	workloads.ChannelShape
+	Throughput float64       // messages per second
+	PerMessage time.Duration // wall time per message
+}
+
+// ContentionSweep pushes messages values through one shared channel at
+// each shape.
+func ContentionSweep(shapes []workloads.ChannelShape, messages int) []ContentionPoint {
+	points := make([]ContentionPoint, 0, len(shapes))
+	for _, shape := range shapes {
+		Settle()
+		elapsed := workloads.RunChannelContention(shape, messages)
+		points = append(points, ContentionPoint{
+			ChannelShape: shape,
+			Throughput:   float64(messages) / elapsed.Seconds(),
+			PerMessage:   elapsed / time.Duration(messages),
+		})
+	}
+	return points
+}
+
+// ContentionCollapse returns the first point, after the best one, whose
+// throughput has fallen below half the best: past it, adding goroutines
+// to the channel costs more in lock handoffs than it brings. ok is false
+// when throughput never halves.
+func ContentionCollapse(points []ContentionPoint) (ContentionPoint, bool) {
+	best := 0
+	for i, p := range points {
+		if p.Throughput > points[best].Throughput {
+			best = i
+		}
+	}
+	for _, p := range points[best+1:] {
+		if p.Throughput < points[best].Throughput/2 {
+			return p, true
+		}
+	}
+	return ContentionPoint{}, false
+}
+
+// BufferPoint is one buffer size and pair count of a BufferSweep, in
+// messages per second at GOMAXPROCS=1 and at the parallel setting.
+type BufferPoint struct {
+	Buffer, Pairs        int
+	Concurrent, Parallel float64
+}
+
+// Ratio is the parallel throughput over the concurrent one: below 1,
+// spreading the pairs over more Ps made each handoff dearer.
+func (p BufferPoint) Ratio() float64 { return p.Parallel / p.Concurrent }
+
+// BufferSweep sends messages values through producer/consumer pairs at
+// every buffer size and pair count, once at GOMAXPROCS=1 and once at
+// procs.
+func BufferSweep(buffers, pairs []int, messages, procs int) []BufferPoint {
+	points := make([]BufferPoint, 0, len(buffers)*len(pairs))
+	for _, b := range buffers {
+		for _, n := range pairs {
+			p := BufferPoint{Buffer: b, Pairs: n}
+			Settle()
+			p.Concurrent = float64(messages) / workloads.RunChannelPairs(n, b, messages, 1).Seconds()
+			Settle()
+			p.Parallel = float64(messages) / workloads.RunChannelPairs(n, b, messages, procs).Seconds()
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// ShardPoint is one shard count of a ShardSweep.
+type ShardPoint struct {
+	Shards     int
+	Throughput float64 // messages per second
+	Speedup    float64 // against a single shared channel
+}
+
+// ShardSweep pushes messages values from senders goroutines through each
+// number of shards. shards should start at 1, the single-channel baseline
+// the others are compared against.
+func ShardSweep(senders int, shards []int, messages int) []ShardPoint {
+	points := make([]ShardPoint, 0, len(shards))
+	for _, k := range shards {
+		Settle()
+		elapsed := workloads.RunShardedChannels(senders, k, messages)
+		p := ShardPoint{Shards: k, Throughput: float64(messages) / elapsed.Seconds(), Speedup: 1}
+		if len(points) > 0 {
+			p.Speedup = p.Throughput / points[0].Throughput
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// ShardPayoff returns the fewest shards that beat the single channel by
+// at least 10%. ok is false when sharding never pays off.
+func ShardPayoff(points []ShardPoint) (ShardPoint, bool) {
+	for _, p := range points[1:] {
+		if p.Speedup >= 1.1 {
+			return p, true
+		}
+	}
+	return ShardPoint{}, false
+}
+
+// PipelinePoint is one set of stage widths and buffer size of a
+// PipelineSweep, run at GOMAXPROCS=1 and at the parallel setting.
+type PipelinePoint struct {
+	Widths               workloads.StageWidths
+	Buffer               int
+	Concurrent, Parallel workloads.PipelineResult
+}
+
+// Speedup is the parallel run's throughput over the concurrent one's.
+func (p PipelinePoint) Speedup() float64 {
+	return float64(p.Concurrent.Elapsed) / float64(p.Parallel.Elapsed)
+}
+
+// PipelineSweep streams items lines through the pipeline at every set of
+// stage widths and buffer size, once at GOMAXPROCS=1 and once at procs.
+func PipelineSweep(widths []workloads.StageWidths, buffers []int, items, procs int) []PipelinePoint {
+	lines := workloads.PipelineLines(items)
+	points := make([]PipelinePoint, 0, len(widths)*len(buffers))
+	for _, w := range widths {
+		for _, b := range buffers {
+			p := PipelinePoint{Widths: w, Buffer: b}
+			Settle()
+			p.Concurrent = workloads.RunPipeline(lines, w, b, 1)
+			Settle()
+			p.Parallel = workloads.RunPipeline(lines, w, b, procs)
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// tuneRuns is how many times TunePipeline runs each allocation, keeping
+// the fastest so one descheduled run can't steer the search
+const tuneRuns = 3
+
+// PipelineTuning is the allocations TunePipeline tried, in order, and the
+// fastest of them.
+type PipelineTuning struct {
+	Trials []workloads.PipelineResult
+	Best   workloads.PipelineResult
+	// Even is the run with the budget split as evenly as it goes
+	Even workloads.PipelineResult
+}
+
+// TunePipeline searches for the stage widths that stream items lines
+// fastest at GOMAXPROCS=procs using at most budget goroutines across the
+// stages. It starts from one goroutine per stage and keeps giving one more
+// to the bottleneck until the budget is spent, then moves one goroutine at
+// a time between stages for as long as that beats the best so far by 2%.
+func TunePipeline(budget, buffer, items, procs int) PipelineTuning {
+	lines := workloads.PipelineLines(items)
+	var t PipelineTuning
+	tried := map[workloads.StageWidths]workloads.PipelineResult{}
+	run := func(w workloads.StageWidths) workloads.PipelineResult {
+		if r, ok := tried[w]; ok {
+			return r
+		}
+		var r workloads.PipelineResult
+		for i := 0; i < tuneRuns; i++ {
+			Settle()
+			if run := workloads.RunPipeline(lines, w, buffer, procs); i == 0 || run.Elapsed < r.Elapsed {
+				r = run
+			}
+		}
+		tried[w] = r
+		t.Trials = append(t.Trials, r)
+		if t.Best.Elapsed == 0 || r.Elapsed < t.Best.Elapsed {
+			t.Best = r
+		}
+		return r
+	}
+
+	// Grow: the bottleneck is the stage another goroutine helps most
+	r := run(workloads.StageWidths{1, 1, 1})
+	for w := r.Widths; w.Total() < budget; w = r.Widths {
+		w[r.Bottleneck()]++
+		r = run(w)
+	}
+
+	// Refine: growing only ever adds, so try handing a goroutine back
+	for improved := true; improved; {
+		improved = false
+		from := t.Best
+		for i := range workloads.PipelineStages {
+			for j := range workloads.PipelineStages {
+				if i == j || from.Widths[i] == 1 {
+					continue
+				}
+				w := from.Widths
+				w[i]--
+				w[j]++
+				if r := run(w); float64(r.Elapsed) < float64(from.Elapsed)*0.98 {
+					improved = true
+				}
+			}
+		}
+	}
+
+	var even workloads.StageWidths
+	for i := range even {
+		even[i] = budget / len(even)
+		if i < budget%len(even) {
+			even[i]++
+		}
+	}
+	t.Even = run(even)
+	return t
+}
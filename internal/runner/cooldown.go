@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/sysinfo"
+)
+
+const (
+	// cooldownMaxExtensions caps how many extra periods a frequency-checked
+	// cooldown may add while waiting for the clock to recover
+	cooldownMaxExtensions = 3
+	// cooldownFreqTolerance is how close to the starting frequency counts
+	// as recovered
+	cooldownFreqTolerance = 0.95
+)
+
+// CooldownEvent records one pause between suites, exported with the
+// results so thermal effects can be checked later.
+type CooldownEvent struct {
+	Before    string  `json:"before_suite"`
+	SleptNS   int64   `json:"slept_ns"`
+	StartMHz  float64 `json:"start_mhz,omitempty"`
+	EndMHz    float64 `json:"end_mhz,omitempty"`
+	Recovered bool    `json:"recovered"`
+}
+
+// CoolDown idles before the next suite. With checkFreq it keeps idling,
+// up to cooldownMaxExtensions more periods, until cpu0's clock is back
+// within tolerance of baselineMHz.
+func CoolDown(nextSuite string, period time.Duration, checkFreq bool, baselineMHz float64) CooldownEvent {
+	event := CooldownEvent{Before: nextSuite, Recovered: true}
+	event.StartMHz, _ = sysinfo.CurrentCPUMHz()
+
+	start := time.Now()
+	time.Sleep(period)
+
+	if checkFreq && baselineMHz > 0 {
+		for i := 0; ; i++ {
+			mhz, ok := sysinfo.CurrentCPUMHz()
+			if !ok || mhz >= baselineMHz*cooldownFreqTolerance {
+				break
+			}
+			if i == cooldownMaxExtensions {
+				event.Recovered = false
+				break
+			}
+			time.Sleep(period)
+		}
+	}
+
+	event.SleptNS = time.Since(start).Nanoseconds()
+	event.EndMHz, _ = sysinfo.CurrentCPUMHz()
+	return event
+}
@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// DAGRun is one graph shape executed serially and in parallel.
+type DAGRun struct {
+	Shape    workloads.DAGShape
+	Serial   time.Duration // one worker at GOMAXPROCS=1
+	Parallel time.Duration // procs workers at GOMAXPROCS=procs
+	// Bound is the speedup the shape allows procs workers
+	Bound float64
+}
+
+func (r DAGRun) Speedup() float64 {
+	return float64(r.Serial) / float64(r.Parallel)
+}
+
+// RunDAGShape builds shape once and times it serially and with procs
+// workers at GOMAXPROCS=procs, iterations times each, averaging the runs.
+func RunDAGShape(shape workloads.DAGShape, procs, iterations int) DAGRun {
+	d := workloads.NewDAG(shape)
+	var serial, parallel []time.Duration
+	for i := 0; i < iterations; i++ {
+		Settle()
+		serial = append(serial, workloads.RunDAG(d, 1, 1))
+		Settle()
+		parallel = append(parallel, workloads.RunDAG(d, procs, procs))
+	}
+	return DAGRun{
+		Shape:    shape,
+		Serial:   stats.Average(serial),
+		Parallel: stats.Average(parallel),
+		Bound:    shape.Bound(procs),
+	}
+}
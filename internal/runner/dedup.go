@@ -0,0 +1,23 @@
+package runner
+
+import "compare_process/internal/workloads"
+
+// DedupPoint is one seen-set's throughput at one worker count.
+type DedupPoint struct {
+	Workers      int
+	BlocksPerSec float64
+	// Unique is how many distinct blocks the set recorded
+	Unique int
+}
+
+// DedupSweep deduplicates ids through mode at each worker count, with
+// GOMAXPROCS at procs, so every point does the same work split more ways.
+func DedupSweep(mode workloads.DedupMode, workers []int, ids []int32, procs int) []DedupPoint {
+	points := make([]DedupPoint, 0, len(workers))
+	for _, n := range workers {
+		Settle()
+		elapsed, unique := workloads.RunDedup(mode, procs, n, ids)
+		points = append(points, DedupPoint{Workers: n, BlocksPerSec: float64(len(ids)) / elapsed.Seconds(), Unique: unique})
+	}
+	return points
+}
@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// ServePoint is one serving model at one connection count.
+type ServePoint struct {
+	Conns      int
+	Throughput float64 // requests per second
+	P50        time.Duration
+	P99        time.Duration
+}
+
+// ServeSweep serves requests requests per connection, gap apart on
+// average, through model at each connection count with GOMAXPROCS at
+// procs.
+func ServeSweep(model workloads.ServeModel, conns []int, requests int, gap time.Duration, procs int) []ServePoint {
+	points := make([]ServePoint, 0, len(conns))
+	for _, n := range conns {
+		Settle()
+		elapsed, latencies := workloads.RunConnections(model, procs, n, requests, gap)
+		points = append(points, ServePoint{
+			Conns:      n,
+			Throughput: float64(len(latencies)) / elapsed.Seconds(),
+			P50:        stats.Percentile(latencies, 50),
+			P99:        stats.Percentile(latencies, 99),
+		})
+	}
+	return points
+}
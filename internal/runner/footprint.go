@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"runtime"
+	"time"
+
+	"compare_process/internal/workloads"
+)
+
+// Footprint is the memory a batch of parked goroutines holds, as deltas
+// of the runtime's memory metrics.
+type Footprint struct {
+	Goroutines int
+	Active     bool
+	Spawn      time.Duration // starting all of them until the last parked
+	Stack      uint64        // goroutine stacks
+	Heap       uint64        // heap, including the runtime's g structs
+}
+
+// PerGoroutine is the stack and heap each goroutine holds, in bytes.
+// Memory obtained from the OS isn't counted: the runtime keeps it after
+// one batch exits, so only the first batch would show it.
+func (f Footprint) PerGoroutine() float64 {
+	return float64(f.Stack+f.Heap) / float64(f.Goroutines)
+}
+
+// MeasureFootprint parks n goroutines, reads what they hold and releases
+// them again.
+func MeasureFootprint(n int, active bool) Footprint {
+	runtime.GC()
+	before := readRuntime()
+
+	start := time.Now()
+	release := workloads.ParkGoroutines(n, active)
+	spawn := time.Since(start)
+	after := readRuntime()
+	release()
+
+	return Footprint{
+		Goroutines: n,
+		Active:     active,
+		Spawn:      spawn,
+		Stack:      delta(after.stacks, before.stacks),
+		Heap:       delta(after.heap, before.heap),
+	}
+}
+
+// delta is after-before, clamped at zero: freed memory can make a
+// statistic shrink across a measurement that added little of its own.
+func delta(after, before uint64) uint64 {
+	if after < before {
+		return 0
+	}
+	return after - before
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"compare_process/internal/sysinfo"
+)
+
+// GridCell is the outcome of one GOGC × GOMEMLIMIT child run.
+type GridCell struct {
+	GOGC     string
+	MemLimit string
+	Total    time.Duration // sum of the suites' average parallel times
+	MaxRSS   uint64
+	RSSOK    bool
+	Err      error
+}
+
+// RunGridCell re-executes the current binary on the selected suites with
+// GOGC and GOMEMLIMIT set, in a fresh process so it starts with clean heap
+// and GC pacer state. The child's results are exchanged through a JSON
+// file in dir; args carry the suites' own settings.
+func RunGridCell(dir, suites, gogc, limit string, args ...string) GridCell {
+	cell := GridCell{GOGC: gogc, MemLimit: limit}
+
+	exe, err := os.Executable()
+	if err != nil {
+		cell.Err = fmt.Errorf("locating executable: %w", err)
+		return cell
+	}
+	out := filepath.Join(dir, fmt.Sprintf("gogc-%s-limit-%s.json", gogc, limit))
+
+	cmd := exec.Command(exe, append([]string{"-suites", suites, "-json", out}, args...)...)
+	cmd.Env = append(os.Environ(), "GOGC="+gogc)
+	if limit != "off" {
+		cmd.Env = append(cmd.Env, "GOMEMLIMIT="+limit)
+	}
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		cell.Err = err
+		return cell
+	}
+	cell.MaxRSS, cell.RSSOK = sysinfo.ChildMaxRSS(cmd.ProcessState)
+
+	cell.Total, cell.Err = totalParallelTime(out)
+	return cell
+}
+
+// totalParallelTime reads just the parallel timings out of a child's
+// result file, so this package doesn't depend on the report format.
+func totalParallelTime(path string) (time.Duration, error) {
+	var file struct {
+		Results []struct {
+			ParallelNS []int64 `json:"parallel_ns"`
+		} `json:"results"`
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading results: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return 0, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	var total time.Duration
+	for _, r := range file.Results {
+		var sum int64
+		for _, ns := range r.ParallelNS {
+			sum += ns
+		}
+		if len(r.ParallelNS) > 0 {
+			total += time.Duration(sum / int64(len(r.ParallelNS)))
+		}
+	}
+	return total, nil
+}
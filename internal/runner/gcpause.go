@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// LatenessBuckets are the upper bounds of GCPausePoint.Buckets; the last
+// bucket holds everything later
+var LatenessBuckets = []time.Duration{100 * time.Microsecond, time.Millisecond, 10 * time.Millisecond}
+
+// GCPausePoint is the ticker's lateness at one GOGC setting.
+type GCPausePoint struct {
+	GOGC int
+	GCs  uint32
+	// AllocRate is bytes allocated per second
+	AllocRate float64
+	P50, P99  time.Duration
+	Max       time.Duration
+	// Missed is the deadlines the ticker woke too late to serve, and
+	// Ticks the ones it served
+	Missed int
+	Ticks  int
+	// Buckets counts the ticks by lateness, split at LatenessBuckets
+	Buckets []int
+}
+
+// GCPauseSweep runs the ticker at GOMAXPROCS procs beside an allocator on
+// every other P at each GOGC setting, restoring the previous settings
+// afterwards.
+func GCPauseSweep(gogc []int, period, duration time.Duration, procs int) []GCPausePoint {
+	previous := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(previous)
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+
+	points := make([]GCPausePoint, 0, len(gogc))
+	for _, g := range gogc {
+		debug.SetGCPercent(g)
+		Settle()
+		r := workloads.RunTickerUnderAlloc(period, duration, max(1, procs-1))
+
+		p := GCPausePoint{
+			GOGC:      g,
+			GCs:       r.GCs,
+			AllocRate: float64(r.Allocated) / duration.Seconds(),
+			P50:       stats.Percentile(r.Lateness, 50),
+			P99:       stats.Percentile(r.Lateness, 99),
+			Missed:    r.Missed,
+			Ticks:     len(r.Lateness),
+			Buckets:   make([]int, len(LatenessBuckets)+1),
+		}
+		for _, late := range r.Lateness {
+			p.Max = max(p.Max, late)
+			bucket := len(LatenessBuckets)
+			for i, bound := range LatenessBuckets {
+				if late < bound {
+					bucket = i
+					break
+				}
+			}
+			p.Buckets[bucket]++
+		}
+		points = append(points, p)
+	}
+	return points
+}
@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// DebugTiming is one workload's average times in a GODEBUG child run.
+type DebugTiming struct {
+	Workload   string
+	Concurrent time.Duration
+	Parallel   time.Duration
+}
+
+// DebugRun is the outcome of one child run under a GODEBUG setting; an
+// empty Setting is the baseline, run with the environment as it is.
+type DebugRun struct {
+	Setting string
+	Timings []DebugTiming
+	Err     error
+}
+
+// Total sums the run's average parallel times.
+func (r DebugRun) Total() time.Duration {
+	var total time.Duration
+	for _, t := range r.Timings {
+		total += t.Parallel
+	}
+	return total
+}
+
+// RunDebugSetting re-executes the current binary on the selected suites
+// with GODEBUG set to setting, in a fresh process since most scheduler
+// settings are read once at startup. The child's results are exchanged
+// through a JSON file in dir; args carry the suites' own settings.
+func RunDebugSetting(dir string, index int, suites, setting string, args ...string) DebugRun {
+	run := DebugRun{Setting: setting}
+
+	exe, err := os.Executable()
+	if err != nil {
+		run.Err = fmt.Errorf("locating executable: %w", err)
+		return run
+	}
+	out := filepath.Join(dir, "godebug-"+strconv.Itoa(index)+".json")
+
+	cmd := exec.Command(exe, append([]string{"-suites", suites, "-json", out}, args...)...)
+	cmd.Env = os.Environ()
+	if setting != "" {
+		cmd.Env = append(cmd.Env, "GODEBUG="+setting)
+	}
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		run.Err = err
+		return run
+	}
+
+	run.Timings, run.Err = readTimings(out)
+	return run
+}
+
+// readTimings reads each workload's average times out of a child's result
+// file, so this package doesn't depend on the report format.
+func readTimings(path string) ([]DebugTiming, error) {
+	var file struct {
+		Results []struct {
+			Workload     string  `json:"workload"`
+			ConcurrentNS []int64 `json:"concurrent_ns"`
+			ParallelNS   []int64 `json:"parallel_ns"`
+		} `json:"results"`
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading results: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	timings := make([]DebugTiming, 0, len(file.Results))
+	for _, r := range file.Results {
+		timings = append(timings, DebugTiming{
+			Workload:   r.Workload,
+			Concurrent: meanNS(r.ConcurrentNS),
+			Parallel:   meanNS(r.ParallelNS),
+		})
+	}
+	return timings, nil
+}
+
+func meanNS(ns []int64) time.Duration {
+	if len(ns) == 0 {
+		return 0
+	}
+	var sum int64
+	for _, n := range ns {
+		sum += n
+	}
+	return time.Duration(sum / int64(len(ns)))
+}
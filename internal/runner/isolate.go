@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// IsolatedRun is where a child process left one suite's output.
+type IsolatedRun struct {
+	ResultPath string // -json result file
+	EventPath  string // -events-jsonl event log
+}
+
+// RunIsolated re-executes the current binary to run a single suite in a
+// fresh process, so GOMAXPROCS changes, GC state and heap growth from one
+// suite can't carry into the next. The child's report goes straight to our
+// stdout; its results and events are left in dir for the caller to merge.
+func RunIsolated(dir, suite string, args ...string) (IsolatedRun, error) {
+	run := IsolatedRun{
+		ResultPath: filepath.Join(dir, suite+".json"),
+		EventPath:  filepath.Join(dir, suite+".events.jsonl"),
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return run, fmt.Errorf("locating executable: %w", err)
+	}
+
+	childArgs := append([]string{
+		"-isolated-child",
+		"-suites", suite,
+		"-json", run.ResultPath,
+		"-events-jsonl", run.EventPath,
+	}, args...)
+	cmd := exec.Command(exe, childArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return run, fmt.Errorf("suite %s child process: %w", suite, err)
+	}
+	return run, nil
+}
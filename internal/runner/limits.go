@@ -0,0 +1,179 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// LoadPoint is one concurrency level of a LimitSweep or LoadCurve: a cap
+// on requests in flight or a number of clients offering load.
+type LoadPoint struct {
+	Concurrency int
+	Throughput  float64 // requests per second
+	Min         time.Duration
+	P50         time.Duration
+	P99         time.Duration
+}
+
+// LimitSweep serves requests requests of kind at each concurrency limit.
+func LimitSweep(kind workloads.RequestKind, limits []int, requests int) []LoadPoint {
+	points := make([]LoadPoint, 0, len(limits))
+	for _, limit := range limits {
+		Settle()
+		elapsed, latencies := workloads.RunBoundedRequests(kind, limit, requests)
+		points = append(points, LoadPoint{
+			Concurrency: limit,
+			Throughput:  float64(requests) / elapsed.Seconds(),
+			Min:         stats.Percentile(latencies, 0),
+			P50:         stats.Percentile(latencies, 50),
+			P99:         stats.Percentile(latencies, 99),
+		})
+	}
+	return points
+}
+
+// RecommendLimit picks the smallest limit whose p99 fits budget and whose
+// throughput is within 5% of the best that fits: past that point, more
+// concurrency only adds memory and contention. ok is false when no limit
+// meets the budget.
+func RecommendLimit(points []LoadPoint, budget time.Duration) (LoadPoint, bool) {
+	best := 0.0
+	for _, p := range points {
+		if p.P99 <= budget {
+			best = max(best, p.Throughput)
+		}
+	}
+	for _, p := range points {
+		if p.P99 <= budget && p.Throughput >= best*0.95 {
+			return p, true
+		}
+	}
+	return LoadPoint{}, false
+}
+
+// LoadCurve has each number of clients issue perClient requests of kind
+// back to back, tracing throughput against latency as offered load grows.
+func LoadCurve(kind workloads.RequestKind, clients []int, perClient int) []LoadPoint {
+	points := make([]LoadPoint, 0, len(clients))
+	for _, n := range clients {
+		Settle()
+		elapsed, latencies := workloads.RunClosedLoop(kind, n, perClient)
+		points = append(points, LoadPoint{
+			Concurrency: n,
+			Throughput:  float64(len(latencies)) / elapsed.Seconds(),
+			Min:         stats.Percentile(latencies, 0),
+			P50:         stats.Percentile(latencies, 50),
+			P99:         stats.Percentile(latencies, 99),
+		})
+	}
+	return points
+}
+
+// LoadKnee returns the last point before throughput stops keeping up with
+// latency: where the next step gains under 10% throughput but adds over
+// 50% p99. Without such a step it returns the last point.
+func LoadKnee(points []LoadPoint) LoadPoint {
+	for i := 0; i+1 < len(points); i++ {
+		cur, next := points[i], points[i+1]
+		if next.Throughput < cur.Throughput*1.1 && next.P99 > cur.P99*3/2 {
+			return cur
+		}
+	}
+	return points[len(points)-1]
+}
+
+// ArrivalPoint is one arrival process of an OpenLoopSweep.
+type ArrivalPoint struct {
+	Process    string
+	Offered    float64 // requests per second
+	Throughput float64 // requests per second
+	QueueP50   time.Duration
+	QueueP99   time.Duration
+	P50        time.Duration
+	P99        time.Duration
+	// Uncorrected percentiles time requests from when the generator sent
+	// them rather than when they were due
+	UncorrectedP50 time.Duration
+	UncorrectedP99 time.Duration
+}
+
+// OpenLoopSweep offers requests requests of kind at rate per second to
+// workers workers under each arrival process at procs, reporting the
+// queueing delay and latency each process causes at the same mean load.
+func OpenLoopSweep(kind workloads.RequestKind, processes []workloads.ArrivalProcess, rate float64, requests, workers, procs int) []ArrivalPoint {
+	points := make([]ArrivalPoint, 0, len(processes))
+	for _, p := range processes {
+		Settle()
+		run := workloads.RunOpenLoop(kind, p, rate, requests, workers, procs)
+		points = append(points, ArrivalPoint{
+			Process:        p.Name,
+			Offered:        rate,
+			Throughput:     float64(requests) / run.Elapsed.Seconds(),
+			QueueP50:       stats.Percentile(run.Queued, 50),
+			QueueP99:       stats.Percentile(run.Queued, 99),
+			P50:            stats.Percentile(run.Latencies, 50),
+			P99:            stats.Percentile(run.Latencies, 99),
+			UncorrectedP50: stats.Percentile(run.Uncorrected, 50),
+			UncorrectedP99: stats.Percentile(run.Uncorrected, 99),
+		})
+	}
+	return points
+}
+
+// GeneratorPoint is one load generator's view of the same server in a
+// CompareGenerators run.
+type GeneratorPoint struct {
+	Generator  string
+	Throughput float64 // requests per second
+	P50        time.Duration
+	P99        time.Duration
+	P999       time.Duration
+	Max        time.Duration
+}
+
+func generatorPoint(name string, requests int, elapsed time.Duration, latencies []time.Duration) GeneratorPoint {
+	return GeneratorPoint{
+		Generator:  name,
+		Throughput: float64(requests) / elapsed.Seconds(),
+		P50:        stats.Percentile(latencies, 50),
+		P99:        stats.Percentile(latencies, 99),
+		P999:       stats.Percentile(latencies, 99.9),
+		Max:        stats.Max(latencies),
+	}
+}
+
+// CompareGenerators drives kind at rate per second with requests
+// requests, freezing the server for stall halfway through each run:
+// workers closed-loop clients timed from when they sent, those timings
+// corrected HdrHistogram-style for the requests the clients held back,
+// the same clients timed from when they meant to send, and open-loop
+// steady arrivals to workers workers. The server and the stall are the same;
+// only what the generator records differs.
+func CompareGenerators(kind workloads.RequestKind, rate float64, requests, workers, procs int, stall time.Duration) []GeneratorPoint {
+	halfway := time.Duration(float64(requests) / rate / 2 * float64(time.Second))
+	stalled := func() (workloads.RequestKind, *time.Timer) {
+		var st workloads.Stall
+		return st.Wrap(kind), time.AfterFunc(halfway, func() { st.Freeze(stall) })
+	}
+
+	perClient := requests / workers
+	pace := time.Duration(float64(workers) / rate * float64(time.Second))
+	Settle()
+	k, timer := stalled()
+	elapsed, raw, corrected := workloads.RunPacedClosedLoop(k, workers, perClient, pace, procs)
+	timer.Stop()
+	points := []GeneratorPoint{
+		generatorPoint("closed, from send", perClient*workers, elapsed, raw),
+		generatorPoint("closed, HdrHistogram", perClient*workers, elapsed, stats.CorrectOmission(raw, pace)),
+		generatorPoint("closed, from schedule", perClient*workers, elapsed, corrected),
+	}
+
+	steady := workloads.ArrivalProcesses()[0] // evenly spaced, like the pace
+	Settle()
+	k, timer = stalled()
+	run := workloads.RunOpenLoop(k, steady, rate, requests, workers, procs)
+	timer.Stop()
+	return append(points, generatorPoint("open loop", requests, run.Elapsed, run.Latencies))
+}
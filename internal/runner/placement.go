@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/sysinfo"
+	"compare_process/internal/workloads"
+)
+
+// placementRuns is how many times ComparePlacements runs each kernel on
+// each placement, keeping the fastest
+const placementRuns = 3
+
+// PlacementResult is one kernel's time with one worker pinned alone and
+// with a pair of workers pinned to each placement.
+type PlacementResult struct {
+	Kernel string
+	Solo   time.Duration
+	// Pairs holds the time on each placement, in the order given
+	Pairs []time.Duration
+}
+
+// Slowdown is how much longer the pair on placement i took than one
+// worker alone: 1 when the two workers didn't get in each other's way.
+func (r PlacementResult) Slowdown(i int) float64 {
+	return float64(r.Pairs[i]) / float64(r.Solo)
+}
+
+// ComparePlacements runs each kernel alone on the first placement's first
+// CPU, then on each placement's pair of CPUs, pinning every worker's
+// thread.
+func ComparePlacements(kernels []workloads.PlacementKernel, placements []sysinfo.Placement) ([]PlacementResult, error) {
+	fastest := func(k workloads.PlacementKernel, cpus []int) (time.Duration, error) {
+		var best time.Duration
+		for i := 0; i < placementRuns; i++ {
+			Settle()
+			elapsed, err := workloads.RunPinned(k, cpus, sysinfo.PinThread)
+			if err != nil {
+				return 0, err
+			}
+			if i == 0 || elapsed < best {
+				best = elapsed
+			}
+		}
+		return best, nil
+	}
+
+	results := make([]PlacementResult, 0, len(kernels))
+	for _, k := range kernels {
+		r := PlacementResult{Kernel: k.Name}
+		var err error
+		if r.Solo, err = fastest(k, placements[0].CPUs[:1]); err != nil {
+			return nil, err
+		}
+		for _, p := range placements {
+			elapsed, err := fastest(k, p.CPUs)
+			if err != nil {
+				return nil, err
+			}
+			r.Pairs = append(r.Pairs, elapsed)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"time"
+
+	"compare_process/internal/sysinfo"
+	"compare_process/internal/workloads"
+)
+
+// Thresholds used to classify a workload from its profile
+const (
+	ioBoundUtilization  = 0.5 // below this, goroutines mostly wait rather than run
+	memoryBoundMissRate = 0.3 // above this, the caches aren't keeping up
+)
+
+// Profile is what we observe about one run of a workload: how much of the
+// available CPU it used, how it hit the caches and how long it spent
+// waiting on locks.
+type Profile struct {
+	Wall      time.Duration
+	CPU       time.Duration
+	CPUOK     bool
+	Procs     int
+	Perf      sysinfo.PerfCounters
+	PerfOK    bool
+	MutexWait time.Duration
+}
+
+// Utilization is the fraction of procs × wall time spent on-CPU.
+func (p Profile) Utilization() float64 {
+	if p.Wall == 0 || p.Procs == 0 {
+		return 0
+	}
+	return float64(p.CPU) / (float64(p.Wall) * float64(p.Procs))
+}
+
+// Classify turns the profile into a compute/memory/I/O-bound label and a
+// short reason, so the speedup numbers come with an explanation.
+func (p Profile) Classify() (string, string) {
+	if !p.CPUOK {
+		return "unknown", "CPU time unavailable on this platform"
+	}
+
+	util := p.Utilization()
+	if util < ioBoundUtilization {
+		return "I/O-bound", fmt.Sprintf("only %.0f%% of CPU time used; goroutines mostly blocked", util*100)
+	}
+	if !p.PerfOK {
+		return "CPU-bound", fmt.Sprintf("%.0f%% CPU used; perf unavailable to split compute vs memory", util*100)
+	}
+	if p.Perf.MissRate() > memoryBoundMissRate {
+		return "memory-bound", fmt.Sprintf("%.0f%% CPU used, %.0f%% cache misses", util*100, p.Perf.MissRate()*100)
+	}
+	return "compute-bound", fmt.Sprintf("%.0f%% CPU used, %.0f%% cache misses", util*100, p.Perf.MissRate()*100)
+}
+
+// ProfileWorkload runs w once at procs under the CPU-time, perf and
+// mutex-wait monitors.
+func ProfileWorkload(w workloads.Workload, procs int) Profile {
+	Settle()
+
+	perf, perfErr := sysinfo.StartPerf()
+	waitBefore := mutexWaitTotal()
+	cpuBefore, cpuOK := sysinfo.ProcessCPUTime()
+
+	wall := w.Run(procs, nil)
+
+	cpuAfter, _ := sysinfo.ProcessCPUTime()
+	profile := Profile{
+		Wall:      wall,
+		CPU:       cpuAfter - cpuBefore,
+		CPUOK:     cpuOK,
+		Procs:     procs,
+		MutexWait: mutexWaitTotal() - waitBefore,
+	}
+	if perfErr == nil {
+		if counters, err := perf.Stop(); err == nil {
+			profile.Perf = counters
+			profile.PerfOK = true
+		}
+	}
+	return profile
+}
+
+// mutexWaitTotal reads the cumulative time goroutines have spent blocked on
+// sync.Mutex/RWMutex.
+func mutexWaitTotal() time.Duration {
+	sample := []metrics.Sample{{Name: "/sync/mutex/wait/total:seconds"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	return time.Duration(sample[0].Value.Float64() * float64(time.Second))
+}
@@ -0,0 +1,242 @@
+package runner
+
+import (
+	"math"
+	"runtime/metrics"
+	"time"
+)
+
+// Names of the runtime/metrics samples a runtimeSnapshot reads
+const (
+	metricAllocBytes   = "/gc/heap/allocs:bytes"
+	metricAllocObjects = "/gc/heap/allocs:objects"
+	metricTinyAllocs   = "/gc/heap/tiny/allocs:objects"
+	metricGCCycles     = "/gc/cycles/total:gc-cycles"
+	metricGCPauses     = "/sched/pauses/total/gc:seconds"
+	metricSchedLatency = "/sched/latencies:seconds"
+	metricGoroutines   = "/sched/goroutines:goroutines"
+	metricHeapObjects  = "/memory/classes/heap/objects:bytes"
+	metricHeapUnused   = "/memory/classes/heap/unused:bytes"
+	metricStacks       = "/memory/classes/heap/stacks:bytes"
+)
+
+var runtimeMetrics = []string{
+	metricAllocBytes, metricAllocObjects, metricTinyAllocs, metricGCCycles, metricGCPauses,
+	metricSchedLatency, metricGoroutines, metricHeapObjects, metricHeapUnused, metricStacks,
+}
+
+// runtimeSnapshot is one read of the runtime's metrics. Unlike
+// runtime.ReadMemStats, reading them doesn't stop the world.
+type runtimeSnapshot struct {
+	allocBytes   uint64
+	allocObjects uint64
+	gcCycles     uint64
+	gcPauses     *metrics.Float64Histogram
+	schedLatency *metrics.Float64Histogram
+	goroutines   uint64
+	heap         uint64 // in-use heap spans: live objects plus their free slots
+	stacks       uint64
+}
+
+func readRuntime() runtimeSnapshot {
+	samples := make([]metrics.Sample, len(runtimeMetrics))
+	for i, name := range runtimeMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	var s runtimeSnapshot
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			v := sample.Value.Uint64()
+			switch sample.Name {
+			case metricAllocBytes:
+				s.allocBytes = v
+			case metricAllocObjects, metricTinyAllocs:
+				s.allocObjects += v
+			case metricGCCycles:
+				s.gcCycles = v
+			case metricGoroutines:
+				s.goroutines = v
+			case metricHeapObjects, metricHeapUnused:
+				s.heap += v
+			case metricStacks:
+				s.stacks = v
+			}
+		case metrics.KindFloat64Histogram:
+			switch sample.Name {
+			case metricGCPauses:
+				s.gcPauses = sample.Value.Float64Histogram()
+			case metricSchedLatency:
+				s.schedLatency = sample.Value.Float64Histogram()
+			}
+		}
+	}
+	return s
+}
+
+// Bucket is one non-empty bucket of a Histogram. An open-ended top bucket
+// has HiNS set to math.MaxInt64.
+type Bucket struct {
+	LoNS  int64  `json:"lo_ns"`
+	HiNS  int64  `json:"hi_ns"`
+	Count uint64 `json:"count"`
+}
+
+// Histogram is the change in a runtime/metrics time histogram across a
+// measurement, keeping only the buckets that gained samples.
+type Histogram []Bucket
+
+// histogramDelta subtracts before from after; both must come from the same
+// metric, so their buckets line up.
+func histogramDelta(after, before *metrics.Float64Histogram) Histogram {
+	if after == nil {
+		return nil
+	}
+	var h Histogram
+	for i, count := range after.Counts {
+		if before != nil && i < len(before.Counts) {
+			count -= before.Counts[i]
+		}
+		if count == 0 {
+			continue
+		}
+		h = append(h, Bucket{
+			LoNS:  boundNS(after.Buckets[i]),
+			HiNS:  boundNS(after.Buckets[i+1]),
+			Count: count,
+		})
+	}
+	return h
+}
+
+// boundNS converts a bucket boundary in seconds to nanoseconds, clamping
+// the infinite ends so they survive JSON.
+func boundNS(seconds float64) int64 {
+	switch {
+	case math.IsInf(seconds, -1) || seconds < 0:
+		return 0
+	case math.IsInf(seconds, 1) || seconds >= float64(math.MaxInt64)/1e9:
+		return math.MaxInt64
+	}
+	return int64(seconds * 1e9)
+}
+
+func (h Histogram) Total() uint64 {
+	var total uint64
+	for _, b := range h {
+		total += b.Count
+	}
+	return total
+}
+
+// Quantile returns the upper bound of the bucket holding the q-th quantile
+// (0-1), so it never understates a latency. An open-ended top bucket
+// reports its lower bound.
+func (h Histogram) Quantile(q float64) time.Duration {
+	total := h.Total()
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for _, b := range h {
+		seen += b.Count
+		if seen >= max(rank, 1) {
+			if b.HiNS == math.MaxInt64 {
+				return time.Duration(b.LoNS)
+			}
+			return time.Duration(b.HiNS)
+		}
+	}
+	return time.Duration(h[len(h)-1].LoNS)
+}
+
+// Sum estimates the total time recorded, counting each sample at its
+// bucket's midpoint.
+func (h Histogram) Sum() time.Duration {
+	var sum float64
+	for _, b := range h {
+		hi := b.HiNS
+		if hi == math.MaxInt64 {
+			hi = b.LoNS
+		}
+		sum += float64(b.Count) * float64(b.LoNS+hi) / 2
+	}
+	return time.Duration(sum)
+}
+
+// Merge adds o's buckets to h's, keeping them sorted.
+func (h Histogram) Merge(o Histogram) Histogram {
+	merged := make(Histogram, 0, len(h)+len(o))
+	i, j := 0, 0
+	for i < len(h) || j < len(o) {
+		switch {
+		case j == len(o) || (i < len(h) && h[i].LoNS < o[j].LoNS):
+			merged = append(merged, h[i])
+			i++
+		case i == len(h) || o[j].LoNS < h[i].LoNS:
+			merged = append(merged, o[j])
+			j++
+		default:
+			b := h[i]
+			b.Count += o[j].Count
+			merged = append(merged, b)
+			i++
+			j++
+		}
+	}
+	return merged
+}
+
+// RuntimeStats is what the scheduler and collector recorded while one
+// mode's runs executed, from runtime/metrics.
+type RuntimeStats struct {
+	// SchedLatency is how long goroutines sat runnable before running
+	SchedLatency Histogram `json:"sched_latency,omitempty"`
+	// GCPauses are the collector's stop-the-world pauses
+	GCPauses Histogram `json:"gc_pauses,omitempty"`
+	GCCycles uint64    `json:"gc_cycles"`
+	// Goroutines is the most left alive after a run; growth across runs
+	// points at a leak
+	Goroutines uint64 `json:"goroutines"`
+}
+
+func runtimeDelta(after, before runtimeSnapshot) RuntimeStats {
+	return RuntimeStats{
+		SchedLatency: histogramDelta(after.schedLatency, before.schedLatency),
+		GCPauses:     histogramDelta(after.gcPauses, before.gcPauses),
+		GCCycles:     after.gcCycles - before.gcCycles,
+		Goroutines:   after.goroutines,
+	}
+}
+
+func (r *RuntimeStats) add(o RuntimeStats) {
+	r.SchedLatency = r.SchedLatency.Merge(o.SchedLatency)
+	r.GCPauses = r.GCPauses.Merge(o.GCPauses)
+	r.GCCycles += o.GCCycles
+	r.Goroutines = max(r.Goroutines, o.Goroutines)
+}
+
+// SuiteSched is the scheduler latency recorded while one suite ran, across
+// every GOMAXPROCS setting the suite used.
+type SuiteSched struct {
+	Suite        string    `json:"suite"`
+	SchedLatency Histogram `json:"sched_latency,omitempty"`
+}
+
+// SchedWatch brackets a stretch of work with reads of the scheduler
+// latency histogram.
+type SchedWatch struct {
+	before *metrics.Float64Histogram
+}
+
+func StartSchedWatch() SchedWatch {
+	return SchedWatch{before: readRuntime().schedLatency}
+}
+
+// Stop returns what the histogram gained since StartSchedWatch.
+func (w SchedWatch) Stop() Histogram {
+	return histogramDelta(readRuntime().schedLatency, w.before)
+}
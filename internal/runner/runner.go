@@ -0,0 +1,366 @@
+// Package runner executes workloads under controlled runtime conditions:
+// repeated concurrent vs parallel comparisons, profiling, GOMAXPROCS
+// sweeps, perturbations and child-process experiments.
+package runner
+
+import (
+	"context"
+	"maps"
+	"math"
+	"math/rand"
+	"runtime"
+	"slices"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// Result holds the raw timings of one workload at GOMAXPROCS=1
+// (concurrent) and GOMAXPROCS=Procs (parallel).
+type Result struct {
+	Workload   string
+	Tasks      int
+	Procs      int
+	Concurrent []time.Duration
+	Parallel   []time.Duration
+
+	// Counters are the workload's own counters averaged per run; Gauges
+	// hold their last recorded values
+	Counters map[string]float64
+	Gauges   map[string]float64
+
+	// Heap allocations of each mode's runs, for B/op and allocs/op
+	ConcurrentAllocs Allocs
+	ParallelAllocs   Allocs
+
+	// Scheduler latency and GC pauses over each mode's runs
+	ConcurrentRuntime RuntimeStats
+	ParallelRuntime   RuntimeStats
+
+	// Per-operation timings from inside each mode's tasks, when the
+	// workload's micro timing was on
+	ConcurrentMicro Micro
+	ParallelMicro   Micro
+
+	// Timings an outlier rule took out of Concurrent and Parallel, and the
+	// zero-based iteration each of them ran in
+	ConcurrentOutliers     []time.Duration
+	ParallelOutliers       []time.Duration
+	ConcurrentOutlierIters []int
+	ParallelOutlierIters   []int
+	// Robust centers and spreads the timings on their median and MAD
+	// instead of their mean and standard deviation
+	Robust bool
+	// Paired says Concurrent[i] and Parallel[i] ran back to back, so the
+	// modes can be compared pair by pair
+	Paired bool
+	// Window, if set, is how long each timing kept rerunning the workload;
+	// the timing is the average of the runs that completed in it
+	Window time.Duration
+}
+
+// RejectOutliers moves the timings rule discards from each mode into its
+// outliers, so every statistic works on the rest. A paired result loses
+// whole pairs, both timings of any iteration either mode discards, so
+// the pairs stay lined up.
+func (r Result) RejectOutliers(rule stats.OutlierRule) Result {
+	c := stats.OutlierIndices(r.Concurrent, rule)
+	p := stats.OutlierIndices(r.Parallel, rule)
+	if r.Paired && len(r.Concurrent) == len(r.Parallel) {
+		c = slices.Compact(slices.Sorted(slices.Values(append(c, p...))))
+		p = c
+	}
+	r.Concurrent = moveOutliers(r.Concurrent, c, &r.ConcurrentOutliers, &r.ConcurrentOutlierIters)
+	r.Parallel = moveOutliers(r.Parallel, p, &r.ParallelOutliers, &r.ParallelOutlierIters)
+	return r
+}
+
+// moveOutliers returns runs without the timings at positions out, which
+// it adds to outliers with the iteration each ran in added to iters.
+func moveOutliers(runs []time.Duration, out []int, outliers *[]time.Duration, iters *[]int) []time.Duration {
+	if len(out) == 0 {
+		return runs
+	}
+	ran := KeptIterations(len(runs), *iters)
+	var kept []time.Duration
+	for i, d := range runs {
+		if slices.Contains(out, i) {
+			*outliers = append(*outliers, d)
+			*iters = append(*iters, ran[i])
+		} else {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// KeptIterations returns the zero-based iteration each of n kept timings ran
+// in, given the iterations the outliers taken from among them ran in.
+func KeptIterations(n int, removed []int) []int {
+	iters := make([]int, 0, n)
+	for i := 0; len(iters) < n; i++ {
+		if !slices.Contains(removed, i) {
+			iters = append(iters, i)
+		}
+	}
+	return iters
+}
+
+// Center is the typical timing of runs, one of r's modes: the median when
+// r is robust, else the mean.
+func (r Result) Center(runs []time.Duration) time.Duration {
+	if r.Robust {
+		return stats.Median(runs)
+	}
+	return stats.Average(runs)
+}
+
+// Spread is how far runs stray from their center: the MAD when r is
+// robust, else the standard deviation.
+func (r Result) Spread(runs []time.Duration) time.Duration {
+	if r.Robust {
+		return stats.MAD(runs)
+	}
+	return stats.StdDev(runs)
+}
+
+// Rate is an amount of work completed per second in each mode.
+type Rate struct {
+	Unit       string // what is counted, e.g. "tasks"
+	Concurrent float64
+	Parallel   float64
+}
+
+// Rates are r's throughput in each mode at its centered timings: tasks
+// per second, then each of the workload's counters per second, such as
+// primes found or I/O requests served.
+func (r Result) Rates() []Rate {
+	concurrent, parallel := r.Center(r.Concurrent).Seconds(), r.Center(r.Parallel).Seconds()
+	if concurrent <= 0 || parallel <= 0 {
+		return nil
+	}
+	rate := func(unit string, perRun float64) Rate {
+		return Rate{Unit: unit, Concurrent: perRun / concurrent, Parallel: perRun / parallel}
+	}
+
+	rates := []Rate{rate("tasks", float64(r.Tasks))}
+	for _, name := range slices.Sorted(maps.Keys(r.Counters)) {
+		rates = append(rates, rate(name, r.Counters[name]))
+	}
+	return rates
+}
+
+// Micro is what micro timing recorded over one mode's runs: how many
+// operations the tasks timed and their durations summed over goroutines.
+type Micro struct {
+	Ops     float64 `json:"ops"`
+	TotalNS float64 `json:"total_ns"`
+}
+
+// PerOp is the mean operation time, or zero without micro timings.
+func (m Micro) PerOp() time.Duration {
+	if m.Ops == 0 {
+		return 0
+	}
+	return time.Duration(m.TotalNS / m.Ops)
+}
+
+// MicroSpeedup is how much faster one operation got in parallel. Unlike
+// Speedup it ignores how many operations ran at once, so it stays near 1
+// for work that parallelism spreads out but cannot make faster.
+func (r Result) MicroSpeedup() float64 {
+	if r.ParallelMicro.PerOp() == 0 {
+		return 0
+	}
+	return float64(r.ConcurrentMicro.PerOp()) / float64(r.ParallelMicro.PerOp())
+}
+
+func (r Result) Speedup() float64 {
+	return float64(r.Center(r.Concurrent)) / float64(r.Center(r.Parallel))
+}
+
+// MaxSpeedup is the best speedup Procs cores can give Tasks equal
+// goroutines: they finish in ceil(Tasks/Procs) waves instead of Tasks, so
+// fewer goroutines than cores, or a partial last wave, caps it below
+// Procs. Without a task count it is Procs.
+func (r Result) MaxSpeedup() float64 {
+	if r.Tasks <= 0 || r.Procs <= 0 {
+		return float64(r.Procs)
+	}
+	waves := (r.Tasks + r.Procs - 1) / r.Procs
+	return float64(r.Tasks) / float64(waves)
+}
+
+// Efficiency is the speedup as a percentage of MaxSpeedup.
+func (r Result) Efficiency() float64 {
+	return r.Speedup() / r.MaxSpeedup() * 100
+}
+
+// CV is the worse of the two modes' coefficients of variation, in percent,
+// or NaN when there weren't enough runs to tell.
+func (r Result) CV() float64 {
+	return max(stats.CoefficientOfVariation(r.Concurrent), stats.CoefficientOfVariation(r.Parallel)) * 100
+}
+
+// Settle forces a garbage collection and pauses briefly, so one run's
+// garbage and background work don't leak into the next measurement.
+func Settle() {
+	runtime.GC()
+	time.Sleep(10 * time.Millisecond)
+}
+
+// CILevel is the confidence level of the intervals adaptive iteration
+// narrows
+const CILevel = 0.95
+
+// Iterations says how often Compare runs a workload: Min times, or with
+// RelativeCI set, on until the 95% confidence interval of each mode's mean
+// is within ±RelativeCI of it (0.02 for ±2%), at most Max times. With
+// Window set, each iteration reruns the workload until Window has passed,
+// like testing.B's -benchtime, and times the average run. OrderSeed seeds
+// the coin tosses that order the modes, so a seed replays the order.
+type Iterations struct {
+	Min        int
+	Max        int
+	RelativeCI float64
+	Window     time.Duration
+	OrderSeed  int64
+}
+
+// Fixed runs exactly n iterations.
+func Fixed(n int) Iterations { return Iterations{Min: n, Max: n} }
+
+// done reports whether r has enough iterations.
+func (it Iterations) done(r Result) bool {
+	n := len(r.Concurrent)
+	if n < it.Min {
+		return false
+	}
+	if it.RelativeCI == 0 || n >= it.Max {
+		return true
+	}
+	return r.RelativeCI(r.Concurrent) <= it.RelativeCI && r.RelativeCI(r.Parallel) <= it.RelativeCI
+}
+
+// RelativeCI is the half-width of the 95% confidence interval of the mean
+// of runs, one of r's two modes, relative to that mean.
+func (r Result) RelativeCI(runs []time.Duration) float64 {
+	if len(runs) < 2 {
+		return math.Inf(1)
+	}
+	return float64(stats.MeanCI(runs, CILevel)) / float64(stats.Average(runs))
+}
+
+// Compare runs w concurrently and in parallel on procs Ps as often as
+// iterations says, settling the runtime before every run. The two modes
+// alternate in A/B/B/A blocks whose first order is a coin toss, so drift
+// in clock speed or temperature over the session falls on both alike and
+// each iteration's two timings form a pair. The tosses are drawn from
+// iterations.OrderSeed. onIteration, if set, is
+// called after each iteration with its two timings. If ctx is done before
+// an iteration starts, Compare returns the iterations so far with ctx's
+// error.
+func Compare(ctx context.Context, w workloads.Workload, procs int, iterations Iterations, onIteration func(i int, concurrent, parallel time.Duration)) (Result, error) {
+	result := Result{Workload: w.Name(), Tasks: w.Tasks(), Procs: procs, Paired: true, Window: iterations.Window}
+	cm, pm := workloads.NewMetrics(), workloads.NewMetrics()
+	runs := 0
+
+	rng := rand.New(rand.NewSource(iterations.OrderSeed))
+	concurrentFirst := false
+	for i := 0; !iterations.done(result); i++ {
+		if err := ctx.Err(); err != nil {
+			result.recordMetrics(cm, pm, runs)
+			return result, err
+		}
+		// A new block every other iteration, mirrored in the second half
+		if i%2 == 0 {
+			concurrentFirst = rng.Intn(2) == 0
+		} else {
+			concurrentFirst = !concurrentFirst
+		}
+
+		var concurrent, parallel time.Duration
+		var concurrentAllocs, parallelAllocs Allocs
+		var concurrentRuntime, parallelRuntime RuntimeStats
+		var concurrentRuns, parallelRuns int
+		runConcurrent := func() {
+			Settle()
+			concurrent, concurrentAllocs, concurrentRuntime, concurrentRuns = measureWindow(w, 1, cm, iterations.Window)
+		}
+		runParallel := func() {
+			Settle()
+			parallel, parallelAllocs, parallelRuntime, parallelRuns = measureWindow(w, procs, pm, iterations.Window)
+		}
+		if concurrentFirst {
+			runConcurrent()
+			runParallel()
+		} else {
+			runParallel()
+			runConcurrent()
+		}
+
+		runs += concurrentRuns + parallelRuns
+		result.ConcurrentAllocs.add(concurrentAllocs)
+		result.ParallelAllocs.add(parallelAllocs)
+		result.ConcurrentRuntime.add(concurrentRuntime)
+		result.ParallelRuntime.add(parallelRuntime)
+		result.Concurrent = append(result.Concurrent, concurrent)
+		result.Parallel = append(result.Parallel, parallel)
+		if onIteration != nil {
+			onIteration(i, concurrent, parallel)
+		}
+	}
+	result.recordMetrics(cm, pm, runs)
+	return result, nil
+}
+
+// RunOnce times w once concurrently and once in parallel on procs Ps,
+// without settling in between.
+func RunOnce(w workloads.Workload, procs int) Result {
+	cm, pm := workloads.NewMetrics(), workloads.NewMetrics()
+	concurrent, concurrentAllocs, concurrentRuntime := measure(w, 1, cm)
+	parallel, parallelAllocs, parallelRuntime := measure(w, procs, pm)
+
+	result := Result{
+		Workload:         w.Name(),
+		Tasks:            w.Tasks(),
+		Procs:            procs,
+		Concurrent:       []time.Duration{concurrent},
+		Parallel:         []time.Duration{parallel},
+		ConcurrentAllocs: concurrentAllocs,
+		ParallelAllocs:   parallelAllocs,
+
+		ConcurrentRuntime: concurrentRuntime,
+		ParallelRuntime:   parallelRuntime,
+	}
+	result.recordMetrics(cm, pm, 2)
+	return result
+}
+
+// recordMetrics averages both modes' counters over the runs of the
+// workload, keeps the gauges the parallel runs, which ran last, recorded,
+// and moves the micro timing counters into each mode's Micro.
+func (r *Result) recordMetrics(concurrent, parallel *workloads.Metrics, runs int) {
+	cc, pc := concurrent.Counters(), parallel.Counters()
+	r.ConcurrentMicro = takeMicro(cc)
+	r.ParallelMicro = takeMicro(pc)
+
+	r.Counters = cc
+	for name, total := range pc {
+		r.Counters[name] += total
+	}
+	for name, total := range r.Counters {
+		r.Counters[name] = total / float64(max(runs, 1))
+	}
+	r.Gauges = concurrent.Gauges()
+	maps.Copy(r.Gauges, parallel.Gauges())
+}
+
+// takeMicro removes the micro timing counters from counters.
+func takeMicro(counters map[string]float64) Micro {
+	m := Micro{Ops: counters[workloads.MicroOps], TotalNS: counters[workloads.MicroNS]}
+	delete(counters, workloads.MicroOps)
+	delete(counters, workloads.MicroNS)
+	return m
+}
@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// modeLog is a workload that takes 4ms at GOMAXPROCS=1 and 1ms otherwise
+// without running anything, and logs the modes in the order they ran.
+type modeLog struct {
+	modes []string
+}
+
+func (l *modeLog) workload() workloads.Workload {
+	return workloads.New("log", 1, func(maxProcs int, _ *workloads.Metrics) time.Duration {
+		if maxProcs == 1 {
+			l.modes = append(l.modes, "concurrent")
+			return 4 * time.Millisecond
+		}
+		l.modes = append(l.modes, "parallel")
+		return time.Millisecond
+	})
+}
+
+func TestComparePairs(t *testing.T) {
+	const iterations = 6
+	var log modeLog
+	var seen []int
+	r, err := Compare(context.Background(), log.workload(), 2, Iterations{Min: iterations, Max: iterations, OrderSeed: 1},
+		func(i int, concurrent, parallel time.Duration) {
+			seen = append(seen, i)
+			if concurrent != 4*time.Millisecond || parallel != time.Millisecond {
+				t.Errorf("iteration %d timed %v and %v, want 4ms and 1ms", i, concurrent, parallel)
+			}
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Paired || len(r.Concurrent) != iterations || len(r.Parallel) != iterations {
+		t.Fatalf("result paired %v with %d and %d timings, want paired with %d each",
+			r.Paired, len(r.Concurrent), len(r.Parallel), iterations)
+	}
+	if !slices.Equal(seen, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("onIteration saw %v", seen)
+	}
+
+	// Each iteration runs both modes, and the second of a block mirrors
+	// the first
+	for i := 0; i < iterations; i++ {
+		pair := log.modes[2*i : 2*i+2]
+		if pair[0] == pair[1] {
+			t.Fatalf("iteration %d ran %v", i, pair)
+		}
+		if i%2 == 1 && pair[0] != log.modes[2*i-1] {
+			t.Errorf("iteration %d ran %v after %v, want the block mirrored", i, pair, log.modes[2*i-2:2*i])
+		}
+	}
+}
+
+// TestCompareOrderSeed checks the seed alone decides the order of the
+// modes.
+func TestCompareOrderSeed(t *testing.T) {
+	order := func(seed int64) []string {
+		var log modeLog
+		if _, err := Compare(context.Background(), log.workload(), 2, Iterations{Min: 16, Max: 16, OrderSeed: seed}, nil); err != nil {
+			t.Fatal(err)
+		}
+		return log.modes
+	}
+	first := order(7)
+	if again := order(7); !slices.Equal(again, first) {
+		t.Errorf("seed 7 ran\n%v\nthen\n%v", first, again)
+	}
+	differs := false
+	for seed := int64(8); seed < 12 && !differs; seed++ {
+		differs = !slices.Equal(order(seed), first)
+	}
+	if !differs {
+		t.Errorf("seeds 7 to 11 all ran %v", first)
+	}
+}
+
+func TestCompareCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var log modeLog
+	r, err := Compare(ctx, log.workload(), 2, Fixed(4), func(i int, _, _ time.Duration) {
+		if i == 1 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+	if len(r.Concurrent) != 2 || len(r.Parallel) != 2 {
+		t.Errorf("cancelled after 2 iterations, kept %d and %d timings", len(r.Concurrent), len(r.Parallel))
+	}
+}
+
+func TestRejectOutliersPaired(t *testing.T) {
+	r := Result{
+		Concurrent: []time.Duration{20, 21, 20, 22, 21, 20, 90},
+		Parallel:   []time.Duration{10, 11, 40, 10, 11, 10, 11},
+		Paired:     true,
+	}.RejectOutliers(stats.Tukey)
+
+	// Either mode's outlier takes its whole pair
+	want := Result{
+		Concurrent:             []time.Duration{20, 21, 22, 21, 20},
+		Parallel:               []time.Duration{10, 11, 10, 11, 10},
+		ConcurrentOutliers:     []time.Duration{20, 90},
+		ParallelOutliers:       []time.Duration{40, 11},
+		ConcurrentOutlierIters: []int{2, 6},
+		ParallelOutlierIters:   []int{2, 6},
+		Paired:                 true,
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("paired rejection gave\n%+v\nwant\n%+v", r, want)
+	}
+
+	// A second pass knows which iterations the kept timings ran in
+	r.Concurrent[1] = 500
+	r = r.RejectOutliers(stats.Tukey)
+	if !slices.Equal(r.ConcurrentOutlierIters, []int{2, 6, 1}) || !slices.Equal(r.ParallelOutlierIters, []int{2, 6, 1}) {
+		t.Errorf("second pass outlier iterations %v and %v, want [2 6 1]", r.ConcurrentOutlierIters, r.ParallelOutlierIters)
+	}
+}
+
+func TestRejectOutliersUnpaired(t *testing.T) {
+	r := Result{
+		Concurrent: []time.Duration{20, 21, 20, 22, 90},
+		Parallel:   []time.Duration{10, 40, 11, 10, 11},
+	}.RejectOutliers(stats.Tukey)
+	if !slices.Equal(r.ConcurrentOutlierIters, []int{4}) || !slices.Equal(r.ParallelOutlierIters, []int{1}) {
+		t.Errorf("outlier iterations %v and %v, want [4] and [1]", r.ConcurrentOutlierIters, r.ParallelOutlierIters)
+	}
+	if len(r.Concurrent) != 4 || len(r.Parallel) != 4 {
+		t.Errorf("kept %v and %v", r.Concurrent, r.Parallel)
+	}
+}
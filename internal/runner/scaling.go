@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"cmp"
+	"math"
+	"runtime"
+	"slices"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// ScalingCell is the scalability workload's median time at one goroutine
+// count and GOMAXPROCS, and its speedup over one goroutine on one P.
+type ScalingCell struct {
+	Goroutines int           `json:"goroutines"`
+	Procs      int           `json:"procs"`
+	Elapsed    time.Duration `json:"elapsed_ns"`
+	Speedup    float64       `json:"speedup"`
+}
+
+// ScalingMatrix times the scalability workload at every combination of
+// goroutines and procs, the median of iterations settled runs apiece.
+// Both lists should start at 1, the baseline of every speedup.
+func ScalingMatrix(goroutines, procs []int, iterations int) []ScalingCell {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	cells := make([]ScalingCell, 0, len(goroutines)*len(procs))
+	for _, g := range goroutines {
+		for _, p := range procs {
+			var times []time.Duration
+			for i := 0; i < iterations; i++ {
+				Settle()
+				times = append(times, workloads.RunScalabilityTest(g, p))
+			}
+			cell := ScalingCell{Goroutines: g, Procs: p, Elapsed: stats.Median(times), Speedup: 1}
+			if len(cells) > 0 {
+				cell.Speedup = float64(cells[0].Elapsed) / float64(cell.Elapsed)
+			}
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// USL is a Universal Scalability Law model of relative throughput:
+// C(N) = N / (1 + σ(N-1) + κN(N-1)). Sigma is the cost of contention for
+// shared resources, which flattens the curve; Kappa is the cost of
+// coherence between workers (crosstalk), which bends it back down.
+type USL struct {
+	Sigma float64
+	Kappa float64
+}
+
+// Speedup is the throughput the model predicts at n workers, relative to
+// one.
+func (u USL) Speedup(n float64) float64 {
+	return n / (1 + u.Sigma*(n-1) + u.Kappa*n*(n-1))
+}
+
+// Peak is the worker count with the most throughput, √((1-σ)/κ). ok is
+// false without coherence cost, when throughput never turns down.
+func (u USL) Peak() (n float64, ok bool) {
+	if u.Kappa <= 0 {
+		return 0, false
+	}
+	return math.Sqrt((1 - u.Sigma) / u.Kappa), true
+}
+
+// FitUSL fits the USL by least squares to the goroutine counts of cells
+// at their largest GOMAXPROCS, where adding goroutines can add
+// parallelism, each relative to one goroutine at that GOMAXPROCS. ok is
+// false without three goroutine counts to fit.
+//
+// N/C(N) - 1 = σ(N-1) + κN(N-1) is linear in σ and κ, so the fit solves
+// its two normal equations.
+func FitUSL(cells []ScalingCell) (model USL, ok bool) {
+	procs := 0
+	for _, c := range cells {
+		procs = max(procs, c.Procs)
+	}
+	var base time.Duration
+	for _, c := range cells {
+		if c.Procs == procs && c.Goroutines == 1 {
+			base = c.Elapsed
+		}
+	}
+	if base <= 0 {
+		return USL{}, false
+	}
+
+	var aa, ab, bb, ay, by float64
+	points := 0
+	for _, c := range cells {
+		if c.Procs != procs || c.Elapsed <= 0 {
+			continue
+		}
+		n := float64(c.Goroutines)
+		y := n/(float64(base)/float64(c.Elapsed)) - 1
+		a, b := n-1, n*(n-1)
+		aa, ab, bb = aa+a*a, ab+a*b, bb+b*b
+		ay, by = ay+a*y, by+b*y
+		points++
+	}
+	det := aa*bb - ab*ab
+	if points < 3 || det == 0 {
+		return USL{}, false
+	}
+	model = USL{Sigma: (ay*bb - by*ab) / det, Kappa: (by*aa - ay*ab) / det}
+
+	// A negative parameter has no physical meaning; refit the other alone
+	switch {
+	case model.Kappa < 0:
+		model = USL{Sigma: max(ay/aa, 0)}
+	case model.Sigma < 0:
+		model = USL{Kappa: max(by/bb, 0)}
+	}
+	model.Sigma = min(model.Sigma, 1)
+	return model, true
+}
+
+// UnevenRun is one run of the scalability sum cut into tasks of growing
+// size, split one way across its goroutines.
+type UnevenRun struct {
+	Static   bool
+	Elapsed  time.Duration
+	Finished []time.Duration // when each goroutine ran out of tasks
+}
+
+// Mode names how r split its tasks.
+func (r UnevenRun) Mode() string {
+	if r.Static {
+		return "static"
+	}
+	return "queue"
+}
+
+// Imbalance is the latest goroutine's finish over the mean finish: 1 when
+// every goroutine ran out of work together, higher the longer the rest
+// waited on the slowest.
+func (r UnevenRun) Imbalance() float64 {
+	return float64(stats.Max(r.Finished)) / float64(stats.Average(r.Finished))
+}
+
+// Idle is the share of the goroutines' time spent finished while others
+// still worked, the capacity an uneven split wastes.
+func (r UnevenRun) Idle() float64 {
+	var idle time.Duration
+	for _, f := range r.Finished {
+		idle += r.Elapsed - f
+	}
+	return float64(idle) / float64(r.Elapsed*time.Duration(len(r.Finished)))
+}
+
+// CompareUnevenSplits times the uneven scalability sum on goroutines
+// goroutines at procs, statically split and through a shared queue,
+// keeping each one's median run of iterations.
+func CompareUnevenSplits(goroutines, procs, iterations int) []UnevenRun {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	var out []UnevenRun
+	for _, static := range []bool{true, false} {
+		var runs []UnevenRun
+		for i := 0; i < iterations; i++ {
+			Settle()
+			elapsed, finished := workloads.RunUnevenScalabilityTest(goroutines, procs, static)
+			runs = append(runs, UnevenRun{Static: static, Elapsed: elapsed, Finished: finished})
+		}
+		slices.SortFunc(runs, func(a, b UnevenRun) int { return cmp.Compare(a.Elapsed, b.Elapsed) })
+		out = append(out, runs[len(runs)/2])
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// SpawnPoint is the cost of starting a batch of short-lived goroutines at
+// one GOMAXPROCS.
+type SpawnPoint struct {
+	Goroutines, Procs int
+	Elapsed           time.Duration // median time to spawn and reap them all
+	P50, P99          time.Duration // wait from go statement to first instruction
+}
+
+// Rate is the goroutines started and finished per second.
+func (p SpawnPoint) Rate() float64 { return float64(p.Goroutines) / p.Elapsed.Seconds() }
+
+// PerSpawn is the wall time each goroutine adds to the batch.
+func (p SpawnPoint) PerSpawn() time.Duration { return p.Elapsed / time.Duration(p.Goroutines) }
+
+// MeasureSpawn times n goroutines spawned at procs, the median of
+// iterations settled runs, then records their start latencies in one
+// more run.
+func MeasureSpawn(n, procs, iterations int) SpawnPoint {
+	var times []time.Duration
+	for i := 0; i < iterations; i++ {
+		Settle()
+		times = append(times, workloads.SpawnGoroutines(n, procs))
+	}
+	Settle()
+	latencies := workloads.SpawnLatencies(n, procs)
+	return SpawnPoint{
+		Goroutines: n,
+		Procs:      procs,
+		Elapsed:    stats.Median(times),
+		P50:        stats.Percentile(latencies, 50),
+		P99:        stats.Percentile(latencies, 99),
+	}
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"slices"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/workloads"
+)
+
+// StreamPoint is the stream aggregated by one shard count, with GOMAXPROCS
+// set to match.
+type StreamPoint struct {
+	Shards int
+	// EventsPerSec is the median run's ingest rate
+	EventsPerSec float64
+	// P50 and P99 are the median run's window completeness latencies
+	P50 time.Duration
+	P99 time.Duration
+	// Agrees is whether every run's checksum matched the first shard
+	// count's
+	Agrees bool
+}
+
+// StreamSweep generates spec's stream once and aggregates it at each shard
+// count, keeping the median of iterations runs by elapsed time.
+func StreamSweep(spec workloads.StreamSpec, shards []int, iterations int) []StreamPoint {
+	events := spec.Generate()
+	var want int64
+	points := make([]StreamPoint, 0, len(shards))
+	for i, n := range shards {
+		var runs []workloads.StreamResult
+		agrees := true
+		for j := 0; j < iterations; j++ {
+			Settle()
+			r := workloads.RunStream(spec, events, n, n)
+			if i == 0 && j == 0 {
+				want = r.Checksum
+			}
+			agrees = agrees && r.Checksum == want
+			runs = append(runs, r)
+		}
+		slices.SortFunc(runs, func(a, b workloads.StreamResult) int { return int(a.Elapsed - b.Elapsed) })
+		median := runs[len(runs)/2]
+		points = append(points, StreamPoint{
+			Shards:       n,
+			EventsPerSec: float64(len(events)) / median.Elapsed.Seconds(),
+			P50:          stats.Percentile(median.Latencies, 50),
+			P99:          stats.Percentile(median.Latencies, 99),
+			Agrees:       agrees,
+		})
+	}
+	return points
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"math"
+	"time"
+
+	"compare_process/internal/stats"
+	"compare_process/internal/sysinfo"
+	"compare_process/internal/workloads"
+)
+
+// ProcsSweep returns 1, 2, 4, ... up to and including limit.
+func ProcsSweep(limit int) []int {
+	var counts []int
+	for p := 1; p < limit; p *= 2 {
+		counts = append(counts, p)
+	}
+	return append(counts, limit)
+}
+
+// ProcsLimit is the most Ps this process can usefully run: NumCPU capped
+// by the affinity mask and, like automaxprocs, the container CPU quota
+// rounded down.
+type ProcsLimit struct {
+	NumCPU      int
+	Affinity    int
+	AffinityErr error
+	Quota       float64
+	QuotaOK     bool
+	Limit       int
+}
+
+func DetectProcsLimit(numCPU int) ProcsLimit {
+	l := ProcsLimit{NumCPU: numCPU, Limit: numCPU}
+
+	if cpus, err := sysinfo.AffinityCPUs(); err == nil {
+		l.Affinity = len(cpus)
+		l.Limit = min(l.Limit, len(cpus))
+	} else {
+		l.AffinityErr = err
+	}
+
+	if l.Quota, l.QuotaOK = sysinfo.CPUQuota(); l.QuotaOK {
+		// Never plan on more CPU time than the quota grants, but always
+		// leave at least one P
+		l.Limit = min(l.Limit, l.QuotaProcs())
+	}
+	return l
+}
+
+// QuotaProcs is the container quota rounded down to whole Ps.
+func (l ProcsLimit) QuotaProcs() int {
+	return max(1, int(math.Floor(l.Quota)))
+}
+
+// CurvePoint is a workload's median time at one GOMAXPROCS value and its
+// speedup over GOMAXPROCS=1.
+type CurvePoint struct {
+	Procs   int
+	Elapsed time.Duration
+	Speedup float64
+	// MaxSpeedup is what Procs cores can give the workload's tasks at best
+	MaxSpeedup float64
+}
+
+// SpeedupCurve times w at each of procs, the median of iterations settled
+// runs apiece. procs should start at 1, the baseline of every speedup.
+func SpeedupCurve(w workloads.Workload, procs []int, iterations int) []CurvePoint {
+	points := make([]CurvePoint, 0, len(procs))
+	for _, p := range procs {
+		var times []time.Duration
+		for i := 0; i < iterations; i++ {
+			Settle()
+			times = append(times, w.Run(p, nil))
+		}
+		point := CurvePoint{
+			Procs:      p,
+			Elapsed:    stats.Median(times),
+			MaxSpeedup: Result{Tasks: w.Tasks(), Procs: p}.MaxSpeedup(),
+		}
+		if len(points) > 0 {
+			point.Speedup = float64(points[0].Elapsed) / float64(point.Elapsed)
+		} else {
+			point.Speedup = 1
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// FitAmdahl fits points to Amdahl's Law by least squares and returns the
+// estimated serial fraction of the workload, between 0 and 1. Each
+// point's ideal speedup is its MaxSpeedup rather than Procs, so the waves
+// of a task count that doesn't divide evenly aren't mistaken for serial
+// work. ok is false without a point past one P to fit.
+//
+// Amdahl's S = 1/(s + (1-s)/m) rearranges to 1/S - 1/m = s(1 - 1/m), a
+// line through the origin whose slope is s.
+func FitAmdahl(points []CurvePoint) (serial float64, ok bool) {
+	var xy, xx float64
+	for _, p := range points {
+		if p.MaxSpeedup <= 1 || p.Speedup <= 0 {
+			continue
+		}
+		x := 1 - 1/p.MaxSpeedup
+		y := 1/p.Speedup - 1/p.MaxSpeedup
+		xy += x * y
+		xx += x * x
+	}
+	if xx == 0 {
+		return 0, false
+	}
+	return min(max(xy/xx, 0), 1), true
+}
+
+// AmdahlSpeedup is the speedup Amdahl's Law predicts for a workload with
+// the given serial fraction on an ideal speedup of maxSpeedup.
+func AmdahlSpeedup(serial, maxSpeedup float64) float64 {
+	return 1 / (serial + (1-serial)/maxSpeedup)
+}
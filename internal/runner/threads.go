@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"compare_process/internal/sysinfo"
+	"compare_process/internal/workloads"
+)
+
+// threadSampleInterval is how often MonitorThreads samples the thread count
+const threadSampleInterval = 2 * time.Millisecond
+
+// ThreadMonitor samples the process's OS thread count from a background
+// goroutine, to show how blocking calls make the runtime add threads (Ms)
+// beyond GOMAXPROCS.
+type ThreadMonitor struct {
+	stop         chan struct{}
+	done         chan struct{}
+	startCreated int
+
+	// Start and Peak are OS thread counts, valid when OK; Created counts
+	// the threads the runtime created while monitoring and is always valid
+	Start   int
+	Peak    int
+	Created int
+	OK      bool
+}
+
+func StartThreadMonitor(interval time.Duration) *ThreadMonitor {
+	t := &ThreadMonitor{
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+		startCreated: sysinfo.ThreadsCreated(),
+	}
+	if n, err := sysinfo.OSThreads(); err == nil {
+		t.Start, t.Peak, t.OK = n, n, true
+	}
+	go t.loop(interval)
+	return t
+}
+
+func (t *ThreadMonitor) loop(interval time.Duration) {
+	defer close(t.done)
+	defer workloads.Guard()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.sample()
+		}
+	}
+}
+
+func (t *ThreadMonitor) sample() {
+	if !t.OK {
+		return
+	}
+	if n, err := sysinfo.OSThreads(); err == nil {
+		t.Peak = max(t.Peak, n)
+	}
+}
+
+// Stop takes a last sample and ends the monitoring.
+func (t *ThreadMonitor) Stop() {
+	close(t.stop)
+	<-t.done
+	t.sample()
+	t.Created = sysinfo.ThreadsCreated() - t.startCreated
+}
+
+func (t *ThreadMonitor) String() string {
+	if !t.OK {
+		return fmt.Sprintf("%d created (live count unavailable)", t.Created)
+	}
+	return fmt.Sprintf("%d → peak %d (%d created)", t.Start, t.Peak, t.Created)
+}
+
+// MonitorThreads runs w once with GOMAXPROCS=procs under a ThreadMonitor.
+// It is a separate, untimed run because sampling /proc allocates and takes
+// CPU, which would skew a measured run's timing and allocation counts.
+func MonitorThreads(w workloads.Workload, procs int) *ThreadMonitor {
+	Settle()
+	t := StartThreadMonitor(threadSampleInterval)
+	w.Run(procs, nil)
+	t.Stop()
+	return t
+}
@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// OutlierRule decides which timings are too far from the rest to keep.
+type OutlierRule string
+
+const (
+	// NoOutliers keeps every timing
+	NoOutliers OutlierRule = "none"
+	// Tukey drops timings more than 1.5 interquartile ranges outside the
+	// quartiles
+	Tukey OutlierRule = "tukey"
+	// MADRule drops timings whose modified z-score, 0.6745×|x-median|/MAD,
+	// is above 3.5 (Iglewicz and Hoaglin)
+	MADRule OutlierRule = "mad"
+)
+
+// ParseOutlierRule accepts none, tukey or mad.
+func ParseOutlierRule(s string) (OutlierRule, error) {
+	switch r := OutlierRule(s); r {
+	case NoOutliers, Tukey, MADRule:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown outlier rule %q (want none, tukey or mad)", s)
+}
+
+// minOutlierSamples is the fewest timings worth screening: with fewer the
+// quartiles and median are too coarse to call anything an outlier
+const minOutlierSamples = 4
+
+// RejectOutliers splits durations into the timings rule keeps and the
+// ones it discards, both in their original order. Sets too small to judge
+// are kept whole.
+func RejectOutliers(durations []time.Duration, rule OutlierRule) (kept, outliers []time.Duration) {
+	out := OutlierIndices(durations, rule)
+	if len(out) == 0 {
+		return durations, nil
+	}
+	for i, d := range durations {
+		if slices.Contains(out, i) {
+			outliers = append(outliers, d)
+		} else {
+			kept = append(kept, d)
+		}
+	}
+	return kept, outliers
+}
+
+// OutlierIndices returns the positions in durations of the timings rule
+// discards, in order.
+func OutlierIndices(durations []time.Duration, rule OutlierRule) []int {
+	if rule == NoOutliers || rule == "" || len(durations) < minOutlierSamples {
+		return nil
+	}
+
+	var outside func(d time.Duration) bool
+	switch rule {
+	case Tukey:
+		q1, q3 := quantile(durations, 0.25), quantile(durations, 0.75)
+		fence := 1.5 * (q3 - q1)
+		outside = func(d time.Duration) bool {
+			return float64(d) < q1-fence || float64(d) > q3+fence
+		}
+	case MADRule:
+		median, mad := float64(Median(durations)), float64(MAD(durations))
+		if mad == 0 {
+			// Over half the timings are identical; nothing to scale by
+			return nil
+		}
+		outside = func(d time.Duration) bool {
+			return 0.6745*math.Abs(float64(d)-median)/mad > 3.5
+		}
+	}
+
+	var out []int
+	for i, d := range durations {
+		if outside(d) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// MAD returns the median absolute deviation of durations from their
+// median, a spread that, unlike the standard deviation, one wild timing
+// can't inflate.
+func MAD(durations []time.Duration) time.Duration {
+	median := Median(durations)
+	deviations := make([]time.Duration, len(durations))
+	for i, d := range durations {
+		deviations[i] = max(d-median, median-d)
+	}
+	return Median(deviations)
+}
+
+// quantile interpolates between the closest ranks, so small sets still
+// get distinct quartiles.
+func quantile(durations []time.Duration, q float64) float64 {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	hi := min(lo+1, len(sorted)-1)
+	return float64(sorted[lo]) + (pos-float64(lo))*float64(sorted[hi]-sorted[lo])
+}
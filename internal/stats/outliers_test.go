@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRejectOutliers(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     OutlierRule
+		in       []time.Duration
+		outliers []time.Duration
+	}{
+		{"none", NoOutliers, ms(10, 11, 12, 13, 14, 100), nil},
+		{"unset", "", ms(10, 11, 12, 13, 14, 100), nil},
+		{"tukey high", Tukey, ms(100, 10, 11, 12, 13, 14), ms(100)},
+		{"tukey low", Tukey, ms(50, 51, 1, 52, 53, 54), ms(1)},
+		{"tukey both", Tukey, ms(1, 50, 51, 52, 53, 54, 55, 56, 200), ms(1, 200)},
+		{"tukey clean", Tukey, ms(10, 11, 12, 13, 14, 15), nil},
+		{"tukey too few", Tukey, ms(1, 2, 100), nil},
+		{"mad high", MADRule, ms(10, 11, 12, 100, 13, 14), ms(100)},
+		{"mad clean", MADRule, ms(10, 11, 12, 13, 14, 15), nil},
+		{"mad zero spread", MADRule, ms(5, 5, 5, 5, 9), nil},
+		{"mad too few", MADRule, ms(1, 2, 100), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, outliers := RejectOutliers(tt.in, tt.rule)
+			if !slices.Equal(outliers, tt.outliers) {
+				t.Errorf("outliers = %v, want %v", outliers, tt.outliers)
+			}
+			want := slices.DeleteFunc(slices.Clone(tt.in), func(d time.Duration) bool { return slices.Contains(tt.outliers, d) })
+			if !slices.Equal(kept, want) {
+				t.Errorf("kept = %v, want %v in their original order", kept, want)
+			}
+		})
+	}
+}
+
+func TestMAD(t *testing.T) {
+	tests := []struct {
+		in   []time.Duration
+		want time.Duration
+	}{
+		{ms(1, 2, 3, 4, 100), time.Millisecond},
+		{ms(10, 11, 12, 13, 14, 100), 1500 * time.Microsecond},
+		{ms(5, 5, 5, 9), 0},
+	}
+	for _, tt := range tests {
+		if got := MAD(tt.in); got != tt.want {
+			t.Errorf("MAD(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseOutlierRule(t *testing.T) {
+	for _, s := range []string{"none", "tukey", "mad"} {
+		if r, err := ParseOutlierRule(s); err != nil || string(r) != s {
+			t.Errorf("ParseOutlierRule(%q) = %q, %v", s, r, err)
+		}
+	}
+	if _, err := ParseOutlierRule("iqr"); err == nil {
+		t.Error("ParseOutlierRule(\"iqr\") accepted an unknown rule")
+	}
+}
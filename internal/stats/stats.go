@@ -0,0 +1,144 @@
+// Package stats summarizes sets of benchmark timings.
+package stats
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Average returns the mean of durations, or 0 for an empty set.
+func Average(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	total := time.Duration(0)
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// Variance returns the sample variance of durations in ns², or 0 with
+// fewer than two samples.
+func Variance(durations []time.Duration) float64 {
+	if len(durations) <= 1 {
+		return 0
+	}
+
+	avg := float64(Average(durations))
+	sumSq := 0.0
+	for _, d := range durations {
+		diff := float64(d) - avg
+		sumSq += diff * diff
+	}
+	return sumSq / float64(len(durations)-1)
+}
+
+// StdDev returns the sample standard deviation of durations.
+func StdDev(durations []time.Duration) time.Duration {
+	return time.Duration(math.Sqrt(Variance(durations)))
+}
+
+// CoefficientOfVariation is the sample standard deviation relative to the
+// mean, or NaN when there are too few samples to tell.
+func CoefficientOfVariation(durations []time.Duration) float64 {
+	if len(durations) <= 1 {
+		return math.NaN()
+	}
+	return math.Sqrt(Variance(durations)) / float64(Average(durations))
+}
+
+// Min returns the smallest of durations, or 0 for an empty set.
+func Min(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	return slices.Min(durations)
+}
+
+// Max returns the largest of durations, or 0 for an empty set.
+func Max(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	return slices.Max(durations)
+}
+
+// Median returns the middle of durations, averaging the two middle values
+// of an even-sized set, or 0 for an empty set.
+func Median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Percentile returns the nearest-rank p-th percentile (0-100) of
+// durations, or 0 for an empty set.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// Summary describes one set of timings.
+type Summary struct {
+	N        int
+	Min      time.Duration
+	Max      time.Duration
+	Mean     time.Duration
+	Median   time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Variance float64 // ns²
+	StdDev   time.Duration
+	CV       float64 // StdDev/Mean, NaN with fewer than two samples
+}
+
+// Summarize computes every statistic of durations at once.
+func Summarize(durations []time.Duration) Summary {
+	return Summary{
+		N:        len(durations),
+		Min:      Min(durations),
+		Max:      Max(durations),
+		Mean:     Average(durations),
+		Median:   Median(durations),
+		P90:      Percentile(durations, 90),
+		P99:      Percentile(durations, 99),
+		Variance: Variance(durations),
+		StdDev:   StdDev(durations),
+		CV:       CoefficientOfVariation(durations),
+	}
+}
+
+// CorrectOmission returns latencies with the samples a load generator
+// that waits for each reply failed to take, the way HdrHistogram's
+// RecordValueWithExpectedInterval fills them in. A request expected every
+// interval that took v > interval held back the ones due meanwhile, which
+// would have waited v-interval, v-2·interval, ... down to interval.
+func CorrectOmission(latencies []time.Duration, interval time.Duration) []time.Duration {
+	corrected := slices.Clone(latencies)
+	if interval <= 0 {
+		return corrected
+	}
+	for _, v := range latencies {
+		for missing := v - interval; missing >= interval; missing -= interval {
+			corrected = append(corrected, missing)
+		}
+	}
+	return corrected
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// ms turns values into that many milliseconds each.
+func ms(values ...float64) []time.Duration {
+	durations := make([]time.Duration, len(values))
+	for i, v := range values {
+		durations[i] = time.Duration(v * float64(time.Millisecond))
+	}
+	return durations
+}
+
+// near reports whether got is within tol of want, relative to want.
+func near(got, want, tol float64) bool {
+	return got == want || math.Abs(got-want) <= tol*math.Abs(want)
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     []time.Duration
+		mean   time.Duration
+		median time.Duration
+		stddev float64 // ms
+	}{
+		{"empty", nil, 0, 0, 0},
+		{"one", ms(7), 7 * time.Millisecond, 7 * time.Millisecond, 0},
+		{"two", ms(1, 3), 2 * time.Millisecond, 2 * time.Millisecond, math.Sqrt2},
+		{"textbook", ms(2, 4, 4, 4, 5, 5, 7, 9), 5 * time.Millisecond, 4500 * time.Microsecond, 2.138089935},
+		{"unsorted", ms(9, 2, 5, 4, 7, 4, 5, 4), 5 * time.Millisecond, 4500 * time.Microsecond, 2.138089935},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Summarize(tt.in)
+			if s.N != len(tt.in) {
+				t.Errorf("N = %d, want %d", s.N, len(tt.in))
+			}
+			if s.Mean != tt.mean {
+				t.Errorf("Mean = %v, want %v", s.Mean, tt.mean)
+			}
+			if s.Median != tt.median {
+				t.Errorf("Median = %v, want %v", s.Median, tt.median)
+			}
+			if got := float64(s.StdDev) / float64(time.Millisecond); !near(got, tt.stddev, 1e-6) {
+				t.Errorf("StdDev = %vms, want %vms", got, tt.stddev)
+			}
+			if len(tt.in) < 2 != math.IsNaN(s.CV) {
+				t.Errorf("CV = %v with %d samples", s.CV, len(tt.in))
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	in := ms(5, 1, 4, 2, 3, 10, 9, 8, 7, 6)
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{10, time.Millisecond},
+		{50, 5 * time.Millisecond},
+		{90, 9 * time.Millisecond},
+		{99, 10 * time.Millisecond},
+		{100, 10 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Percentile(in, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package stats
+
+import (
+	"math"
+	"time"
+)
+
+// TTest is the outcome of Welch's two-sample t-test.
+type TTest struct {
+	T  float64 // positive when a's mean is larger
+	DF float64 // Welch–Satterthwaite degrees of freedom
+	P  float64 // two-sided p-value, NaN when there are too few samples
+}
+
+// WelchTTest tests whether a and b have different means without assuming
+// equal variances, which concurrent and parallel timings rarely have.
+func WelchTTest(a, b []time.Duration) TTest {
+	if len(a) < 2 || len(b) < 2 {
+		return TTest{T: math.NaN(), DF: math.NaN(), P: math.NaN()}
+	}
+
+	na, nb := float64(len(a)), float64(len(b))
+	va, vb := Variance(a)/na, Variance(b)/nb
+	diff := float64(Average(a)) - float64(Average(b))
+	if va+vb == 0 {
+		// No spread at all: the means either differ or they don't
+		if diff == 0 {
+			return TTest{T: 0, DF: na + nb - 2, P: 1}
+		}
+		return TTest{T: math.Copysign(math.Inf(1), diff), DF: na + nb - 2, P: 0}
+	}
+
+	t := diff / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/(na-1) + vb*vb/(nb-1))
+	return TTest{T: t, DF: df, P: studentTwoSided(t, df)}
+}
+
+// Significant reports whether the difference holds at confidence, e.g.
+// 0.95 for p < 0.05.
+func (t TTest) Significant(confidence float64) bool {
+	return t.P < 1-confidence
+}
+
+// studentTwoSided is P(|T| ≥ |t|) for Student's t with df degrees of
+// freedom.
+func studentTwoSided(t, df float64) float64 {
+	return regIncBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// regIncBeta is the regularized incomplete beta function I_x(a, b),
+// evaluated by continued fraction (Numerical Recipes' betai).
+func regIncBeta(x, a, b float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	// The fraction converges fastest below the distribution's mean
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(x, a, b) / a
+	}
+	return 1 - front*betaFraction(1-x, b, a)/b
+}
+
+// betaFraction evaluates the continued fraction for regIncBeta with
+// Lentz's method.
+func betaFraction(x, a, b float64) float64 {
+	const (
+		maxIter = 200
+		eps     = 1e-14
+		tiny    = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		// Even step
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		// Odd step
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return h
+}
+
+// MeanCI returns the half-width of the level confidence interval of the
+// mean of durations, e.g. level 0.95 for a 95% interval, from Student's t.
+// It is 0 with fewer than two samples, which can't bound anything.
+func MeanCI(durations []time.Duration, level float64) time.Duration {
+	n := float64(len(durations))
+	if n < 2 {
+		return 0
+	}
+	return time.Duration(tCritical(level, n-1) * math.Sqrt(Variance(durations)/n))
+}
+
+// tCritical is the t with P(|T| ≥ t) = 1-level for df degrees of freedom,
+// found by bisection since the tail probability falls as t grows.
+func tCritical(level, df float64) float64 {
+	lo, hi := 0.0, 1e4
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if studentTwoSided(mid, df) > 1-level {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// PairedTTest tests whether the mean of a[i]-b[i] differs from zero, for
+// timings taken in pairs. Pairing cancels whatever both runs of a pair
+// shared, like the machine's state at the time, which Welch's test counts
+// as noise.
+func PairedTTest(a, b []time.Duration) TTest {
+	n := min(len(a), len(b))
+	if n < 2 {
+		return TTest{T: math.NaN(), DF: math.NaN(), P: math.NaN()}
+	}
+
+	diffs := make([]time.Duration, n)
+	for i := range diffs {
+		diffs[i] = a[i] - b[i]
+	}
+	mean, df := float64(Average(diffs)), float64(n-1)
+	v := Variance(diffs) / float64(n)
+	if v == 0 {
+		if mean == 0 {
+			return TTest{T: 0, DF: df, P: 1}
+		}
+		return TTest{T: math.Copysign(math.Inf(1), mean), DF: df, P: 0}
+	}
+	t := mean / math.Sqrt(v)
+	return TTest{T: t, DF: df, P: studentTwoSided(t, df)}
+}
+
+// PairedRatio returns the geometric mean of a[i]/b[i] and its level
+// confidence interval, from Student's t on the log ratios. The bounds are
+// NaN with fewer than two pairs.
+func PairedRatio(a, b []time.Duration, level float64) (ratio, lo, hi float64) {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	logs := make([]float64, n)
+	mean := 0.0
+	for i := range logs {
+		logs[i] = math.Log(float64(a[i]) / float64(b[i]))
+		mean += logs[i]
+	}
+	mean /= float64(n)
+	if n < 2 {
+		return math.Exp(mean), math.NaN(), math.NaN()
+	}
+
+	ss := 0.0
+	for _, l := range logs {
+		ss += (l - mean) * (l - mean)
+	}
+	half := tCritical(level, float64(n-1)) * math.Sqrt(ss/float64(n-1)/float64(n))
+	return math.Exp(mean), math.Exp(mean - half), math.Exp(mean + half)
+}
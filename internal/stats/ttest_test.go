@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// welchA and welchB are the first example of the Welch's t-test article
+// on Wikipedia
+var (
+	welchA = ms(27.5, 21.0, 19.0, 23.6, 17.0, 17.9, 16.9, 20.1, 21.9, 22.6, 23.1, 19.6, 19.0, 21.7, 21.4)
+	welchB = ms(27.1, 22.0, 20.8, 23.4, 23.4, 23.5, 25.8, 22.0, 24.8, 20.2, 21.9, 22.1, 22.9, 20.5, 24.4)
+)
+
+func TestTTests(t *testing.T) {
+	tests := []struct {
+		name     string
+		test     func(a, b []time.Duration) TTest
+		a, b     []time.Duration
+		t, df, p float64
+		tooFew   bool // every field is NaN
+	}{
+		{"welch", WelchTTest, welchA, welchB, -2.455356398, 24.98852929, 0.021378001, false},
+		{"welch swapped", WelchTTest, welchB, welchA, 2.455356398, 24.98852929, 0.021378001, false},
+		{"welch identical", WelchTTest, ms(5, 5, 5), ms(5, 5, 5), 0, 4, 1, false},
+		{"welch no spread", WelchTTest, ms(6, 6, 6), ms(5, 5, 5), math.Inf(1), 4, 0, false},
+		{"welch one sample", WelchTTest, ms(5), ms(5, 6), 0, 0, 0, true},
+		{"paired", PairedTTest, welchA, welchB, -2.685714575, 14, 0.017746022, false},
+		{"paired constant shift", PairedTTest, ms(1, 5, 9), ms(2, 6, 10), math.Inf(-1), 2, 0, false},
+		{"paired one pair", PairedTTest, ms(5, 6), ms(5), 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.test(tt.a, tt.b)
+			if tt.tooFew {
+				if !math.IsNaN(got.T) || !math.IsNaN(got.DF) || !math.IsNaN(got.P) {
+					t.Errorf("got %+v, want all NaN", got)
+				}
+				return
+			}
+			if !near(got.T, tt.t, 1e-6) || !near(got.DF, tt.df, 1e-6) || !near(got.P, tt.p, 1e-5) {
+				t.Errorf("got %+v, want T=%v DF=%v P=%v", got, tt.t, tt.df, tt.p)
+			}
+		})
+	}
+}
+
+func TestSignificant(t *testing.T) {
+	r := WelchTTest(welchA, welchB)
+	if !r.Significant(0.95) {
+		t.Errorf("p=%v not significant at 95%%", r.P)
+	}
+	if r.Significant(0.99) {
+		t.Errorf("p=%v significant at 99%%", r.P)
+	}
+}
+
+// TestTCritical checks the bisection against t tables' two-sided 95%
+// values.
+func TestTCritical(t *testing.T) {
+	tests := []struct {
+		df, want float64
+	}{
+		{1, 12.706204736},
+		{7, 2.364624252},
+		{10, 2.228138852},
+		{30, 2.042272456},
+	}
+	for _, tt := range tests {
+		if got := tCritical(0.95, tt.df); !near(got, tt.want, 1e-6) {
+			t.Errorf("tCritical(0.95, %v) = %v, want %v", tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestMeanCI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []time.Duration
+		want float64 // ms
+	}{
+		{"empty", nil, 0},
+		{"one", ms(5), 0},
+		{"no spread", ms(5, 5, 5), 0},
+		{"textbook", ms(2, 4, 4, 4, 5, 5, 7, 9), 1.787487919},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := float64(MeanCI(tt.in, 0.95)) / float64(time.Millisecond); !near(got, tt.want, 1e-6) {
+				t.Errorf("MeanCI = %vms, want %vms", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPairedRatio(t *testing.T) {
+	ratio, lo, hi := PairedRatio(ms(2, 4, 8), ms(1, 2, 4), 0.95)
+	if !near(ratio, 2, 1e-9) || !near(lo, 2, 1e-9) || !near(hi, 2, 1e-9) {
+		t.Errorf("constant ratio: got %v [%v, %v], want 2 [2, 2]", ratio, lo, hi)
+	}
+	ratio, lo, hi = PairedRatio(ms(2), ms(1), 0.95)
+	if !near(ratio, 2, 1e-9) || !math.IsNaN(lo) || !math.IsNaN(hi) {
+		t.Errorf("one pair: got %v [%v, %v], want 2 [NaN, NaN]", ratio, lo, hi)
+	}
+	ratio, lo, hi = PairedRatio(ms(2, 3, 4, 6), ms(1, 2, 2, 2), 0.95)
+	if !(lo < ratio && ratio < hi) {
+		t.Errorf("spread ratios: %v outside [%v, %v]", ratio, lo, hi)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CPUQuota returns the container CPU limit in cores from cgroup v2's
+// cpu.max or cgroup v1's CFS quota, or false when unlimited.
+func CPUQuota() (float64, bool) {
+	if data, err := os.ReadFile("/sys/fs/cgroup/cpu.max"); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, err1 := strconv.ParseFloat(fields[0], 64)
+			period, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 == nil && err2 == nil && period > 0 {
+				return quota / period, true
+			}
+		}
+		return 0, false
+	}
+
+	quotaData, err1 := os.ReadFile("/sys/fs/cgroup/cpu/cpu.cfs_quota_us")
+	periodData, err2 := os.ReadFile("/sys/fs/cgroup/cpu/cpu.cfs_period_us")
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	quota, err1 := strconv.ParseFloat(strings.TrimSpace(string(quotaData)), 64)
+	period, err2 := strconv.ParseFloat(strings.TrimSpace(string(periodData)), 64)
+	if err1 != nil || err2 != nil || quota <= 0 || period <= 0 {
+		return 0, false
+	}
+	return quota / period, true
+}
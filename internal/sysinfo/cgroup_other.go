@@ -0,0 +1,8 @@
+//go:build !linux
+
+package sysinfo
+
+// cgroups are Linux-only; elsewhere there is no container CPU quota.
+func CPUQuota() (float64, bool) {
+	return 0, false
+}
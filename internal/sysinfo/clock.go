@@ -0,0 +1,71 @@
+package sysinfo
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// clockSamples is how many clock reads each calibration averages over
+	clockSamples = 1 << 20
+	// clockShare is the share of an interval a start/stop pair of clock
+	// reads may take before it skews the measurement
+	clockShare = 0.01
+)
+
+// Clock is what reading the clock costs on this host. Per-task latencies
+// take a time.Now at the start and a time.Since at the end, so every
+// recorded interval carries one of each.
+type Clock struct {
+	NowNS        float64 `json:"now_ns"`        // time.Now: wall and monotonic clock
+	MonotonicNS  float64 `json:"monotonic_ns"`  // time.Since: monotonic clock (runtime nanotime) only
+	ResolutionNS int64   `json:"resolution_ns"` // smallest step seen between readings
+}
+
+// MeasureClock times back-to-back clock reads.
+func MeasureClock() Clock {
+	var c Clock
+
+	start := time.Now()
+	for i := 0; i < clockSamples; i++ {
+		_ = time.Now()
+	}
+	c.NowNS = float64(time.Since(start).Nanoseconds()) / clockSamples
+
+	var sink time.Duration
+	start = time.Now()
+	for i := 0; i < clockSamples; i++ {
+		sink += time.Since(start)
+	}
+	c.MonotonicNS = float64(time.Since(start).Nanoseconds()) / clockSamples
+
+	// Spin until the monotonic reading changes, a few times over
+	for i := 0; i < 8; i++ {
+		t := time.Now()
+		d := time.Since(t)
+		for d == 0 {
+			d = time.Since(t)
+		}
+		if c.ResolutionNS == 0 || d.Nanoseconds() < c.ResolutionNS {
+			c.ResolutionNS = d.Nanoseconds()
+		}
+	}
+	_ = sink
+	return c
+}
+
+// Overhead is what timing one interval adds to it: a time.Now and a
+// time.Since, or at least one resolution step.
+func (c Clock) Overhead() time.Duration {
+	return time.Duration(max(c.NowNS+c.MonotonicNS, float64(c.ResolutionNS)))
+}
+
+// Significant reports whether the clock overhead is more than 1% of
+// interval, so per-task latencies that short are partly the clock's.
+func (c Clock) Significant(interval time.Duration) bool {
+	return interval > 0 && float64(c.Overhead()) > float64(interval)*clockShare
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("time.Now %.0fns, monotonic %.0fns, resolution %dns", c.NowNS, c.MonotonicNS, c.ResolutionNS)
+}
@@ -0,0 +1,60 @@
+package sysinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CPUInfo describes the processor model and cache hierarchy. Zero values
+// mean the platform didn't tell us.
+type CPUInfo struct {
+	Model    string  `json:"model,omitempty"`
+	BaseMHz  float64 `json:"base_mhz,omitempty"`
+	BoostMHz float64 `json:"boost_mhz,omitempty"`
+	L1DKB    int     `json:"l1d_kb,omitempty"`
+	L1IKB    int     `json:"l1i_kb,omitempty"`
+	L2KB     int     `json:"l2_kb,omitempty"`
+	L3KB     int     `json:"l3_kb,omitempty"`
+}
+
+func (c CPUInfo) Frequencies() string {
+	switch {
+	case c.BaseMHz > 0 && c.BoostMHz > 0:
+		return fmt.Sprintf("%.0f MHz base, %.0f MHz boost", c.BaseMHz, c.BoostMHz)
+	case c.BaseMHz > 0:
+		return fmt.Sprintf("%.0f MHz", c.BaseMHz)
+	case c.BoostMHz > 0:
+		return fmt.Sprintf("up to %.0f MHz", c.BoostMHz)
+	}
+	return "unknown"
+}
+
+func (c CPUInfo) Caches() string {
+	var parts []string
+	for _, level := range []struct {
+		name string
+		kb   int
+	}{{"L1d", c.L1DKB}, {"L1i", c.L1IKB}, {"L2", c.L2KB}, {"L3", c.L3KB}} {
+		if level.kb > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", level.name, formatKB(level.kb)))
+		}
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatKB(kb int) string {
+	if kb >= 1024 && kb%1024 == 0 {
+		return fmt.Sprintf("%dMB", kb/1024)
+	}
+	return fmt.Sprintf("%dKB", kb)
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
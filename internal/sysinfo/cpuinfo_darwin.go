@@ -0,0 +1,43 @@
+//go:build darwin
+
+package sysinfo
+
+import (
+	"encoding/binary"
+	"syscall"
+)
+
+// DetectCPUInfo reads the brand string, frequency and caches from sysctl.
+// Apple Silicon doesn't publish its frequency, so that stays unknown there.
+func DetectCPUInfo() CPUInfo {
+	var info CPUInfo
+	info.Model, _ = syscall.Sysctl("machdep.cpu.brand_string")
+	if hz := sysctlInt("hw.cpufrequency"); hz > 0 {
+		info.BaseMHz = float64(hz) / 1e6
+	}
+	if hz := sysctlInt("hw.cpufrequency_max"); hz > 0 {
+		info.BoostMHz = float64(hz) / 1e6
+	}
+	info.L1DKB = int(sysctlInt("hw.l1dcachesize") / 1024)
+	info.L1IKB = int(sysctlInt("hw.l1icachesize") / 1024)
+	info.L2KB = int(sysctlInt("hw.l2cachesize") / 1024)
+	info.L3KB = int(sysctlInt("hw.l3cachesize") / 1024)
+	return info
+}
+
+// sysctlInt decodes a little-endian integer sysctl. syscall.Sysctl strips
+// one trailing NUL, which the zero padding restores.
+func sysctlInt(name string) uint64 {
+	s, err := syscall.Sysctl(name)
+	if err != nil || len(s) > 8 {
+		return 0
+	}
+	var buf [8]byte
+	copy(buf[:], s)
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// CurrentCPUMHz is unavailable: the current clock isn't exposed here.
+func CurrentCPUMHz() (float64, bool) {
+	return 0, false
+}
@@ -0,0 +1,97 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// modelGHz matches the rated frequency in model names like
+// "Intel(R) Core(TM) i7-8550U CPU @ 1.80GHz"
+var modelGHz = regexp.MustCompile(`@ ([0-9.]+)GHz`)
+
+// DetectCPUInfo reads the model from /proc/cpuinfo and frequencies and
+// caches from sysfs.
+func DetectCPUInfo() CPUInfo {
+	var info CPUInfo
+	var currentMHz float64
+
+	if f, err := os.Open("/proc/cpuinfo"); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(scanner.Text(), ":")
+			if !ok {
+				continue
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			switch {
+			case key == "model name" && info.Model == "":
+				info.Model = value
+			case key == "Model" && info.Model == "": // arm64 boards
+				info.Model = value
+			case key == "cpu MHz" && currentMHz == 0:
+				currentMHz, _ = strconv.ParseFloat(value, 64)
+			}
+		}
+		f.Close()
+	}
+
+	cpufreq := "/sys/devices/system/cpu/cpu0/cpufreq/"
+	if khz := readSysInt(cpufreq + "base_frequency"); khz > 0 {
+		info.BaseMHz = float64(khz) / 1000
+	} else if m := modelGHz.FindStringSubmatch(info.Model); m != nil {
+		ghz, _ := strconv.ParseFloat(m[1], 64)
+		info.BaseMHz = ghz * 1000
+	} else {
+		info.BaseMHz = currentMHz
+	}
+	if khz := readSysInt(cpufreq + "cpuinfo_max_freq"); khz > 0 {
+		info.BoostMHz = float64(khz) / 1000
+	}
+
+	dirs, _ := filepath.Glob("/sys/devices/system/cpu/cpu0/cache/index[0-9]*")
+	for _, dir := range dirs {
+		level := readSysInt(filepath.Join(dir, "level"))
+		kind, _ := os.ReadFile(filepath.Join(dir, "type"))
+		size, _ := os.ReadFile(filepath.Join(dir, "size"))
+		kb, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(string(size)), "K"))
+		if err != nil {
+			continue
+		}
+		switch t := strings.TrimSpace(string(kind)); {
+		case level == 1 && t == "Data":
+			info.L1DKB = kb
+		case level == 1 && t == "Instruction":
+			info.L1IKB = kb
+		case level == 2:
+			info.L2KB = kb
+		case level == 3:
+			info.L3KB = kb
+		}
+	}
+	return info
+}
+
+func readSysInt(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return v
+}
+
+// CurrentCPUMHz reports cpu0's current clock, from cpufreq when the driver
+// exposes it and /proc/cpuinfo otherwise.
+func CurrentCPUMHz() (float64, bool) {
+	if khz := readSysInt("/sys/devices/system/cpu/cpu0/cpufreq/scaling_cur_freq"); khz > 0 {
+		return float64(khz) / 1000, true
+	}
+	mhz, err := strconv.ParseFloat(procField("/proc/cpuinfo", "cpu MHz"), 64)
+	return mhz, err == nil && mhz > 0
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package sysinfo
+
+// CPU model and cache details aren't available portably elsewhere.
+func DetectCPUInfo() CPUInfo {
+	return CPUInfo{}
+}
+
+// CurrentCPUMHz is unavailable: the current clock isn't exposed here.
+func CurrentCPUMHz() (float64, bool) {
+	return 0, false
+}
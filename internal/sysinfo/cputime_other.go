@@ -0,0 +1,10 @@
+//go:build !unix
+
+package sysinfo
+
+import "time"
+
+// ProcessCPUTime is unavailable on this platform.
+func ProcessCPUTime() (time.Duration, bool) {
+	return 0, false
+}
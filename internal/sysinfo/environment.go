@@ -0,0 +1,58 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// goEnvVars are the environment variables that change runtime behavior
+// and so can confound results
+var goEnvVars = []string{"GOGC", "GOMEMLIMIT", "GOMAXPROCS", "GODEBUG", "GOEXPERIMENT"}
+
+// Environment is a snapshot of everything outside the benchmark that can
+// skew its numbers, stored so results can be audited later.
+type Environment struct {
+	Kernel      string            `json:"kernel,omitempty"`
+	Governor    string            `json:"cpu_governor,omitempty"`
+	Container   string            `json:"container,omitempty"`
+	Virtualized bool              `json:"virtualized"`
+	Hypervisor  string            `json:"hypervisor,omitempty"`
+	MemoryBytes uint64            `json:"memory_bytes,omitempty"`
+	GoEnv       map[string]string `json:"go_env,omitempty"`
+}
+
+func DetectEnvironment() Environment {
+	env := detectPlatformEnvironment()
+	for _, name := range goEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			if env.GoEnv == nil {
+				env.GoEnv = map[string]string{}
+			}
+			env.GoEnv[name] = value
+		}
+	}
+	return env
+}
+
+func (e Environment) String() string {
+	parts := []string{"kernel " + valueOr(e.Kernel, "unknown")}
+	if e.Governor != "" {
+		parts = append(parts, "governor "+e.Governor)
+	}
+	if e.MemoryBytes > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f GB RAM", float64(e.MemoryBytes)/(1<<30)))
+	}
+	if e.Container != "" {
+		parts = append(parts, "container "+e.Container)
+	}
+	if e.Virtualized {
+		parts = append(parts, "VM "+valueOr(e.Hypervisor, "(unknown hypervisor)"))
+	}
+	for _, name := range goEnvVars {
+		if value, ok := e.GoEnv[name]; ok {
+			parts = append(parts, name+"="+value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
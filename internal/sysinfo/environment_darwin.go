@@ -0,0 +1,15 @@
+//go:build darwin
+
+package sysinfo
+
+import "syscall"
+
+func detectPlatformEnvironment() Environment {
+	var env Environment
+	env.Kernel, _ = syscall.Sysctl("kern.osrelease")
+	env.MemoryBytes = sysctlInt("hw.memsize")
+	if sysctlInt("kern.hv_vmm_present") == 1 {
+		env.Virtualized = true
+	}
+	return env
+}
@@ -0,0 +1,83 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func detectPlatformEnvironment() Environment {
+	var env Environment
+
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		env.Kernel = strings.TrimSpace(string(data))
+	}
+	if data, err := os.ReadFile("/sys/devices/system/cpu/cpu0/cpufreq/scaling_governor"); err == nil {
+		env.Governor = strings.TrimSpace(string(data))
+	}
+	if kb := procField("/proc/meminfo", "MemTotal"); kb != "" {
+		n, _ := strconv.ParseUint(strings.TrimSuffix(kb, " kB"), 10, 64)
+		env.MemoryBytes = n * 1024
+	}
+	env.Container = detectContainer()
+
+	// The kernel sets the "hypervisor" CPU flag inside any VM
+	if flags := procField("/proc/cpuinfo", "flags"); strings.Contains(" "+flags+" ", " hypervisor ") {
+		env.Virtualized = true
+		for _, path := range []string{"/sys/class/dmi/id/product_name", "/sys/class/dmi/id/sys_vendor"} {
+			if data, err := os.ReadFile(path); err == nil {
+				env.Hypervisor = strings.TrimSpace(string(data))
+				break
+			}
+		}
+	}
+	return env
+}
+
+// detectContainer recognizes the common container runtimes from the marker
+// files and cgroup paths they leave behind.
+func detectContainer() string {
+	if name := os.Getenv("container"); name != "" {
+		return name // set by systemd-nspawn, podman and LXC
+	}
+	if _, err := os.Stat("/run/.containerenv"); err == nil {
+		return "podman"
+	}
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return "docker"
+	}
+
+	data, err := os.ReadFile("/proc/1/cgroup")
+	if err != nil {
+		return ""
+	}
+	cgroups := string(data)
+	for _, marker := range []string{"kubepods", "docker", "containerd", "lxc"} {
+		if strings.Contains(cgroups, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// procField returns the first "name: value" field from a /proc file.
+func procField(path, name string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // cpuinfo flags lines are long
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == name {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package sysinfo
+
+// Only the Go environment variables are captured on other platforms.
+func detectPlatformEnvironment() Environment {
+	return Environment{}
+}
@@ -0,0 +1,50 @@
+package sysinfo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CoreClass is a group of identical cores on a hybrid CPU, e.g. the
+// performance or efficiency cores. CPUs is empty when the OS doesn't tell
+// us which logical CPUs belong to the class (macOS).
+type CoreClass struct {
+	Name  string
+	Count int
+	CPUs  []int
+}
+
+// DescribeCoreClasses renders classes as e.g. "8 performance + 4 efficiency".
+func DescribeCoreClasses(classes []CoreClass) string {
+	parts := make([]string, len(classes))
+	for i, c := range classes {
+		parts[i] = fmt.Sprintf("%d %s", c.Count, c.Name)
+	}
+	return strings.Join(parts, " + ")
+}
+
+// parseCPUList parses the kernel's cpulist format, e.g. "0-7,16-23".
+func parseCPUList(s string) ([]int, error) {
+	var cpus []int
+	for _, part := range strings.Split(strings.TrimSpace(s), ",") {
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("bad cpu list %q: %w", s, err)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, fmt.Errorf("bad cpu list %q: %w", s, err)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
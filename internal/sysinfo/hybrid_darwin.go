@@ -0,0 +1,32 @@
+//go:build darwin
+
+package sysinfo
+
+import "syscall"
+
+// DetectCoreClasses reads the performance levels Apple Silicon exposes
+// through sysctl. macOS has no thread pinning, so the classes carry counts
+// but no CPU lists.
+func DetectCoreClasses() ([]CoreClass, error) {
+	levels, err := syscall.SysctlUint32("hw.nperflevels")
+	if err != nil || levels < 2 {
+		return nil, nil
+	}
+
+	pcores, err := syscall.SysctlUint32("hw.perflevel0.logicalcpu")
+	if err != nil {
+		return nil, err
+	}
+	ecores, err := syscall.SysctlUint32("hw.perflevel1.logicalcpu")
+	if err != nil {
+		return nil, err
+	}
+	return []CoreClass{
+		{Name: "performance", Count: int(pcores)},
+		{Name: "efficiency", Count: int(ecores)},
+	}, nil
+}
+
+func PinProcess(cpus []int) (func(), error) {
+	return nil, ErrUnavailable
+}
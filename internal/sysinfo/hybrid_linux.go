@@ -0,0 +1,121 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// DetectCoreClasses finds Intel hybrid P/E cores through the cpu_core and
+// cpu_atom PMUs, or ARM big.LITTLE clusters through cpu_capacity. Classes
+// are ordered fastest first; nil means the cores are homogeneous.
+func DetectCoreClasses() ([]CoreClass, error) {
+	pcores, perr := os.ReadFile("/sys/devices/cpu_core/cpus")
+	ecores, eerr := os.ReadFile("/sys/devices/cpu_atom/cpus")
+	if perr == nil && eerr == nil {
+		p, err := parseCPUList(string(pcores))
+		if err != nil {
+			return nil, err
+		}
+		e, err := parseCPUList(string(ecores))
+		if err != nil {
+			return nil, err
+		}
+		return []CoreClass{
+			{"performance", len(p), p},
+			{"efficiency", len(e), e},
+		}, nil
+	}
+
+	paths, _ := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*/cpu_capacity")
+	byCapacity := map[int][]int{}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		capacity, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			continue
+		}
+		cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(p)), "cpu"))
+		if err != nil {
+			continue
+		}
+		byCapacity[capacity] = append(byCapacity[capacity], cpu)
+	}
+	if len(byCapacity) < 2 {
+		return nil, nil
+	}
+
+	var capacities []int
+	for c := range byCapacity {
+		capacities = append(capacities, c)
+	}
+	slices.Sort(capacities)
+	slices.Reverse(capacities)
+
+	var classes []CoreClass
+	for _, c := range capacities {
+		cpus := byCapacity[c]
+		slices.Sort(cpus)
+		classes = append(classes, CoreClass{fmt.Sprintf("capacity-%d", c), len(cpus), cpus})
+	}
+	return classes, nil
+}
+
+// PinProcess restricts every thread of the process to cpus and returns a
+// function restoring the previous mask. Threads created later inherit the
+// mask from their creator, so pinning the existing ones is enough.
+func PinProcess(cpus []int) (func(), error) {
+	old, err := AffinityCPUs()
+	if err != nil {
+		return nil, err
+	}
+	if err := setProcessAffinity(cpus); err != nil {
+		setProcessAffinity(old)
+		return nil, err
+	}
+	return func() { setProcessAffinity(old) }, nil
+}
+
+func setProcessAffinity(cpus []int) error {
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		tid, err := strconv.Atoi(t.Name())
+		if err != nil {
+			continue
+		}
+		err = setThreadAffinity(tid, cpus)
+		if err != nil && !errors.Is(err, syscall.ESRCH) { // ESRCH: thread exited meanwhile
+			return err
+		}
+	}
+	return nil
+}
+
+// setThreadAffinity restricts thread tid, or the calling thread when tid
+// is 0, to cpus.
+func setThreadAffinity(tid int, cpus []int) error {
+	var mask [1024 / 64]uint64
+	for _, cpu := range cpus {
+		mask[cpu/64] |= 1 << (cpu % 64)
+	}
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY,
+		uintptr(tid), uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return fmt.Errorf("sched_setaffinity: %w", errno)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package sysinfo
+
+// Core classes aren't exposed portably elsewhere; treat cores as identical.
+func DetectCoreClasses() ([]CoreClass, error) {
+	return nil, nil
+}
+
+func PinProcess(cpus []int) (func(), error) {
+	return nil, ErrUnavailable
+}
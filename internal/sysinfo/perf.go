@@ -0,0 +1,14 @@
+package sysinfo
+
+// PerfCounters holds the counts read back from a PerfSession.
+type PerfCounters struct {
+	CacheRefs   uint64
+	CacheMisses uint64
+}
+
+func (c PerfCounters) MissRate() float64 {
+	if c.CacheRefs == 0 {
+		return 0
+	}
+	return float64(c.CacheMisses) / float64(c.CacheRefs)
+}
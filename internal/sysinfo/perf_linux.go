@@ -0,0 +1,75 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PerfSession attaches `perf stat` to this process to count hardware cache
+// events while a workload runs. It's only used when perf is installed and
+// the kernel lets us read the counters.
+type PerfSession struct {
+	cmd    *exec.Cmd
+	output bytes.Buffer
+}
+
+func StartPerf() (*PerfSession, error) {
+	path, err := exec.LookPath("perf")
+	if err != nil {
+		return nil, fmt.Errorf("perf not installed")
+	}
+
+	s := &PerfSession{}
+	s.cmd = exec.Command(path, "stat", "-x", ",",
+		"-e", "cache-references,cache-misses",
+		"-p", strconv.Itoa(os.Getpid()))
+	s.cmd.Stderr = &s.output
+	if err := s.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting perf: %w", err)
+	}
+
+	// perf needs a moment to attach before the counters start moving
+	time.Sleep(50 * time.Millisecond)
+	return s, nil
+}
+
+// Stop detaches perf and parses its CSV output.
+func (s *PerfSession) Stop() (PerfCounters, error) {
+	var c PerfCounters
+
+	s.cmd.Process.Signal(os.Interrupt)
+	s.cmd.Wait()
+
+	// Lines look like: 123456,,cache-references,1000000,100.00,,
+	found := 0
+	for _, line := range strings.Split(s.output.String(), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue // "<not supported>" or "<not counted>"
+		}
+		switch {
+		case strings.HasPrefix(fields[2], "cache-references"):
+			c.CacheRefs = value
+			found++
+		case strings.HasPrefix(fields[2], "cache-misses"):
+			c.CacheMisses = value
+			found++
+		}
+	}
+
+	if found < 2 {
+		return c, fmt.Errorf("perf counters unavailable")
+	}
+	return c, nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package sysinfo
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// perf only exists on Linux; elsewhere the cache counters are unavailable
+// and classification falls back to CPU time alone.
+type PerfSession struct{}
+
+func StartPerf() (*PerfSession, error) {
+	return nil, fmt.Errorf("perf unavailable on %s", runtime.GOOS)
+}
+
+func (s *PerfSession) Stop() (PerfCounters, error) {
+	return PerfCounters{}, fmt.Errorf("perf unavailable on %s", runtime.GOOS)
+}
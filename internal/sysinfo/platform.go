@@ -0,0 +1,40 @@
+// Package sysinfo probes the host: CPU model, caches and core classes, the
+// surrounding environment, and optional OS monitors (perf, affinity, RAPL,
+// /proc). Platforms without a monitor get a fallback that reports it as
+// unavailable instead of failing to build.
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnavailable is returned by platform-specific subsystems on platforms
+// that don't support them, so callers can degrade rather than fail.
+var ErrUnavailable = errors.New("unavailable on this platform")
+
+// Capabilities probes each optional OS subsystem and reports which
+// ones this build can actually use.
+func Capabilities() string {
+	probes := []struct {
+		name string
+		ok   bool
+	}{
+		{"perf", PerfAvailable()},
+		{"affinity", func() bool { _, err := AffinityCPUs(); return err == nil }()},
+		{"RAPL", func() bool { _, err := ReadRAPLEnergy(); return err == nil }()},
+		{"/proc", func() bool { _, err := ReadProcStatus("Threads"); return err == nil }()},
+		{"cputime", func() bool { _, ok := ProcessCPUTime(); return ok }()},
+	}
+
+	parts := make([]string, len(probes))
+	for i, p := range probes {
+		mark := "✗"
+		if p.ok {
+			mark = "✓"
+		}
+		parts[i] = fmt.Sprintf("%s %s", p.name, mark)
+	}
+	return strings.Join(parts, ", ")
+}
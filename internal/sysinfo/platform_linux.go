@@ -0,0 +1,83 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+func PerfAvailable() bool {
+	_, err := exec.LookPath("perf")
+	return err == nil
+}
+
+// AffinityCPUs returns the CPUs this process may run on, per
+// sched_getaffinity(2).
+func AffinityCPUs() ([]int, error) {
+	var mask [1024 / 64]uint64
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY,
+		0, uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return nil, fmt.Errorf("sched_getaffinity: %w", errno)
+	}
+
+	var cpus []int
+	for i, word := range mask {
+		for bit := 0; bit < 64; bit++ {
+			if word&(1<<bit) != 0 {
+				cpus = append(cpus, i*64+bit)
+			}
+		}
+	}
+	return cpus, nil
+}
+
+// ReadRAPLEnergy sums the package energy counters exposed by the
+// intel-rapl powercap driver, in microjoules.
+func ReadRAPLEnergy() (uint64, error) {
+	paths, _ := filepath.Glob("/sys/class/powercap/intel-rapl:[0-9]*/energy_uj")
+	if len(paths) == 0 {
+		return 0, fmt.Errorf("RAPL: %w", ErrUnavailable)
+	}
+
+	var total uint64
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return 0, fmt.Errorf("RAPL: %w", err)
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("RAPL: %w", err)
+		}
+		total += v
+	}
+	return total, nil
+}
+
+// ReadProcStatus returns one field (e.g. "Threads", "VmRSS") from
+// /proc/self/status.
+func ReadProcStatus(field string) (string, error) {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && name == field {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", fmt.Errorf("/proc/self/status: no field %q", field)
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package sysinfo
+
+// perf, sched_getaffinity, RAPL and /proc are Linux-only. These fallbacks
+// let Windows, macOS and the BSDs build everything and report the
+// corresponding measurements as unavailable.
+
+func PerfAvailable() bool {
+	return false
+}
+
+func AffinityCPUs() ([]int, error) {
+	return nil, ErrUnavailable
+}
+
+func ReadRAPLEnergy() (uint64, error) {
+	return 0, ErrUnavailable
+}
+
+func ReadProcStatus(field string) (string, error) {
+	return "", ErrUnavailable
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package sysinfo
+
+import "os"
+
+// ChildMaxRSS is unavailable without getrusage.
+func ChildMaxRSS(state *os.ProcessState) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package sysinfo
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// ChildMaxRSS returns the peak resident set size of an exited child, in bytes.
+func ChildMaxRSS(state *os.ProcessState) (uint64, bool) {
+	ru, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0, false
+	}
+	// Linux and the BSDs report kilobytes; macOS reports bytes
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		return uint64(ru.Maxrss), true
+	}
+	return uint64(ru.Maxrss) * 1024, true
+}
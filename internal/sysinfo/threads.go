@@ -0,0 +1,24 @@
+package sysinfo
+
+import (
+	"runtime/pprof"
+	"strconv"
+)
+
+// OSThreads returns the number of OS threads the process has right now,
+// from the Threads field of /proc/self/status.
+func OSThreads() (int, error) {
+	value, err := ReadProcStatus("Threads")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+// ThreadsCreated returns how many OS threads the Go runtime has created so
+// far, per the threadcreate profile. It works on every platform but only
+// ever grows: the runtime parks idle threads for reuse instead of exiting
+// them, unless a goroutine exits while locked to one.
+func ThreadsCreated() int {
+	return pprof.Lookup("threadcreate").Count()
+}
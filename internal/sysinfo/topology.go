@@ -0,0 +1,53 @@
+package sysinfo
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CPUPlace is where one logical CPU sits: the physical core it shares
+// with its hyperthread siblings, and the socket that core is on.
+type CPUPlace struct {
+	CPU    int
+	Core   int
+	Socket int
+}
+
+// Placement is a pair of logical CPUs chosen to share, or not to share, a
+// physical core or a socket.
+type Placement struct {
+	Name string
+	Note string
+	CPUs []int
+}
+
+// Placements picks from places a pair of hyperthread siblings on one
+// core, a pair of separate cores on one socket and a pair of cores on
+// separate sockets, leaving out the ones the machine can't offer.
+func Placements(places []CPUPlace) []Placement {
+	type core struct{ socket, id int }
+	var cores []core
+	threads := map[core][]int{}
+	for _, p := range places {
+		c := core{p.Socket, p.Core}
+		if _, ok := threads[c]; !ok {
+			cores = append(cores, c)
+		}
+		threads[c] = append(threads[c], p.CPU)
+	}
+
+	var out []Placement
+	if i := slices.IndexFunc(cores, func(c core) bool { return len(threads[c]) > 1 }); i >= 0 {
+		cpus := threads[cores[i]][:2]
+		out = append(out, Placement{"siblings", fmt.Sprintf("hyperthreads of one core (CPUs %d and %d): shared L1, L2 and execution units", cpus[0], cpus[1]), cpus})
+	}
+	if i := slices.IndexFunc(cores, func(c core) bool { return c != cores[0] && c.socket == cores[0].socket }); i >= 0 {
+		cpus := []int{threads[cores[0]][0], threads[cores[i]][0]}
+		out = append(out, Placement{"cores", fmt.Sprintf("separate cores of one socket (CPUs %d and %d): shared L3 and memory controller", cpus[0], cpus[1]), cpus})
+	}
+	if i := slices.IndexFunc(cores, func(c core) bool { return c.socket != cores[0].socket }); i >= 0 {
+		cpus := []int{threads[cores[0]][0], threads[cores[i]][0]}
+		out = append(out, Placement{"sockets", fmt.Sprintf("cores on separate sockets (CPUs %d and %d): nothing shared but the interconnect", cpus[0], cpus[1]), cpus})
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ReadTopology places each CPU the process may run on by the core and
+// package ids in sysfs, sorted by CPU.
+func ReadTopology() ([]CPUPlace, error) {
+	cpus, err := AffinityCPUs()
+	if err != nil {
+		return nil, err
+	}
+	places := make([]CPUPlace, 0, len(cpus))
+	for _, cpu := range cpus {
+		dir := fmt.Sprintf("/sys/devices/system/cpu/cpu%d/topology/", cpu)
+		core, err := readTopologyID(dir + "core_id")
+		if err != nil {
+			return nil, err
+		}
+		socket, err := readTopologyID(dir + "physical_package_id")
+		if err != nil {
+			return nil, err
+		}
+		places = append(places, CPUPlace{cpu, core, socket})
+	}
+	return places, nil
+}
+
+func readTopologyID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// PinThread locks the calling goroutine to its OS thread and restricts
+// that thread to cpu. The returned function restores the thread's mask
+// and unlocks it.
+func PinThread(cpu int) (func(), error) {
+	runtime.LockOSThread()
+	old, err := AffinityCPUs()
+	if err == nil {
+		err = setThreadAffinity(0, []int{cpu})
+	}
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+	return func() {
+		setThreadAffinity(0, old)
+		runtime.UnlockOSThread()
+	}, nil
+}
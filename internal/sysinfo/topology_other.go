@@ -0,0 +1,13 @@
+//go:build !linux
+
+package sysinfo
+
+// sysfs topology and per-thread affinity are Linux-only.
+
+func ReadTopology() ([]CPUPlace, error) {
+	return nil, ErrUnavailable
+}
+
+func PinThread(cpu int) (func(), error) {
+	return nil, ErrUnavailable
+}
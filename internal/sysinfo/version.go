@@ -0,0 +1,56 @@
+package sysinfo
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// BuildInfo identifies the exact benchmark code that produced a result.
+type BuildInfo struct {
+	Version  string `json:"version"`
+	Revision string `json:"revision,omitempty"`
+	Time     string `json:"vcs_time,omitempty"`
+	Modified bool   `json:"modified,omitempty"`
+}
+
+// ReadBuildInfo combines the release version set with -ldflags (empty for
+// development builds) with the VCS stamp the go command embeds when
+// building inside a git checkout.
+func ReadBuildInfo(version string) BuildInfo {
+	info := BuildInfo{Version: version}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Revision = s.Value
+			case "vcs.time":
+				info.Time = s.Value
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	return info
+}
+
+func (b BuildInfo) String() string {
+	if b.Revision == "" {
+		return b.Version
+	}
+	rev := b.Revision
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	if b.Modified {
+		rev += ", modified"
+	}
+	return fmt.Sprintf("%s (%s)", b.Version, rev)
+}
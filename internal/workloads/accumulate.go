@@ -0,0 +1,124 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AccumulateMode is one way for workers to sum into a single total.
+type AccumulateMode struct {
+	Name string
+	Note string
+	// run has workers workers add perWorker values each and returns the
+	// total
+	run func(workers, perWorker int) int64
+}
+
+// AccumulateModes returns the accumulation strategies the accumulate
+// suite compares, from uncontended to fully shared.
+func AccumulateModes() []AccumulateMode {
+	return []AccumulateMode{
+		{"local", "each worker sums into its own variable, merged once at the end", localAccumulate},
+		{"slots", "each worker sums into its own element of a shared slice (false sharing)", slotAccumulate},
+		{"atomic", "every add is an atomic.Int64.Add on one global", atomicAccumulate},
+		{"mutex", "every add locks one mutex around one global", mutexAccumulate},
+	}
+}
+
+// addend is the value worker adds on its j-th step, cheap enough that the
+// synchronization around it dominates
+func addend(j int) int64 { return int64(j & 7) }
+
+// RunAccumulate has workers workers split total adds through mode at
+// maxProcs and returns the wall time.
+func RunAccumulate(mode AccumulateMode, maxProcs, workers, total int) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	start := time.Now()
+	mode.run(workers, total/workers)
+	return time.Since(start)
+}
+
+func localAccumulate(workers, perWorker int) int64 {
+	var wg sync.WaitGroup
+	var total atomic.Int64
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			var sum int64
+			for j := 0; j < perWorker; j++ {
+				sum += addend(j)
+			}
+			total.Add(sum)
+		}()
+	}
+	wg.Wait()
+	return total.Load()
+}
+
+func slotAccumulate(workers, perWorker int) int64 {
+	var wg sync.WaitGroup
+	// Adjacent int64s share cache lines, so every store to a slot
+	// invalidates the line in the other cores even though no two workers
+	// touch the same slot
+	slots := make([]int64, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				slots[w] += addend(j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var total int64
+	for _, s := range slots {
+		total += s
+	}
+	return total
+}
+
+func atomicAccumulate(workers, perWorker int) int64 {
+	var wg sync.WaitGroup
+	var total atomic.Int64
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				total.Add(addend(j))
+			}
+		}()
+	}
+	wg.Wait()
+	return total.Load()
+}
+
+func mutexAccumulate(workers, perWorker int) int64 {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var total int64
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				mu.Lock()
+				total += addend(j)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return total
+}
@@ -0,0 +1,165 @@
+package workloads
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Backend simulates a downstream service that serves Capacity calls at
+// its base latency; beyond that, calls queue inside it and latency grows
+// with the square of the overload, so pushing more calls at it loses
+// throughput instead of gaining it.
+type Backend struct {
+	Capacity int
+	Base     time.Duration
+	inflight atomic.Int64
+}
+
+func NewBackend(capacity int, base time.Duration) *Backend {
+	return &Backend{Capacity: capacity, Base: base}
+}
+
+// Call serves one call and returns how long it took.
+func (b *Backend) Call() time.Duration {
+	n := b.inflight.Add(1)
+	defer b.inflight.Add(-1)
+
+	overload := max(0, float64(n)-float64(b.Capacity)) / float64(b.Capacity)
+	latency := time.Duration(float64(b.Base) * (1 + overload*overload))
+	time.Sleep(latency)
+	return latency
+}
+
+// Limiter caps the calls in flight to a backend. Release reports each
+// call's latency, which an adaptive limiter steers by.
+type Limiter interface {
+	Acquire()
+	Release(latency time.Duration)
+	Limit() float64
+	String() string
+}
+
+// slotLimiter holds callers until fewer than limit calls are in flight,
+// admitting them in arrival order so no client starves.
+type slotLimiter struct {
+	mu       sync.Mutex
+	inflight int
+	limit    float64
+	waiters  []chan struct{}
+}
+
+func (l *slotLimiter) Acquire() {
+	l.mu.Lock()
+	if len(l.waiters) == 0 && l.free() {
+		l.inflight++
+		l.mu.Unlock()
+		return
+	}
+	admit := make(chan struct{})
+	l.waiters = append(l.waiters, admit)
+	l.mu.Unlock()
+	<-admit // release took the slot for us
+}
+
+func (l *slotLimiter) free() bool {
+	return float64(l.inflight) < max(1, l.limit)
+}
+
+func (l *slotLimiter) Limit() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// release frees a slot after adjust, if set, has updated the limit, and
+// hands what the limit allows to the longest waiters.
+func (l *slotLimiter) release(adjust func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inflight--
+	if adjust != nil {
+		adjust()
+	}
+	for len(l.waiters) > 0 && l.free() {
+		l.inflight++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+}
+
+// FixedLimiter allows limit calls in flight, whatever the backend does.
+type FixedLimiter struct{ slotLimiter }
+
+func NewFixedLimiter(limit int) *FixedLimiter {
+	return &FixedLimiter{slotLimiter{limit: float64(limit)}}
+}
+
+func (l *FixedLimiter) Release(time.Duration) { l.release(nil) }
+func (l *FixedLimiter) String() string        { return fmt.Sprintf("fixed %d", int(l.limit)) }
+
+// AIMDLimiter grows its limit by one per limit's worth of calls that meet
+// Target (additive increase, about one per round trip) and cuts it by
+// Backoff when calls miss (multiplicative decrease), the way TCP
+// congestion control probes for capacity. Like TCP it cuts at most once
+// per round trip, since every call already in flight when the backend
+// saturated misses too.
+type AIMDLimiter struct {
+	slotLimiter
+	Target  time.Duration
+	Backoff float64
+	lastCut time.Time
+}
+
+func NewAIMDLimiter(initial int, target time.Duration) *AIMDLimiter {
+	return &AIMDLimiter{slotLimiter: slotLimiter{limit: float64(initial)}, Target: target, Backoff: 0.9}
+}
+
+func (l *AIMDLimiter) Release(latency time.Duration) {
+	l.release(func() {
+		switch {
+		case latency <= l.Target:
+			l.limit += 1 / l.limit
+		case time.Since(l.lastCut) > latency:
+			l.limit = max(1, l.limit*l.Backoff)
+			l.lastCut = time.Now()
+		}
+	})
+}
+
+func (l *AIMDLimiter) String() string { return fmt.Sprintf("AIMD (target %v)", l.Target) }
+
+// RunLimited has clients goroutines call b back to back through l for
+// duration. It returns every call's latency from asking the limiter to
+// the backend answering, so time spent held back counts too, and the
+// backend's own latency for the same calls.
+func RunLimited(l Limiter, b *Backend, clients int, duration time.Duration) (latencies, service []time.Duration) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	deadline := time.Now().Add(duration)
+
+	for c := 0; c < clients; c++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			var own, ownService []time.Duration
+			for time.Now().Before(deadline) {
+				begin := time.Now()
+				l.Acquire()
+				took := b.Call()
+				l.Release(took)
+				own = append(own, time.Since(begin))
+				ownService = append(ownService, took)
+			}
+			mu.Lock()
+			latencies = append(latencies, own...)
+			service = append(service, ownService...)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	return latencies, service
+}
@@ -0,0 +1,26 @@
+package workloads
+
+// BurnCPU spins forever on pure ALU work.
+func BurnCPU() {
+	x := uint64(1)
+	for {
+		x = x*6364136223846793005 + 1442695040888963407
+	}
+}
+
+// ThrashMemory streams through two buffers far larger than the caches
+// forever, saturating memory bandwidth rather than the ALUs.
+func ThrashMemory(mb int) {
+	if mb < 1 {
+		mb = 1
+	}
+	src := make([]byte, mb<<20)
+	dst := make([]byte, mb<<20)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	for {
+		copy(dst, src)
+		copy(src, dst)
+	}
+}
@@ -0,0 +1,152 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AppendStrategy is one way for workers to build a single result slice.
+type AppendStrategy struct {
+	Name string
+	Note string
+	// build has workers workers produce items values between them and
+	// returns the slice they built
+	build func(workers, items int) []int64
+}
+
+// AppendStrategies returns the strategies the appends suite compares,
+// from one shared slice behind a lock to no sharing until the end.
+func AppendStrategies() []AppendStrategy {
+	return []AppendStrategy{
+		{"mutex", "append to one shared slice under a sync.Mutex", mutexAppend},
+		{"indexed", "preallocate every item and write each worker's range by index", indexedAppend},
+		{"merge", "append to a slice per worker, copied into one at the end", mergeAppend},
+		{"channel", "send every item on one channel to a collector that appends", channelAppend},
+	}
+}
+
+// itemValue is the item produced at index i, cheap enough that building
+// the slice dominates
+func itemValue(i int) int64 { return int64(i) * 2654435761 & 0xffff }
+
+// span is worker w's share of items, spreading the remainder so every
+// item is produced exactly once
+func span(w, workers, items int) (lo, hi int) {
+	return w * items / workers, (w + 1) * items / workers
+}
+
+// AppendWorkload has workers goroutines build a slice of items values
+// through s.
+func AppendWorkload(s AppendStrategy, workers, items int) Workload {
+	return New(s.Name, workers, func(maxProcs int, m *Metrics) time.Duration {
+		oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+		defer runtime.GOMAXPROCS(oldMaxProcs)
+
+		start := time.Now()
+		out := s.build(workers, items)
+		elapsed := time.Since(start)
+		atomic.AddUint64(&sink, uint64(len(out)))
+		m.Set("goroutines", float64(workers))
+		return elapsed
+	})
+}
+
+func mutexAppend(workers, items int) []int64 {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var out []int64
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, items)
+			for i := lo; i < hi; i++ {
+				v := itemValue(i)
+				mu.Lock()
+				out = append(out, v)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+func indexedAppend(workers, items int) []int64 {
+	var wg sync.WaitGroup
+	// Each worker owns a contiguous range, so only the lines at the
+	// range boundaries are ever shared
+	out := make([]int64, items)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, items)
+			for i := lo; i < hi; i++ {
+				out[i] = itemValue(i)
+			}
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+func mergeAppend(workers, items int) []int64 {
+	var wg sync.WaitGroup
+	parts := make([][]int64, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, items)
+			var part []int64
+			for i := lo; i < hi; i++ {
+				part = append(part, itemValue(i))
+			}
+			parts[w] = part
+		}()
+	}
+	wg.Wait()
+
+	n := 0
+	for _, p := range parts {
+		n += len(p)
+	}
+	out := make([]int64, 0, n)
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func channelAppend(workers, items int) []int64 {
+	var wg sync.WaitGroup
+	ch := make(chan int64, 256)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, items)
+			for i := lo; i < hi; i++ {
+				ch <- itemValue(i)
+			}
+		}()
+	}
+	go func() {
+		defer Guard()
+		wg.Wait()
+		close(ch)
+	}()
+
+	var out []int64
+	for v := range ch {
+		out = append(out, v)
+	}
+	return out
+}
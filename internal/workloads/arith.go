@@ -0,0 +1,86 @@
+package workloads
+
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+)
+
+const (
+	arithIters      = 20_000_000
+	arithOpsPerIter = 8
+	vectorLen       = 1024 // float32s; 4KB stays resident in L1
+	vectorPasses    = 40_000
+)
+
+// Ceilings are the machine's arithmetic throughputs in ops/second, on one
+// core and on all cores. Higher-level suites normalize against them.
+type Ceilings struct {
+	IntSingle, IntAll       float64
+	FloatSingle, FloatAll   float64
+	VectorSingle, VectorAll float64
+}
+
+func MeasureCeilings() *Ceilings {
+	procs := runtime.NumCPU()
+	return &Ceilings{
+		IntSingle:    MeasurePeakIntOps(1),
+		IntAll:       MeasurePeakIntOps(procs),
+		FloatSingle:  MeasurePeakFloatOps(1),
+		FloatAll:     MeasurePeakFloatOps(procs),
+		VectorSingle: MeasureVectorOps(1),
+		VectorAll:    MeasureVectorOps(procs),
+	}
+}
+
+// MeasurePeakIntOps estimates the integer ALU ceiling with independent
+// multiply-add chains that don't touch memory.
+func MeasurePeakIntOps(procs int) float64 {
+	elapsed := ParallelFor(procs, procs, func(lo, hi int) {
+		a, b, c, d := uint64(1), uint64(2), uint64(3), uint64(4)
+		for i := 0; i < arithIters; i++ {
+			a = a*3 + 1
+			b = b*5 + 1
+			c = c*7 + 1
+			d = d*9 + 1
+		}
+		atomic.AddUint64(&sink, a+b+c+d)
+	})
+	return float64(procs) * arithIters * arithOpsPerIter / elapsed.Seconds()
+}
+
+// MeasurePeakFloatOps is the floating point equivalent of MeasurePeakIntOps.
+func MeasurePeakFloatOps(procs int) float64 {
+	elapsed := ParallelFor(procs, procs, func(lo, hi int) {
+		a, b, c, d := 1.0, 2.0, 3.0, 4.0
+		for i := 0; i < arithIters; i++ {
+			a = a*0.9999999 + 0.5
+			b = b*0.9999998 + 0.5
+			c = c*0.9999997 + 0.5
+			d = d*0.9999996 + 0.5
+		}
+		atomic.AddUint64(&sink, math.Float64bits(a+b+c+d))
+	})
+	return float64(procs) * arithIters * arithOpsPerIter / elapsed.Seconds()
+}
+
+// MeasureVectorOps runs a saxpy over an L1-resident slice: the shape of loop
+// a vectorizing compiler would turn into SIMD. The gc compiler doesn't
+// auto-vectorize, so comparing it against the float ceiling shows how much
+// of the loop overhead remains.
+func MeasureVectorOps(procs int) float64 {
+	elapsed := ParallelFor(procs, procs, func(lo, hi int) {
+		x := make([]float32, vectorLen)
+		y := make([]float32, vectorLen)
+		for i := range x {
+			x[i] = float32(i)
+		}
+		for pass := 0; pass < vectorPasses; pass++ {
+			for i := range y {
+				y[i] += 1.0001 * x[i]
+			}
+		}
+		atomic.AddUint64(&sink, uint64(math.Float32bits(y[vectorLen-1])))
+	})
+	return float64(procs) * vectorPasses * vectorLen * 2 / elapsed.Seconds()
+}
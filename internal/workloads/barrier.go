@@ -0,0 +1,155 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// BarrierMode is one way to hold workers at the end of each phase of an
+// iterative parallel algorithm until all of them get there.
+type BarrierMode struct {
+	Name string
+	Note string
+	// run has workers workers do phases phases of work, none starting a
+	// phase before every worker finished the previous one
+	run func(workers, phases int, work func(worker int))
+}
+
+// BarrierModes returns the barrier implementations the barrier suite
+// compares.
+func BarrierModes() []BarrierMode {
+	return []BarrierMode{
+		{"WaitGroup", "fork-join: new goroutines each phase, one WaitGroup reused", waitGroupPhases},
+		{"channels", "long-lived workers report on a channel, a closed channel releases them", channelPhases},
+		{"sync.Cond", "long-lived workers count in under a mutex, the last Broadcasts", condPhases},
+	}
+}
+
+// phaseWork is a few microseconds of compute per worker per phase, so
+// synchronization is a visible share of each phase
+func phaseWork(slots []int) func(worker int) {
+	return func(worker int) {
+		sum := 0
+		for j := 0; j < 2000; j++ {
+			sum += j ^ worker
+		}
+		slots[worker] += sum
+	}
+}
+
+// RunBarrier runs phases phases of workers workers synchronized by mode
+// at maxProcs and returns the wall time.
+func RunBarrier(mode BarrierMode, maxProcs, workers, phases int) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	slots := make([]int, workers)
+	start := time.Now()
+	mode.run(workers, phases, phaseWork(slots))
+	return time.Since(start)
+}
+
+// RunPhaseWork does the same work on one goroutine with no barrier, the
+// baseline RunBarrier's synchronization cost is measured against.
+func RunPhaseWork(workers, phases int) time.Duration {
+	slots := make([]int, workers)
+	work := phaseWork(slots)
+	start := time.Now()
+	for p := 0; p < phases; p++ {
+		for w := 0; w < workers; w++ {
+			work(w)
+		}
+	}
+	return time.Since(start)
+}
+
+func waitGroupPhases(workers, phases int, work func(int)) {
+	var wg sync.WaitGroup
+	for p := 0; p < phases; p++ {
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer Guard()
+				defer wg.Done()
+				work(w)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func channelPhases(workers, phases int, work func(int)) {
+	arrived := make(chan struct{}, workers)
+	release := make([]chan struct{}, phases)
+	for p := range release {
+		release[p] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for p := 0; p < phases; p++ {
+				work(w)
+				arrived <- struct{}{}
+				<-release[p]
+			}
+		}()
+	}
+
+	for p := 0; p < phases; p++ {
+		for w := 0; w < workers; w++ {
+			<-arrived
+		}
+		close(release[p])
+	}
+	wg.Wait()
+}
+
+// condBarrier is a reusable barrier: the generation tells a waiter woken
+// by Broadcast that its own phase, not a later one, is over.
+type condBarrier struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	parties    int
+	waiting    int
+	generation int
+}
+
+func (b *condBarrier) await() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	gen := b.generation
+	b.waiting++
+	if b.waiting == b.parties {
+		b.waiting = 0
+		b.generation++
+		b.cond.Broadcast()
+		return
+	}
+	for gen == b.generation {
+		b.cond.Wait()
+	}
+}
+
+func condPhases(workers, phases int, work func(int)) {
+	b := &condBarrier{parties: workers}
+	b.cond = sync.NewCond(&b.mu)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for p := 0; p < phases; p++ {
+				work(w)
+				b.await()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,149 @@
+package workloads
+
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Graph is a directed graph in compressed sparse row form: the neighbors
+// of v are edges[offsets[v]:offsets[v+1]].
+type Graph struct {
+	Name    string
+	offsets []int32
+	edges   []int32
+}
+
+func (g *Graph) Vertices() int { return len(g.offsets) - 1 }
+func (g *Graph) Edges() int    { return len(g.edges) }
+
+func (g *Graph) neighbors(v int32) []int32 {
+	return g.edges[g.offsets[v]:g.offsets[v+1]]
+}
+
+// RandomGraph links each of n vertices to degree uniformly random others.
+// It has a small diameter, so BFS finishes in a few levels with huge
+// frontiers, and every edge lands somewhere unpredictable in memory.
+func RandomGraph(n, degree int) *Graph {
+	rng := rand.New(rand.NewSource(1))
+	g := &Graph{Name: "random", offsets: make([]int32, n+1), edges: make([]int32, n*degree)}
+	for v := 0; v < n; v++ {
+		g.offsets[v+1] = int32((v + 1) * degree)
+		for e := v * degree; e < (v+1)*degree; e++ {
+			g.edges[e] = int32(rng.Intn(n))
+		}
+	}
+	return g
+}
+
+// GridGraph links each cell of a side×side grid to its four neighbors. Its
+// diameter is 2×side, so BFS takes that many levels of small frontiers,
+// and neighbors sit close together in memory.
+func GridGraph(side int) *Graph {
+	n := side * side
+	g := &Graph{Name: "grid", offsets: make([]int32, n+1), edges: make([]int32, 0, 4*n)}
+	for v := 0; v < n; v++ {
+		x, y := v%side, v/side
+		if x > 0 {
+			g.edges = append(g.edges, int32(v-1))
+		}
+		if x < side-1 {
+			g.edges = append(g.edges, int32(v+1))
+		}
+		if y > 0 {
+			g.edges = append(g.edges, int32(v-side))
+		}
+		if y < side-1 {
+			g.edges = append(g.edges, int32(v+side))
+		}
+		g.offsets[v+1] = int32(len(g.edges))
+	}
+	return g
+}
+
+// BFSResult is one traversal from vertex 0.
+type BFSResult struct {
+	Elapsed time.Duration
+	Levels  int
+	Reached int
+}
+
+// SerialBFS traverses g from vertex 0 with a single queue.
+func SerialBFS(g *Graph) BFSResult {
+	start := time.Now()
+	depth := make([]int32, g.Vertices())
+	for i := range depth {
+		depth[i] = -1
+	}
+	depth[0] = 0
+	queue := []int32{0}
+	for head := 0; head < len(queue); head++ {
+		v := queue[head]
+		for _, u := range g.neighbors(v) {
+			if depth[u] < 0 {
+				depth[u] = depth[v] + 1
+				queue = append(queue, u)
+			}
+		}
+	}
+	return BFSResult{Elapsed: time.Since(start), Levels: int(depth[queue[len(queue)-1]]) + 1, Reached: len(queue)}
+}
+
+// ParallelBFS traverses g from vertex 0 level by level at maxProcs: each
+// level's frontier is split across workers goroutines, which claim
+// unvisited neighbors with a compare-and-swap and collect them into their
+// own next-frontier buffers, and the level ends when all of them have
+// joined. That join is the per-level synchronization a deep graph pays
+// for at every level.
+func ParallelBFS(g *Graph, maxProcs, workers int) BFSResult {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	start := time.Now()
+	depth := make([]atomic.Int32, g.Vertices())
+	for i := range depth {
+		depth[i].Store(-1)
+	}
+	depth[0].Store(0)
+
+	frontier := []int32{0}
+	buffers := make([][]int32, workers)
+	levels, reached := 0, 1
+	for len(frontier) > 0 {
+		levels++
+		next := int32(levels)
+		chunk := (len(frontier) + workers - 1) / workers
+
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			lo := w * chunk
+			if lo >= len(frontier) {
+				buffers[w] = buffers[w][:0]
+				continue
+			}
+			hi := min(lo+chunk, len(frontier))
+			wg.Add(1)
+			go func() {
+				defer Guard()
+				defer wg.Done()
+				found := buffers[w][:0]
+				for _, v := range frontier[lo:hi] {
+					for _, u := range g.neighbors(v) {
+						if depth[u].Load() < 0 && depth[u].CompareAndSwap(-1, next) {
+							found = append(found, u)
+						}
+					}
+				}
+				buffers[w] = found
+			}()
+		}
+		wg.Wait()
+
+		frontier = slices.Concat(buffers...)
+		reached += len(frontier)
+	}
+	return BFSResult{Elapsed: time.Since(start), Levels: levels, Reached: reached}
+}
@@ -0,0 +1,64 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// BlockingPattern is one way a goroutine can wait, which decides whether
+// the runtime has to give it an OS thread of its own while it waits.
+type BlockingPattern struct {
+	Name  string
+	Note  string
+	Block func(d time.Duration)
+}
+
+// BlockingPatterns returns the patterns this build supports: timers park
+// the goroutine, while blocking syscalls, cgo calls and locked threads each
+// pin an OS thread, so the runtime starts more to keep GOMAXPROCS busy.
+func BlockingPatterns() []BlockingPattern {
+	patterns := []BlockingPattern{
+		{"sleep", "parked on a timer; no thread held", time.Sleep},
+		{"lockosthread", "locked goroutine owns its thread", sleepLocked},
+	}
+	if SyscallBlockingAvailable {
+		patterns = append(patterns, BlockingPattern{"syscall", "blocking read(2) holds its thread", blockInSyscall})
+	}
+	if CBaselineAvailable {
+		patterns = append(patterns, BlockingPattern{"cgo", "C call holds its thread", CSleep})
+	}
+	return patterns
+}
+
+// RunBlockingTasks has numTasks goroutines block blocks times for d each
+// using p, with GOMAXPROCS at maxProcs.
+func RunBlockingTasks(p BlockingPattern, maxProcs, numTasks, blocks int, d time.Duration) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < numTasks; i++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for j := 0; j < blocks; j++ {
+				p.Block(d)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start)
+}
+
+// sleepLocked sleeps on a thread no other goroutine may use. The lock is
+// never released, so when the task's goroutine exits the runtime exits its
+// thread instead of parking it, and each run starts from fresh threads.
+func sleepLocked(d time.Duration) {
+	runtime.LockOSThread()
+	time.Sleep(d)
+}
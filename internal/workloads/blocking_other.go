@@ -0,0 +1,10 @@
+//go:build !unix
+
+package workloads
+
+import "time"
+
+// Raw pipe syscalls are Unix-only
+const SyscallBlockingAvailable = false
+
+func blockInSyscall(d time.Duration) {}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package workloads
+
+import (
+	"syscall"
+	"time"
+)
+
+const SyscallBlockingAvailable = true
+
+// blockInSyscall blocks in read(2) on a pipe that another goroutine writes
+// to after d. Unlike os.File, raw syscalls bypass the netpoller, so the
+// reading thread really is stuck in the kernel for the whole wait.
+func blockInSyscall(d time.Duration) {
+	var fds [2]int
+	if err := syscall.Pipe(fds[:]); err != nil {
+		time.Sleep(d)
+		return
+	}
+	defer syscall.Close(fds[0])
+	defer syscall.Close(fds[1])
+
+	go func() {
+		defer Guard()
+		time.Sleep(d)
+		syscall.Write(fds[1], []byte{0})
+	}()
+	var buf [1]byte
+	syscall.Read(fds[0], buf[:])
+}
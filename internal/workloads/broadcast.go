@@ -0,0 +1,150 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// broadcastBuffer is the capacity of each subscriber's channel
+const broadcastBuffer = 64
+
+// event is one published message, stamped with its publish time
+type event struct {
+	at time.Duration // since the run started
+}
+
+// BroadcastMode is one way to deliver every message to every subscriber.
+type BroadcastMode struct {
+	Name string
+	Note string
+	// run publishes messages events to subscribers subscribers and has
+	// each record the delay until it saw each event, indexed
+	// [subscriber*messages+seq]
+	run func(subscribers, messages int, start time.Time, delays []time.Duration)
+}
+
+// BroadcastModes returns the fan-out strategies the broadcast suite
+// compares.
+func BroadcastModes() []BroadcastMode {
+	return []BroadcastMode{
+		{"channels", "one buffered channel per subscriber; a slow one blocks the publisher", channelBroadcast},
+		{"sync.Cond", "append to a shared log under a mutex, Broadcast to wake everyone", condBroadcast},
+		{"atomic COW", "publish a new slice snapshot via atomic.Pointer; subscribers poll", atomicBroadcast},
+	}
+}
+
+// RunBroadcast publishes messages events through mode to subscribers
+// subscribers and returns the wall time until the last subscriber saw the
+// last event, with every delivery delay.
+func RunBroadcast(mode BroadcastMode, subscribers, messages int) (time.Duration, []time.Duration) {
+	delays := make([]time.Duration, subscribers*messages)
+	start := time.Now()
+	mode.run(subscribers, messages, start, delays)
+	return time.Since(start), delays
+}
+
+func channelBroadcast(subscribers, messages int, start time.Time, delays []time.Duration) {
+	var wg sync.WaitGroup
+	chans := make([]chan event, subscribers)
+	for s := range chans {
+		chans[s] = make(chan event, broadcastBuffer)
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			seq := 0
+			for e := range chans[s] {
+				delays[s*messages+seq] = time.Since(start) - e.at
+				seq++
+			}
+		}()
+	}
+
+	for i := 0; i < messages; i++ {
+		e := event{at: time.Since(start)}
+		for _, ch := range chans {
+			ch <- e
+		}
+	}
+	for _, ch := range chans {
+		close(ch)
+	}
+	wg.Wait()
+}
+
+func condBroadcast(subscribers, messages int, start time.Time, delays []time.Duration) {
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	log := make([]event, 0, messages)
+
+	var wg sync.WaitGroup
+	for s := 0; s < subscribers; s++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			seen := 0
+			for seen < messages {
+				mu.Lock()
+				for len(log) == seen {
+					cond.Wait()
+				}
+				fresh := log[seen:]
+				mu.Unlock()
+
+				now := time.Since(start)
+				for _, e := range fresh {
+					delays[s*messages+seen] = now - e.at
+					seen++
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < messages; i++ {
+		mu.Lock()
+		log = append(log, event{at: time.Since(start)})
+		mu.Unlock()
+		cond.Broadcast()
+	}
+	wg.Wait()
+}
+
+func atomicBroadcast(subscribers, messages int, start time.Time, delays []time.Duration) {
+	// The backing array never moves, so each snapshot is a longer view of
+	// it and entries a subscriber can see are never written again
+	backing := make([]event, 0, messages)
+	var snapshot atomic.Pointer[[]event]
+	snapshot.Store(&backing)
+
+	var wg sync.WaitGroup
+	for s := 0; s < subscribers; s++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			seen := 0
+			for seen < messages {
+				fresh := (*snapshot.Load())[seen:]
+				if len(fresh) == 0 {
+					runtime.Gosched()
+					continue
+				}
+				now := time.Since(start)
+				for _, e := range fresh {
+					delays[s*messages+seen] = now - e.at
+					seen++
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < messages; i++ {
+		next := append(backing, event{at: time.Since(start)})
+		backing = next
+		snapshot.Store(&next)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,130 @@
+package workloads
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BuildStrategy is one way for workers to assemble a single output string.
+type BuildStrategy struct {
+	Name string
+	Note string
+	// build has workers workers format fragments lines between them and
+	// returns the assembled output
+	build func(workers, fragments int) string
+}
+
+// BuildStrategies returns the strategies the strings suite compares,
+// from nothing shared until the end to one shared builder.
+func BuildStrategies() []BuildStrategy {
+	return []BuildStrategy{
+		{"join", "a strings.Builder per worker, joined with strings.Join at the end", joinBuild},
+		{"locked", "one shared strings.Builder, locked around each fragment", lockedBuild},
+		{"channel", "each fragment sent as a string to a collector that owns the builder", channelBuild},
+	}
+}
+
+// appendFragment appends line i of the output to buf, a short formatted
+// record like a log line or a CSV row
+func appendFragment(buf []byte, i int) []byte {
+	buf = append(buf, "item "...)
+	buf = strconv.AppendInt(buf, int64(i), 10)
+	buf = append(buf, " value "...)
+	buf = strconv.AppendInt(buf, itemValue(i), 16)
+	return append(buf, '\n')
+}
+
+// BuildWorkload has workers goroutines assemble fragments lines into one
+// string through s, recording the output's size as the output_bytes
+// gauge.
+func BuildWorkload(s BuildStrategy, workers, fragments int) Workload {
+	return New(s.Name, workers, func(maxProcs int, m *Metrics) time.Duration {
+		oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+		defer runtime.GOMAXPROCS(oldMaxProcs)
+
+		start := time.Now()
+		out := s.build(workers, fragments)
+		elapsed := time.Since(start)
+		m.Set("goroutines", float64(workers))
+		m.Set("output_bytes", float64(len(out)))
+		return elapsed
+	})
+}
+
+func joinBuild(workers, fragments int) string {
+	var wg sync.WaitGroup
+	parts := make([]string, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, fragments)
+			var b strings.Builder
+			var buf []byte
+			for i := lo; i < hi; i++ {
+				buf = appendFragment(buf[:0], i)
+				b.Write(buf)
+			}
+			parts[w] = b.String()
+		}()
+	}
+	wg.Wait()
+	return strings.Join(parts, "")
+}
+
+func lockedBuild(workers, fragments int) string {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var b strings.Builder
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, fragments)
+			var buf []byte
+			for i := lo; i < hi; i++ {
+				buf = appendFragment(buf[:0], i)
+				mu.Lock()
+				b.Write(buf)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return b.String()
+}
+
+func channelBuild(workers, fragments int) string {
+	var wg sync.WaitGroup
+	ch := make(chan string, 256)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, fragments)
+			var buf []byte
+			for i := lo; i < hi; i++ {
+				buf = appendFragment(buf[:0], i)
+				// The fragment outlives buf, so it needs its own copy
+				ch <- string(buf)
+			}
+		}()
+	}
+	go func() {
+		defer Guard()
+		wg.Wait()
+		close(ch)
+	}()
+
+	var b strings.Builder
+	for f := range ch {
+		b.WriteString(f)
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package workloads
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// cancelCheckEvery is how many numbers a checked prime loop tests between
+// looks at its context, a few microseconds of work
+const cancelCheckEvery = 1024
+
+// CancelCase is one of the basic workloads written one way with respect to
+// cancellation: every goroutine repeats the workload's task until its
+// context ends, and Checked says whether the task itself watches the
+// context or only the loop around it does.
+type CancelCase struct {
+	Workload string
+	Checked  bool
+	tasks    int
+	task     func(ctx context.Context, g int)
+}
+
+// Style names how c's tasks handle cancellation.
+func (c CancelCase) Style() string {
+	if c.Checked {
+		return "checked"
+	}
+	return "unchecked"
+}
+
+// CancelCases returns the CPU, I/O and mixed workloads at c's sizes, each
+// unchecked, the way the basic workloads are written, and checked.
+func (c Config) CancelCases() []CancelCase {
+	var cases []CancelCase
+	for _, checked := range []bool{false, true} {
+		cpu := func(ctx context.Context, _ int) { c.primeTask(ctx, checked) }
+		io := func(ctx context.Context, _ int) { c.ioTask(ctx, checked) }
+		mixed := func(ctx context.Context, g int) {
+			if g%2 == 0 {
+				c.primeTask(ctx, checked)
+			} else {
+				c.ioTask(ctx, checked)
+			}
+		}
+		cases = append(cases,
+			CancelCase{"CPU", checked, c.tasks(1), cpu},
+			CancelCase{"I/O", checked, c.tasks(2), io},
+			CancelCase{"Mixed", checked, c.tasks(1), mixed},
+		)
+	}
+	return cases
+}
+
+// primeTask is CPUIntensiveTask's prime count, checking ctx every
+// cancelCheckEvery numbers when checked.
+func (c Config) primeTask(ctx context.Context, checked bool) {
+	for n := 2; n < c.PrimeLimit; n++ {
+		if checked && n%cancelCheckEvery == 0 && ctx.Err() != nil {
+			return
+		}
+		for i := 2; i*i <= n; i++ {
+			if n%i == 0 {
+				break
+			}
+		}
+	}
+}
+
+// ioTask is IOIntensiveTask's calls, waiting on ctx as well as the
+// simulated I/O when checked.
+func (c Config) ioTask(ctx context.Context, checked bool) {
+	for i := 0; i < c.IOOps; i++ {
+		if checked {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.IOSleep):
+			}
+		} else {
+			time.Sleep(c.IOSleep)
+		}
+		sum := 0
+		for j := 0; j < 50_000; j++ {
+			sum += j
+		}
+		_ = sum
+	}
+}
+
+// RunCancel starts cc's goroutines at maxProcs, cancels them after warmup
+// and returns how long it took from the cancel until the last of them
+// returned.
+func RunCancel(cc CancelCase, maxProcs int, warmup time.Duration) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	for g := 0; g < cc.tasks; g++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			// Like a worker pulling jobs: it notices cancellation between
+			// tasks whatever the task does inside
+			for ctx.Err() == nil {
+				cc.task(ctx, g)
+			}
+		}()
+	}
+
+	time.Sleep(warmup)
+	start := time.Now()
+	cancel()
+	wg.Wait()
+	return time.Since(start)
+}
@@ -0,0 +1,91 @@
+//go:build cgo && cbaseline
+
+package workloads
+
+/*
+#cgo CFLAGS: -O2
+#cgo LDFLAGS: -lpthread
+#include <pthread.h>
+#include <stdlib.h>
+#include <unistd.h>
+
+// Same trial division as CPUIntensiveTask
+static int count_primes(int limit) {
+	int count = 0;
+	for (int n = 2; n < limit; n++) {
+		int is_prime = 1;
+		for (int i = 2; i * i <= n; i++) {
+			if (n % i == 0) {
+				is_prime = 0;
+				break;
+			}
+		}
+		count += is_prime;
+	}
+	return count;
+}
+
+typedef struct {
+	int limit;
+	int result;
+} prime_job;
+
+static void *prime_thread(void *arg) {
+	prime_job *job = arg;
+	job->result = count_primes(job->limit);
+	return NULL;
+}
+
+static int run_primes_serial(int tasks, int limit) {
+	int total = 0;
+	for (int t = 0; t < tasks; t++) {
+		total += count_primes(limit);
+	}
+	return total;
+}
+
+static int run_primes_pthreads(int tasks, int limit) {
+	pthread_t *threads = malloc(sizeof(pthread_t) * tasks);
+	prime_job *jobs = malloc(sizeof(prime_job) * tasks);
+	int total = 0;
+
+	for (int t = 0; t < tasks; t++) {
+		jobs[t].limit = limit;
+		pthread_create(&threads[t], NULL, prime_thread, &jobs[t]);
+	}
+	for (int t = 0; t < tasks; t++) {
+		pthread_join(threads[t], NULL);
+		total += jobs[t].result;
+	}
+
+	free(threads);
+	free(jobs);
+	return total;
+}
+// Blocks the calling thread, for the thread-growth suite
+static void sleep_us(int us) {
+	usleep(us);
+}
+*/
+import "C"
+
+import "time"
+
+const CBaselineAvailable = true
+
+func RunCPrimesSerial(tasks, limit int) time.Duration {
+	start := time.Now()
+	C.run_primes_serial(C.int(tasks), C.int(limit))
+	return time.Since(start)
+}
+
+func RunCPrimesPthreads(tasks, limit int) time.Duration {
+	start := time.Now()
+	C.run_primes_pthreads(C.int(tasks), C.int(limit))
+	return time.Since(start)
+}
+
+// CSleep blocks in C for d, holding the calling OS thread.
+func CSleep(d time.Duration) {
+	C.sleep_us(C.int(d.Microseconds()))
+}
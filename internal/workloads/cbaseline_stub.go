@@ -0,0 +1,12 @@
+//go:build !(cgo && cbaseline)
+
+package workloads
+
+import "time"
+
+// The C baseline is opt-in: build with -tags cbaseline (and cgo enabled)
+const CBaselineAvailable = false
+
+func RunCPrimesSerial(tasks, limit int) time.Duration   { return 0 }
+func RunCPrimesPthreads(tasks, limit int) time.Duration { return 0 }
+func CSleep(d time.Duration)                            {}
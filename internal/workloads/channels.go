@@ -0,0 +1,49 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// RunChannelPairs has pairs producers each send their share of messages
+// values to their own consumer, through a private channel of capacity
+// buffer, at GOMAXPROCS=maxProcs. It returns the wall time until every
+// consumer has drained its channel. No two pairs share a channel, so the
+// cost is the handoff itself: a parked consumer woken on another P, or
+// the producer running ahead into the buffer.
+func RunChannelPairs(pairs, buffer, messages, maxProcs int) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for p := 0; p < pairs; p++ {
+		// Spread the remainder so exactly messages values are sent
+		n := messages / pairs
+		if p < messages%pairs {
+			n++
+		}
+		ch := make(chan int, buffer)
+		wg.Add(2)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			defer close(ch)
+			for i := 0; i < n; i++ {
+				ch <- i
+			}
+		}()
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			sum := 0
+			for v := range ch {
+				sum += v
+			}
+			_ = sum
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
@@ -0,0 +1,112 @@
+package workloads
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock is the time the simulated I/O waits on and is timed by. The real
+// clock measures; a virtual clock makes the I/O workload instant and its
+// timings reproducible, for checking the harness rather than the
+// machine.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	// Add changes the number of goroutines sleeping on the clock, like
+	// sync.WaitGroup.Add: each adds one before it starts and removes it
+	// when it finishes
+	Add(delta int)
+}
+
+// RealClock is the wall clock. Add does nothing.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+func (realClock) Add(int)               {}
+
+// VirtualClock only moves when every goroutine added to it is asleep,
+// and then straight to the earliest wake-up. Sleeps return at once in
+// real time, work between them takes no virtual time, and a wave's
+// elapsed time depends only on the sleeps it makes, whatever GOMAXPROCS
+// or the scheduler do.
+type VirtualClock struct {
+	mu       sync.Mutex
+	now      time.Time
+	running  int
+	sleepers []sleeper
+}
+
+type sleeper struct {
+	wake time.Time
+	done chan struct{}
+}
+
+// NewVirtualClock returns a virtual clock stopped at the Unix epoch.
+func NewVirtualClock() *VirtualClock {
+	return &VirtualClock{now: time.Unix(0, 0)}
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *VirtualClock) Add(delta int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running += delta
+	if c.running < 0 {
+		panic("workloads: negative VirtualClock count")
+	}
+	c.advance()
+}
+
+func (c *VirtualClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.mu.Lock()
+	s := sleeper{wake: c.now.Add(d), done: make(chan struct{})}
+	c.sleepers = append(c.sleepers, s)
+	c.running--
+	c.advance()
+	c.mu.Unlock()
+	<-s.done
+}
+
+// advance moves the clock to the earliest wake-up once no goroutine is
+// running and wakes everyone due then. c.mu must be held.
+func (c *VirtualClock) advance() {
+	if c.running > 0 || len(c.sleepers) == 0 {
+		return
+	}
+	next := slices.MinFunc(c.sleepers, func(a, b sleeper) int { return a.wake.Compare(b.wake) })
+	c.now = next.wake
+	c.sleepers = slices.DeleteFunc(c.sleepers, func(s sleeper) bool {
+		if s.wake.After(c.now) {
+			return false
+		}
+		c.running++
+		close(s.done)
+		return true
+	})
+}
+
+// Virtual reports whether c's I/O runs on a virtual clock.
+func (c Config) Virtual() bool {
+	_, ok := c.Clock.(*VirtualClock)
+	return ok
+}
+
+// clock returns c.Clock, or the real clock when it is nil.
+func (c Config) clock() Clock {
+	if c.Clock == nil {
+		return RealClock
+	}
+	return c.Clock
+}
@@ -0,0 +1,76 @@
+package workloads
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVirtualClockSleepers(t *testing.T) {
+	tests := []struct {
+		name   string
+		sleeps [][]time.Duration
+		want   time.Duration
+	}{
+		{"one", [][]time.Duration{{time.Second}}, time.Second},
+		{"parallel", [][]time.Duration{{time.Second}, {2 * time.Second}, {time.Second}}, 2 * time.Second},
+		{"sequential", [][]time.Duration{{time.Second, time.Second, time.Second}}, 3 * time.Second},
+		{"interleaved", [][]time.Duration{{3 * time.Millisecond, time.Millisecond}, {time.Millisecond, time.Millisecond, time.Millisecond}}, 4 * time.Millisecond},
+		{"zero", [][]time.Duration{{0, 0}, {time.Millisecond}}, time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewVirtualClock()
+			start := clock.Now()
+			var wg sync.WaitGroup
+			clock.Add(len(tt.sleeps))
+			for _, sleeps := range tt.sleeps {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer clock.Add(-1)
+					for _, d := range sleeps {
+						clock.Sleep(d)
+					}
+				}()
+			}
+			wg.Wait()
+			if got := clock.Now().Sub(start); got != tt.want {
+				t.Errorf("elapsed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestVirtualClockIO checks a virtual I/O wave takes exactly its longest
+// task's sleeps, every time and at every GOMAXPROCS.
+func TestVirtualClockIO(t *testing.T) {
+	for _, dist := range LatencyDists() {
+		t.Run(dist.Name, func(t *testing.T) {
+			c := DefaultConfig()
+			c.Goroutines = 16
+			c.IODist = dist.Name
+			c.IOSeed = 1
+			c.Clock = NewVirtualClock()
+
+			var want time.Duration
+			for task := 0; task < c.Goroutines; task++ {
+				wait := c.ioWaits(task)
+				var total time.Duration
+				for i := 0; i < c.IOOps; i++ {
+					total += wait()
+				}
+				want = max(want, total)
+			}
+
+			w := c.IO()
+			for _, procs := range []int{1, 2, 4, 8} {
+				for run := 0; run < 3; run++ {
+					if got := w.Run(procs, nil); got != want {
+						t.Fatalf("GOMAXPROCS=%d run %d: elapsed = %v, want %v", procs, run, got, want)
+					}
+				}
+			}
+		})
+	}
+}
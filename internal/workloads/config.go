@@ -0,0 +1,162 @@
+package workloads
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Config sizes the CPU, I/O and mixed workloads and sets how finely they
+// are timed.
+type Config struct {
+	// PrimeLimit is how far each CPU task counts primes
+	PrimeLimit int
+	// IOSleep is the simulated wait of one I/O operation, and IOOps the
+	// number of operations each I/O task makes
+	IOSleep time.Duration
+	IOOps   int
+	// IODist names the LatencyDists entry the waits follow, with IOSleep
+	// as their mean; "" is constant. IOSeed seeds the draws
+	IODist string
+	IOSeed int64
+	// Goroutines is the number of tasks in every workload's wave; zero
+	// keeps the per-core defaults
+	Goroutines int
+	// Micro also times each operation inside the tasks, on top of the
+	// wave's wall time; the clock reads slow the tasks down
+	Micro bool
+	// Clock times the I/O wave and serves its simulated waits; nil is
+	// the real clock. The mixed wave keeps the real one, since its CPU
+	// half takes no virtual time
+	Clock Clock
+	// Disk replaces the simulated waits of the I/O and mixed waves with
+	// real file I/O: each operation writes DiskBytes to a temp file in
+	// DiskDir (the OS temp dir when empty), fsyncs it if Fsync is set,
+	// and reads it back
+	Disk      bool
+	DiskBytes int
+	Fsync     bool
+	DiskDir   string
+	// Net replaces them with real requests to an echo server on loopback
+	// inside the process, which answers each one after the wait
+	Net bool
+}
+
+// DefaultConfig returns the sizes the workloads have always used.
+func DefaultConfig() Config {
+	return Config{PrimeLimit: PrimeLimit, IOSleep: 5 * time.Millisecond, IOOps: 20, DiskBytes: 64 << 10}
+}
+
+// Validate reports the first setting no workload can run with.
+func (c Config) Validate() error {
+	switch {
+	case c.PrimeLimit < 2:
+		return fmt.Errorf("prime limit %d is below 2", c.PrimeLimit)
+	case c.IOSleep < 0:
+		return fmt.Errorf("negative I/O sleep %v", c.IOSleep)
+	case c.IOOps < 1:
+		return fmt.Errorf("I/O ops %d is below 1", c.IOOps)
+	case !validLatencyDist(c.IODist):
+		return fmt.Errorf("unknown I/O latency distribution %q", c.IODist)
+	case c.Goroutines < 0:
+		return fmt.Errorf("negative goroutine count %d", c.Goroutines)
+	case c.Disk && c.DiskBytes < 1:
+		return fmt.Errorf("disk I/O size %d is below 1 byte", c.DiskBytes)
+	case c.Disk && c.Virtual():
+		return fmt.Errorf("a virtual clock can't time real disk I/O")
+	case c.Net && c.Virtual():
+		return fmt.Errorf("a virtual clock can't time real network I/O")
+	case c.Net && c.Disk:
+		return fmt.Errorf("the I/O workload can't make both disk and network I/O")
+	}
+	if c.Disk && c.DiskDir != "" {
+		if fi, err := os.Stat(c.DiskDir); err != nil {
+			return fmt.Errorf("disk I/O directory: %w", err)
+		} else if !fi.IsDir() {
+			return fmt.Errorf("disk I/O directory %s is not a directory", c.DiskDir)
+		}
+	}
+	return nil
+}
+
+// tasks is the wave size for a workload whose default is perCore
+// goroutines per core.
+func (c Config) tasks(perCore int) int {
+	if c.Goroutines > 0 {
+		return c.Goroutines
+	}
+	return runtime.NumCPU() * perCore
+}
+
+// CPU counts primes in one goroutine per core.
+func (c Config) CPU() Workload {
+	return New("CPU", c.tasks(1), func(maxProcs int, m *Metrics) time.Duration {
+		return c.runCPUTasks(maxProcs, m)
+	})
+}
+
+// IO simulates network calls, or makes real file or network I/O when
+// c.Disk or c.Net is set, in two goroutines per core.
+func (c Config) IO() Workload {
+	return New("I/O", c.tasks(2), func(maxProcs int, m *Metrics) time.Duration {
+		return c.runIOTasks(maxProcs, m)
+	})
+}
+
+// Mixed alternates CPU and I/O tasks, one goroutine per core.
+func (c Config) Mixed() Workload {
+	return New("Mixed", c.tasks(1), func(maxProcs int, m *Metrics) time.Duration {
+		return c.runMixedTasks(maxProcs, m)
+	})
+}
+
+// Scalability splits a fixed sum of squares across four goroutines per
+// core, the scalability suite's most oversubscribed row.
+func (c Config) Scalability() Workload {
+	return New("Scalability", c.tasks(4), func(maxProcs int, m *Metrics) time.Duration {
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+		return RunScalabilityTest(c.tasks(4), maxProcs)
+	})
+}
+
+// Basic returns the CPU, I/O and mixed workloads a bench.Runner compares
+// when given none.
+func (c Config) Basic() []Workload {
+	return []Workload{c.CPU(), c.IO(), c.Mixed()}
+}
+
+// PrimeOps estimates the integer operations in one CPU workload run:
+// multiply, compare, modulo and increment per trial division.
+func (c Config) PrimeOps() float64 {
+	return PrimeInnerIterations(c.PrimeLimit) * 4 * float64(c.tasks(1))
+}
+
+func (c Config) String() string {
+	goroutines := "per-core"
+	if c.Goroutines > 0 {
+		goroutines = fmt.Sprint(c.Goroutines)
+	}
+	timing := "macro"
+	if c.Micro {
+		timing = "macro+micro"
+	}
+	if c.Virtual() {
+		timing += " clock=virtual"
+	}
+	io := fmt.Sprintf("io-sleep=%v", c.IOSleep)
+	if c.IODist != "" && c.IODist != "constant" {
+		io += fmt.Sprintf(" io-dist=%s io-seed=%d", c.IODist, c.IOSeed)
+	}
+	if c.Disk {
+		io = fmt.Sprintf("io-disk=%dB", c.DiskBytes)
+		if c.Fsync {
+			io += "+fsync"
+		}
+	}
+	if c.Net {
+		io = fmt.Sprintf("io-net=loopback+%v", c.IOSleep)
+	}
+	return fmt.Sprintf("prime-limit=%d %s io-ops=%d goroutines=%s timing=%s",
+		c.PrimeLimit, io, c.IOOps, goroutines, timing)
+}
@@ -0,0 +1,140 @@
+package workloads
+
+import (
+	"sync"
+	"time"
+)
+
+// ContentionBuffer is the capacity of the shared channel in contention runs
+const ContentionBuffer = 64
+
+// ChannelShape is how many goroutines send on and receive from one
+// shared channel.
+type ChannelShape struct {
+	Senders   int
+	Receivers int
+}
+
+// RunChannelContention has shape.Senders goroutines push messages values
+// between them through one buffered channel, drained by shape.Receivers
+// goroutines, and returns the wall time until the last value is received.
+// Every send and receive takes the channel's lock, so the goroutines
+// contend on it however many Ps there are.
+func RunChannelContention(shape ChannelShape, messages int) time.Duration {
+	ch := make(chan int, ContentionBuffer)
+	var senders, receivers sync.WaitGroup
+	start := time.Now()
+
+	for r := 0; r < shape.Receivers; r++ {
+		receivers.Add(1)
+		go func() {
+			defer Guard()
+			defer receivers.Done()
+			sum := 0
+			for v := range ch {
+				sum += v
+			}
+			_ = sum
+		}()
+	}
+
+	for s := 0; s < shape.Senders; s++ {
+		// Spread the remainder so exactly messages values are sent
+		n := messages / shape.Senders
+		if s < messages%shape.Senders {
+			n++
+		}
+		senders.Add(1)
+		go func() {
+			defer Guard()
+			defer senders.Done()
+			for i := 0; i < n; i++ {
+				ch <- i
+			}
+		}()
+	}
+
+	senders.Wait()
+	close(ch)
+	receivers.Wait()
+	return time.Since(start)
+}
+
+// shardBatch is how many values a shard's drainer collects before
+// forwarding them to the merge stage
+const shardBatch = 64
+
+// RunShardedChannels has senders goroutines push messages values between
+// them, spread over shards buffered channels. Each shard has its own
+// drainer that forwards values in batches to one merging receiver, so the
+// shared channel only sees one send per batch. With one shard the senders
+// share a single channel with the receiver and there is no merge stage.
+func RunShardedChannels(senders, shards, messages int) time.Duration {
+	if shards <= 1 {
+		return RunChannelContention(ChannelShape{Senders: senders, Receivers: 1}, messages)
+	}
+
+	merged := make(chan []int, shards)
+	var drainers sync.WaitGroup
+	received := make(chan struct{})
+	start := time.Now()
+
+	go func() {
+		defer Guard()
+		defer close(received)
+		sum := 0
+		for batch := range merged {
+			for _, v := range batch {
+				sum += v
+			}
+		}
+		_ = sum
+	}()
+
+	chans := make([]chan int, shards)
+	for k := range chans {
+		chans[k] = make(chan int, ContentionBuffer)
+		drainers.Add(1)
+		go func(ch <-chan int) {
+			defer Guard()
+			defer drainers.Done()
+			batch := make([]int, 0, shardBatch)
+			for v := range ch {
+				batch = append(batch, v)
+				if len(batch) == shardBatch {
+					merged <- batch
+					batch = make([]int, 0, shardBatch)
+				}
+			}
+			if len(batch) > 0 {
+				merged <- batch
+			}
+		}(chans[k])
+	}
+
+	var wg sync.WaitGroup
+	for s := 0; s < senders; s++ {
+		n := messages / senders
+		if s < messages%senders {
+			n++
+		}
+		ch := chans[s%shards]
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				ch <- i
+			}
+		}()
+	}
+
+	wg.Wait()
+	for _, ch := range chans {
+		close(ch)
+	}
+	drainers.Wait()
+	close(merged)
+	<-received
+	return time.Since(start)
+}
@@ -0,0 +1,138 @@
+package workloads
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sharedCounter is a counter every worker reads and increments.
+type sharedCounter interface {
+	inc(worker int)
+	load() int64
+}
+
+// CounterPrimitive is one way to guard a shared counter.
+type CounterPrimitive struct {
+	Name string
+	Note string
+	new  func(workers int) sharedCounter
+}
+
+// CounterPrimitives returns the primitives the counters suite compares,
+// from one lock to no shared cache line at all.
+func CounterPrimitives() []CounterPrimitive {
+	return []CounterPrimitive{
+		{"mutex", "sync.Mutex around every read and increment", func(int) sharedCounter { return &mutexCounter{} }},
+		{"rwmutex", "sync.RWMutex: reads share RLock, increments take Lock", func(int) sharedCounter { return &rwCounter{} }},
+		{"atomic", "atomic.AddInt64 and atomic.LoadInt64 on one int64", func(int) sharedCounter { return &atomicCounter{} }},
+		{"sharded", "an atomic per worker on its own cache line; reads sum them all", newShardedCounter},
+	}
+}
+
+type mutexCounter struct {
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *mutexCounter) inc(int) {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func (c *mutexCounter) load() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+type rwCounter struct {
+	mu sync.RWMutex
+	n  int64
+}
+
+func (c *rwCounter) inc(int) {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func (c *rwCounter) load() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.n
+}
+
+type atomicCounter struct{ n int64 }
+
+func (c *atomicCounter) inc(int)     { atomic.AddInt64(&c.n, 1) }
+func (c *atomicCounter) load() int64 { return atomic.LoadInt64(&c.n) }
+
+type shardedCounter struct {
+	shards []struct {
+		n atomic.Int64
+		// Keeps each shard on its own cache line
+		_ [56]byte
+	}
+}
+
+func newShardedCounter(workers int) sharedCounter {
+	c := &shardedCounter{}
+	c.shards = make([]struct {
+		n atomic.Int64
+		_ [56]byte
+	}, workers)
+	return c
+}
+
+func (c *shardedCounter) inc(worker int) { c.shards[worker].n.Add(1) }
+
+func (c *shardedCounter) load() int64 {
+	var total int64
+	for i := range c.shards {
+		total += c.shards[i].n.Load()
+	}
+	return total
+}
+
+// CounterWorkload has workers goroutines share ops operations on one
+// counter guarded by p: readPct percent of them reads, the rest
+// increments. Each worker spreads its reads evenly, offset from the
+// others so they don't move in lockstep.
+func CounterWorkload(p CounterPrimitive, workers, ops, readPct int) Workload {
+	perWorker := ops / workers
+	name := fmt.Sprintf("%s %d%% reads", p.Name, readPct)
+	return New(name, workers, func(maxProcs int, m *Metrics) time.Duration {
+		oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+		defer runtime.GOMAXPROCS(oldMaxProcs)
+
+		c := p.new(workers)
+		var wg sync.WaitGroup
+		var seen atomic.Int64
+		start := time.Now()
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer Guard()
+				defer wg.Done()
+				var last int64
+				for j := 0; j < perWorker; j++ {
+					if (j+w*37)%100 < readPct {
+						last = c.load()
+					} else {
+						c.inc(w)
+					}
+				}
+				seen.Add(last)
+			}()
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+		atomic.AddUint64(&sink, uint64(seen.Load()))
+		m.Set("goroutines", float64(workers))
+		return elapsed
+	})
+}
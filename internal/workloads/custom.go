@@ -0,0 +1,64 @@
+package workloads
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ShardFunc is one shard of a user-supplied workload. A run calls it once
+// per shard, each in its own goroutine, and the wave ends when all return.
+type ShardFunc func(ctx context.Context, shard int) error
+
+// Custom wraps fn as a workload of shards goroutines, or one per core
+// when shards is zero. Calls that return an error are counted in the
+// "errors" counter.
+func Custom(name string, shards int, fn ShardFunc) Workload {
+	if shards <= 0 {
+		shards = runtime.NumCPU()
+	}
+	return New(name, shards, func(maxProcs int, m *Metrics) time.Duration {
+		oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+		defer runtime.GOMAXPROCS(oldMaxProcs)
+
+		ctx := context.Background()
+		var wg sync.WaitGroup
+		errs := make([]bool, shards)
+		m.Set("goroutines", float64(shards))
+		start := time.Now()
+		for shard := 0; shard < shards; shard++ {
+			wg.Add(1)
+			go func() {
+				defer Guard()
+				defer wg.Done()
+				errs[shard] = fn(ctx, shard) != nil
+			}()
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+
+		failed := 0
+		for _, e := range errs {
+			if e {
+				failed++
+			}
+		}
+		if failed > 0 {
+			m.Add("errors", float64(failed))
+		}
+		return elapsed
+	})
+}
+
+// RegisterCustom registers fn as a custom workload under name. With
+// shards zero it runs -goroutines shards, or one per core. Custom
+// workloads are what the custom suite compares.
+func RegisterCustom(name string, shards int, fn ShardFunc) {
+	register(Registration{Name: name, Custom: true, New: func(c Config) Workload {
+		if shards == 0 {
+			return Custom(name, c.tasks(1), fn)
+		}
+		return Custom(name, shards, fn)
+	}})
+}
@@ -0,0 +1,77 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestCustomShards(t *testing.T) {
+	var mu sync.Mutex
+	var shards []int
+	w := Custom("shards", 5, func(_ context.Context, shard int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		shards = append(shards, shard)
+		return nil
+	})
+	if w.Name() != "shards" || w.Tasks() != 5 {
+		t.Fatalf("workload %s of %d tasks, want shards of 5", w.Name(), w.Tasks())
+	}
+	m := NewMetrics()
+	w.Run(2, m)
+	slices.Sort(shards)
+	if !slices.Equal(shards, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("ran shards %v, want 0 to 4 once each", shards)
+	}
+	if _, ok := m.Counters()["errors"]; ok {
+		t.Errorf("counted errors %v for a run without any", m.Counters())
+	}
+	if m.Gauges()["goroutines"] != 5 {
+		t.Errorf("gauges %v, want 5 goroutines", m.Gauges())
+	}
+
+	if w := Custom("cores", 0, func(context.Context, int) error { return nil }); w.Tasks() != runtime.NumCPU() {
+		t.Errorf("zero shards gave %d tasks, want one per core (%d)", w.Tasks(), runtime.NumCPU())
+	}
+}
+
+func TestCustomErrors(t *testing.T) {
+	w := Custom("errors", 6, func(_ context.Context, shard int) error {
+		if shard%2 == 0 {
+			return errors.New("odd shards only")
+		}
+		return nil
+	})
+	m := NewMetrics()
+	w.Run(1, m)
+	w.Run(2, m)
+	if got := m.Counters()["errors"]; got != 6 {
+		t.Errorf("counted %g errors over two runs of 3 failing shards, want 6", got)
+	}
+}
+
+func TestRegisterCustom(t *testing.T) {
+	ok := func(context.Context, int) error { return nil }
+	RegisterCustom("test-custom-fixed", 3, ok)
+	RegisterCustom("test-custom-sized", 0, ok)
+
+	regs := Registered(regexp.MustCompile("^test-custom-"))
+	if len(regs) != 2 || !regs[0].Custom || !regs[1].Custom {
+		t.Fatalf("registered %v, want two custom workloads", regs)
+	}
+	c := Config{Goroutines: 7}
+	if w := regs[0].New(c); w.Tasks() != 3 {
+		t.Errorf("fixed shards ran %d tasks, want 3", w.Tasks())
+	}
+	if w := regs[1].New(c); w.Tasks() != 7 {
+		t.Errorf("zero shards ran %d tasks, want -goroutines (7)", w.Tasks())
+	}
+	if w := regs[1].New(Config{}); w.Tasks() != runtime.NumCPU() {
+		t.Errorf("zero shards without -goroutines ran %d tasks, want %d", w.Tasks(), runtime.NumCPU())
+	}
+}
@@ -0,0 +1,113 @@
+package workloads
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dagNodeWork is the prime limit each DAG node counts to, tens of
+// microseconds of compute like compiling one small package
+const dagNodeWork = 3000
+
+// DAGShape is a layered dependency graph: Layers layers of Width nodes,
+// each node depending on up to FanIn nodes of the layer before.
+type DAGShape struct {
+	Name   string
+	Layers int
+	Width  int
+	FanIn  int
+}
+
+// DAGShapes returns graphs of the same size from wide and shallow to a
+// single chain, so only their structure differs.
+func DAGShapes() []DAGShape {
+	return []DAGShape{
+		{"wide", 4, 256, 4},
+		{"balanced", 32, 32, 4},
+		{"narrow", 256, 4, 2},
+		{"chain", 1024, 1, 1},
+	}
+}
+
+// Nodes is the node count.
+func (s DAGShape) Nodes() int { return s.Layers * s.Width }
+
+// Bound is the best speedup workers workers can reach on s with equal
+// node costs: the work over the span, since the Layers nodes of the
+// longest dependency path run one after another however many cores are
+// idle, capped at the worker count.
+func (s DAGShape) Bound(workers int) float64 {
+	return min(float64(workers), float64(s.Nodes())/float64(s.Layers))
+}
+
+// DAG is a generated dependency graph, nodes numbered layer by layer.
+type DAG struct {
+	deps       []int   // dependency count per node
+	dependents [][]int // nodes waiting on each node
+}
+
+// NewDAG generates s with fixed randomness, so every run builds the same
+// graph.
+func NewDAG(s DAGShape) *DAG {
+	rng := rand.New(rand.NewSource(1))
+	d := &DAG{deps: make([]int, s.Nodes()), dependents: make([][]int, s.Nodes())}
+	for layer := 1; layer < s.Layers; layer++ {
+		for i := 0; i < s.Width; i++ {
+			node := layer*s.Width + i
+			// Distinct parents from the previous layer
+			for _, p := range rng.Perm(s.Width)[:min(s.FanIn, s.Width)] {
+				parent := (layer-1)*s.Width + p
+				d.dependents[parent] = append(d.dependents[parent], node)
+				d.deps[node]++
+			}
+		}
+	}
+	return d
+}
+
+// RunDAG executes d on workers goroutines at maxProcs and returns the
+// wall time. A node becomes ready when its last dependency finishes, and
+// the worker finishing that dependency queues it, the way a build tool
+// schedules a topological order on the fly.
+func RunDAG(d *DAG, maxProcs, workers int) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	nodes := len(d.deps)
+	unmet := make([]atomic.Int32, nodes)
+	// Room for every node, so queueing a ready one never blocks a worker
+	ready := make(chan int, nodes)
+	for n, deps := range d.deps {
+		unmet[n].Store(int32(deps))
+		if deps == 0 {
+			ready <- n
+		}
+	}
+
+	var wg sync.WaitGroup
+	var done atomic.Int64
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for n := range ready {
+				countPrimes(dagNodeWork)
+				for _, next := range d.dependents[n] {
+					if unmet[next].Add(-1) == 0 {
+						ready <- next
+					}
+				}
+				if done.Add(1) == int64(nodes) {
+					close(ready)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
@@ -0,0 +1,166 @@
+package workloads
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// dedupBlockSize is the bytes per block, a small chunk as a content-defined
+// chunker would cut
+const dedupBlockSize = 256
+
+// dedupShards is the sharded set's shard count, enough that workers rarely
+// land on the same lock
+const dedupShards = 64
+
+// seenSet records block checksums; add reports whether sum is new.
+type seenSet interface {
+	add(sum uint64) bool
+}
+
+// DedupMode is one way for workers to share the set of blocks seen so far.
+type DedupMode struct {
+	Name string
+	Note string
+	new  func() seenSet
+}
+
+// DedupModes returns the seen-set implementations the dedup suite
+// compares.
+func DedupModes() []DedupMode {
+	return []DedupMode{
+		{"mutex", "one map behind one mutex", func() seenSet { return &mutexSet{seen: map[uint64]struct{}{}} }},
+		{"sharded", "64 maps, each behind its own mutex, picked by checksum", newShardedSet},
+		{"sync.Map", "one sync.Map, LoadOrStore per block", func() seenSet { return &syncMapSet{} }},
+	}
+}
+
+type mutexSet struct {
+	mu   sync.Mutex
+	seen map[uint64]struct{}
+}
+
+func (s *mutexSet) add(sum uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[sum]; ok {
+		return false
+	}
+	s.seen[sum] = struct{}{}
+	return true
+}
+
+type shardedSet struct {
+	shards [dedupShards]struct {
+		mu   sync.Mutex
+		seen map[uint64]struct{}
+		// Keeps each shard's lock on its own cache line
+		_ [48]byte
+	}
+}
+
+func newShardedSet() seenSet {
+	s := &shardedSet{}
+	for i := range s.shards {
+		s.shards[i].seen = map[uint64]struct{}{}
+	}
+	return s
+}
+
+func (s *shardedSet) add(sum uint64) bool {
+	// The low bits pick the map's bucket, so shard on the high ones
+	shard := &s.shards[sum>>58]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if _, ok := shard.seen[sum]; ok {
+		return false
+	}
+	shard.seen[sum] = struct{}{}
+	return true
+}
+
+type syncMapSet struct {
+	seen sync.Map
+}
+
+func (s *syncMapSet) add(sum uint64) bool {
+	_, loaded := s.seen.LoadOrStore(sum, struct{}{})
+	return !loaded
+}
+
+// DedupStream returns the block ids of a stream of blocks blocks drawn
+// from unique distinct contents, so about blocks-unique of them repeat
+// earlier ones, like a backup of files that mostly haven't changed.
+func DedupStream(blocks, unique int) []int32 {
+	rng := rand.New(rand.NewSource(1))
+	ids := make([]int32, blocks)
+	for i := range ids {
+		ids[i] = int32(rng.Intn(unique))
+	}
+	return ids
+}
+
+// readBlock fills buf with block id's contents, standing in for reading it
+// from disk
+func readBlock(id int32, buf []byte) {
+	x := uint64(id)*0x9e3779b97f4a7c15 + 1
+	for i := 0; i < len(buf); i += 8 {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+		binary.LittleEndian.PutUint64(buf[i:], x)
+	}
+}
+
+// checksum is 64-bit FNV-1a over buf
+func checksum(buf []byte) uint64 {
+	h := uint64(14695981039346656037)
+	for _, b := range buf {
+		h ^= uint64(b)
+		h *= 1099511628211
+	}
+	return h
+}
+
+// RunDedup has workers workers split ids into contiguous runs at maxProcs,
+// reading, checksumming and recording each block in one set built by
+// mode. It returns the wall time and how many distinct blocks the set
+// took, the same for every mode and worker count.
+func RunDedup(mode DedupMode, maxProcs, workers int, ids []int32) (time.Duration, int) {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	seen := mode.new()
+	counts := make([]int, workers)
+	chunk := (len(ids) + workers - 1) / workers
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		lo, hi := min(w*chunk, len(ids)), min((w+1)*chunk, len(ids))
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			buf := make([]byte, dedupBlockSize)
+			fresh := 0
+			for _, id := range ids[lo:hi] {
+				readBlock(id, buf)
+				if seen.add(checksum(buf)) {
+					fresh++
+				}
+			}
+			counts[w] = fresh
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	unique := 0
+	for _, n := range counts {
+		unique += n
+	}
+	return elapsed, unique
+}
@@ -0,0 +1,65 @@
+package workloads
+
+import (
+	"os"
+	"sync"
+)
+
+// diskIOTask is IOIntensiveTask against the disk: each of ops requests
+// writes size bytes to the task's own temp file in dir, fsyncs it when
+// fsync is set, and reads it back. Requests that fail count towards the
+// "errors" counter instead of "requests".
+func diskIOTask(dir string, ops, size int, fsync, micro bool, wg *sync.WaitGroup, m *Metrics) {
+	defer Guard()
+	defer wg.Done()
+
+	f, err := os.CreateTemp(dir, "compare_process-io-*")
+	if err != nil {
+		m.Add("errors", float64(ops))
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// Every task writes the same pattern; the contents don't matter, only
+	// that the kernel has to move them
+	buf := make([]byte, size)
+	for i := range buf {
+		buf[i] = byte(i)
+	}
+	failed := 0
+	timer := opTimer{on: micro}
+	defer timer.flush(m)
+	for i := 0; i < ops; i++ {
+		timer.begin()
+		if err := diskRoundTrip(f, buf, fsync); err != nil {
+			failed++
+		}
+
+		// Small CPU work between I/O, as in the simulated task
+		sum := 0
+		for j := 0; j < 50_000; j++ {
+			sum += j
+		}
+		timer.end()
+	}
+	m.Add("requests", float64(ops-failed))
+	if failed > 0 {
+		m.Add("errors", float64(failed))
+	}
+}
+
+// diskRoundTrip overwrites f from the start with buf, syncs it to the
+// device when fsync is set, and reads it back into buf.
+func diskRoundTrip(f *os.File, buf []byte, fsync bool) error {
+	if _, err := f.WriteAt(buf, 0); err != nil {
+		return err
+	}
+	if fsync {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+	_, err := f.ReadAt(buf, 0)
+	return err
+}
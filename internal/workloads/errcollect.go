@@ -0,0 +1,170 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrorStrategy is one way to collect the errors of parallel tasks.
+type ErrorStrategy struct {
+	Name string
+	Note string
+	// collect has workers workers run tasks tasks between them, task i
+	// failing when fails(i), and returns how many tasks ran and how many
+	// errors the caller got back
+	collect func(workers, tasks int, fails func(int) bool) (ran, returned int)
+}
+
+// ErrorStrategies returns the strategies the errors suite compares.
+func ErrorStrategies() []ErrorStrategy {
+	return []ErrorStrategy{
+		{"errgroup", "errgroup-style: the first error is kept and cancels the rest", firstErrorCollect},
+		{"multierror", "every error appended to a slice under a mutex, joined at the end", lockedErrorCollect},
+		{"channel", "every error sent on a channel drained by a collector goroutine", channelErrorCollect},
+	}
+}
+
+// ErrorScenario is which tasks of a run fail.
+type ErrorScenario struct {
+	Name  string
+	fails func(i int) bool
+}
+
+// ErrorScenarios returns the failure patterns the errors suite runs: none
+// for the bare overhead, the very first task for early failure, and one
+// task in a hundred for errors spread through the run.
+func ErrorScenarios() []ErrorScenario {
+	return []ErrorScenario{
+		{"none", func(int) bool { return false }},
+		{"early", func(i int) bool { return i == 0 }},
+		{"1%", func(i int) bool { return i%100 == 0 }},
+	}
+}
+
+// errTask is a few microseconds of work that fails when fail is set
+func errTask(i int, fail bool) error {
+	sum := 0
+	for j := 0; j < 2000; j++ {
+		sum += j ^ i
+	}
+	atomic.AddUint64(&sink, uint64(sum))
+	if fail {
+		return fmt.Errorf("task %d failed", i)
+	}
+	return nil
+}
+
+// ErrorWorkload has workers goroutines run tasks tasks, failing as
+// scenario says, and collect the errors through s. It records the tasks
+// that ran and the errors returned as the tasks_run and errors_returned
+// gauges.
+func ErrorWorkload(s ErrorStrategy, scenario ErrorScenario, workers, tasks int) Workload {
+	name := fmt.Sprintf("%s %s", s.Name, scenario.Name)
+	return New(name, workers, func(maxProcs int, m *Metrics) time.Duration {
+		oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+		defer runtime.GOMAXPROCS(oldMaxProcs)
+
+		start := time.Now()
+		ran, returned := s.collect(workers, tasks, scenario.fails)
+		elapsed := time.Since(start)
+		m.Set("goroutines", float64(workers))
+		m.Set("tasks_run", float64(ran))
+		m.Set("errors_returned", float64(returned))
+		return elapsed
+	})
+}
+
+func firstErrorCollect(workers, tasks int, fails func(int) bool) (ran, returned int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	var once sync.Once
+	var first error
+	var count atomic.Int64
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, tasks)
+			// Like a task started by errgroup.Go, each one checks the
+			// group's context before doing any work
+			for i := lo; i < hi && ctx.Err() == nil; i++ {
+				count.Add(1)
+				if err := errTask(i, fails(i)); err != nil {
+					once.Do(func() {
+						first = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if first != nil {
+		returned = 1
+	}
+	return int(count.Load()), returned
+}
+
+func lockedErrorCollect(workers, tasks int, fails func(int) bool) (ran, returned int) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, tasks)
+			for i := lo; i < hi; i++ {
+				if err := errTask(i, fails(i)); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	_ = errors.Join(errs...)
+	return tasks, len(errs)
+}
+
+func channelErrorCollect(workers, tasks int, fails func(int) bool) (ran, returned int) {
+	var wg sync.WaitGroup
+	errCh := make(chan error, workers)
+	collected := make(chan []error)
+	go func() {
+		var errs []error
+		defer func() { collected <- errs }()
+		defer Guard()
+		for err := range errCh {
+			errs = append(errs, err)
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			lo, hi := span(w, workers, tasks)
+			for i := lo; i < hi; i++ {
+				if err := errTask(i, fails(i)); err != nil {
+					errCh <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	errs := <-collected
+	_ = errors.Join(errs...)
+	return tasks, len(errs)
+}
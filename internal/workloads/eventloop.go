@@ -0,0 +1,130 @@
+package workloads
+
+import (
+	"container/heap"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ServeModel is one way to serve many connections whose requests arrive
+// on their own schedules.
+type ServeModel struct {
+	Name string
+	Note string
+	// serve handles every request of conns, recording each one's latency
+	// from arrival to handled at latencies[conn*requests+i]
+	serve func(conns [][]time.Duration, start time.Time, latencies []time.Duration)
+}
+
+// ServeModels returns the serving models the eventloop suite compares.
+func ServeModels() []ServeModel {
+	return []ServeModel{
+		{"goroutine/conn", "one goroutine per connection blocks until its next request", goroutinePerConn},
+		{"event loop", "one goroutine waits on a deadline heap and serves whatever is ready", eventLoop},
+	}
+}
+
+// handleRequest is a couple of microseconds of parsing and responding
+func handleRequest() {
+	sum := 0
+	for j := 0; j < 2000; j++ {
+		sum += j
+	}
+	_ = sum
+}
+
+// RunConnections simulates conns connections, each sending requests
+// requests spaced a random 0.5-1.5×gap apart, served by model at
+// maxProcs. It returns the wall time and every request's latency from its
+// arrival to the handler finishing, which is what a client sees on top of
+// the network.
+func RunConnections(model ServeModel, maxProcs, conns, requests int, gap time.Duration) (time.Duration, []time.Duration) {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	// The same seed gives both models the same arrivals
+	rng := rand.New(rand.NewSource(1))
+	arrivals := make([][]time.Duration, conns)
+	for c := range arrivals {
+		at := time.Duration(0)
+		arrivals[c] = make([]time.Duration, requests)
+		for i := range arrivals[c] {
+			at += gap/2 + time.Duration(rng.Int63n(int64(gap)))
+			arrivals[c][i] = at
+		}
+	}
+
+	latencies := make([]time.Duration, conns*requests)
+	start := time.Now()
+	model.serve(arrivals, start, latencies)
+	return time.Since(start), latencies
+}
+
+func goroutinePerConn(conns [][]time.Duration, start time.Time, latencies []time.Duration) {
+	var wg sync.WaitGroup
+	for c, arrivals := range conns {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for i, at := range arrivals {
+				// The blocking read: park until the request arrives
+				if wait := at - time.Since(start); wait > 0 {
+					time.Sleep(wait)
+				}
+				handleRequest()
+				latencies[c*len(arrivals)+i] = time.Since(start) - at
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// pending is a connection's next request in the event loop's heap
+type pending struct {
+	conn, req int
+	at        time.Duration
+}
+
+type deadlineHeap []pending
+
+func (h deadlineHeap) Len() int           { return len(h) }
+func (h deadlineHeap) Less(i, j int) bool { return h[i].at < h[j].at }
+func (h deadlineHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *deadlineHeap) Push(x any)        { *h = append(*h, x.(pending)) }
+func (h *deadlineHeap) Pop() any {
+	old := *h
+	p := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return p
+}
+
+func eventLoop(conns [][]time.Duration, start time.Time, latencies []time.Duration) {
+	h := make(deadlineHeap, 0, len(conns))
+	for c, arrivals := range conns {
+		if len(arrivals) > 0 {
+			h = append(h, pending{conn: c, at: arrivals[0]})
+		}
+	}
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		// Like epoll_wait with the earliest deadline as its timeout
+		next := &h[0]
+		if wait := next.at - time.Since(start); wait > 0 {
+			time.Sleep(wait)
+		}
+		handleRequest()
+		arrivals := conns[next.conn]
+		latencies[next.conn*len(arrivals)+next.req] = time.Since(start) - next.at
+
+		if next.req++; next.req < len(arrivals) {
+			next.at = arrivals[next.req]
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+}
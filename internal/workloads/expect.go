@@ -0,0 +1,46 @@
+package workloads
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Expectation is a qualitative outcome a workload should show on an
+// ordinary machine, such as "speedup ≥ 2× on ≥ 4 cores". Speedup is the
+// concurrent time over the parallel time.
+type Expectation struct {
+	// MinSpeedup and MaxSpeedup bound the speedup; zero leaves that side
+	// open. MaxSpeedup is exclusive.
+	MinSpeedup, MaxSpeedup float64
+	// MinCores is the fewest GOMAXPROCS the expectation applies to
+	MinCores int
+}
+
+// Applies reports whether the expectation is meant for parallel runs at
+// procs.
+func (e Expectation) Applies(procs int) bool { return procs >= e.MinCores }
+
+// Holds reports whether speedup is within the expected bounds.
+func (e Expectation) Holds(speedup float64) bool {
+	return (e.MinSpeedup == 0 || speedup >= e.MinSpeedup) &&
+		(e.MaxSpeedup == 0 || speedup < e.MaxSpeedup)
+}
+
+func (e Expectation) String() string {
+	x := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) + "×" }
+	var s string
+	switch {
+	case e.MinSpeedup > 0 && e.MaxSpeedup > 0:
+		s = fmt.Sprintf("%s ≤ speedup < %s", x(e.MinSpeedup), x(e.MaxSpeedup))
+	case e.MinSpeedup > 0:
+		s = "speedup ≥ " + x(e.MinSpeedup)
+	case e.MaxSpeedup > 0:
+		s = "speedup < " + x(e.MaxSpeedup)
+	default:
+		s = "any speedup"
+	}
+	if e.MinCores > 1 {
+		s += fmt.Sprintf(" on ≥ %d cores", e.MinCores)
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package workloads
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestExpect(t *testing.T) {
+	Register("test-expect", testWorkload("expect"))
+	first := Expectation{MinSpeedup: 2, MinCores: 4}
+	second := Expectation{MaxSpeedup: 1.3}
+	Expect("test-expect", first)
+	Expect("test-expect", second)
+
+	regs := Registered(regexp.MustCompile("^test-expect$"))
+	if len(regs) != 1 || !reflect.DeepEqual(regs[0].Expectations, []Expectation{first, second}) {
+		t.Fatalf("registered %v, want both expectations in order", regs)
+	}
+
+	// Registered hands out copies
+	regs[0].Expectations[0].MinSpeedup = 10
+	if got := Registered(regexp.MustCompile("^test-expect$"))[0].Expectations[0]; got != first {
+		t.Errorf("changing a returned expectation changed the registry to %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expect on an unregistered name did not panic")
+		}
+	}()
+	Expect("test-expect-missing", first)
+}
+
+func TestExpectation(t *testing.T) {
+	tests := []struct {
+		e       Expectation
+		speedup float64
+		procs   int
+		applies bool
+		holds   bool
+		str     string
+	}{
+		{Expectation{MinSpeedup: 2, MinCores: 4}, 2, 4, true, true, "speedup ≥ 2× on ≥ 4 cores"},
+		{Expectation{MinSpeedup: 2, MinCores: 4}, 1.9, 2, false, false, "speedup ≥ 2× on ≥ 4 cores"},
+		{Expectation{MaxSpeedup: 1.3}, 1.3, 1, true, false, "speedup < 1.3×"},
+		{Expectation{MaxSpeedup: 1.3}, 0.9, 8, true, true, "speedup < 1.3×"},
+		{Expectation{MinSpeedup: 1.2, MaxSpeedup: 3}, 3.5, 2, true, false, "1.2× ≤ speedup < 3×"},
+		{Expectation{}, 0.1, 1, true, true, "any speedup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := tt.e.Applies(tt.procs); got != tt.applies {
+				t.Errorf("Applies(%d) = %v, want %v", tt.procs, got, tt.applies)
+			}
+			if got := tt.e.Holds(tt.speedup); got != tt.holds {
+				t.Errorf("Holds(%g) = %v, want %v", tt.speedup, got, tt.holds)
+			}
+			if got := tt.e.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package workloads
+
+import "sync"
+
+// activeDepth and activeFrame size the call chain an active goroutine
+// parks in: about 4 KiB of live frames, like a handler blocked partway
+// through a request
+const (
+	activeDepth = 16
+	activeFrame = 256
+)
+
+// ParkGoroutines starts n goroutines and returns once all of them are
+// blocked. Idle ones park straight away on a channel; active ones park at
+// the bottom of a call chain, so their stacks have grown past the initial
+// size. release unblocks them all and waits for them to exit.
+func ParkGoroutines(n int, active bool) (release func()) {
+	var started, done sync.WaitGroup
+	gate := make(chan struct{})
+	started.Add(n)
+	done.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			defer Guard()
+			defer done.Done()
+			if active {
+				parkDeep(activeDepth, &started, gate)
+				return
+			}
+			started.Done()
+			<-gate
+		}()
+	}
+
+	started.Wait()
+	return func() {
+		close(gate)
+		done.Wait()
+	}
+}
+
+//go:noinline
+func parkDeep(depth int, started *sync.WaitGroup, gate <-chan struct{}) byte {
+	var frame [activeFrame]byte
+	frame[depth%activeFrame] = byte(depth)
+	if depth == 0 {
+		started.Done()
+		<-gate
+		return frame[0]
+	}
+	return parkDeep(depth-1, started, gate) + frame[depth%activeFrame]
+}
@@ -0,0 +1,123 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Future is the result of a call running on its own goroutine.
+type Future[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Async starts f on a new goroutine and returns its Future.
+func Async[T any](f func() (T, error)) *Future[T] {
+	fut := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer Guard()
+		defer close(fut.done)
+		fut.val, fut.err = f()
+	}()
+	return fut
+}
+
+// Await blocks until the call finishes and returns its result. Any number
+// of goroutines may await the same Future.
+func (f *Future[T]) Await() (T, error) {
+	<-f.done
+	return f.val, f.err
+}
+
+// AsyncMode is one way to fan calls out and collect their results.
+type AsyncMode struct {
+	Name string
+	Note string
+	// run makes calls asynchronous calls of work and returns the sum of
+	// their results
+	run func(calls int, work func(int) int) int
+}
+
+// AsyncModes returns the result-retrieval patterns the futures suite
+// compares.
+func AsyncModes() []AsyncMode {
+	return []AsyncMode{
+		{"channel", "each call sends its result on its own channel", channelResults},
+		{"slice+WaitGroup", "calls write their slot of a shared slice; one Wait", sliceResults},
+		{"Future[T]", "generic future: a done channel closed over the result", futureResults},
+	}
+}
+
+// asyncWork is a few hundred nanoseconds of compute, small enough that
+// the cost of going async dominates
+func asyncWork(i int) int {
+	sum := 0
+	for j := 0; j < 200; j++ {
+		sum += i ^ j
+	}
+	return sum
+}
+
+// RunAsyncCalls makes calls asynchronous calls with mode at maxProcs and
+// returns the wall time.
+func RunAsyncCalls(mode AsyncMode, maxProcs, calls int) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	start := time.Now()
+	mode.run(calls, asyncWork)
+	return time.Since(start)
+}
+
+func channelResults(calls int, work func(int) int) int {
+	results := make([]chan int, calls)
+	for i := range results {
+		results[i] = make(chan int, 1)
+		go func() {
+			// Send even after a panic, or the sum would wait forever
+			var r int
+			defer func() { results[i] <- r }()
+			defer Guard()
+			r = work(i)
+		}()
+	}
+	sum := 0
+	for _, ch := range results {
+		sum += <-ch
+	}
+	return sum
+}
+
+func sliceResults(calls int, work func(int) int) int {
+	var wg sync.WaitGroup
+	results := make([]int, calls)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			results[i] = work(i)
+		}()
+	}
+	wg.Wait()
+	sum := 0
+	for _, r := range results {
+		sum += r
+	}
+	return sum
+}
+
+func futureResults(calls int, work func(int) int) int {
+	futures := make([]*Future[int], calls)
+	for i := range futures {
+		futures[i] = Async(func() (int, error) { return work(i), nil })
+	}
+	sum := 0
+	for _, f := range futures {
+		v, _ := f.Await()
+		sum += v
+	}
+	return sum
+}
@@ -0,0 +1,68 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// gcLiveObjects is how many allocations each allocator keeps reachable,
+// so every GC cycle has a live heap to mark and not just garbage
+const gcLiveObjects = 256
+
+// TickerResult is one run of a latency-sensitive ticker beside allocating
+// goroutines: how late each tick woke past its deadline, the deadlines it
+// woke too late to serve at all, and the GC cycles and bytes allocated
+// meanwhile.
+type TickerResult struct {
+	Lateness  []time.Duration
+	Missed    int
+	GCs       uint32
+	Allocated uint64
+}
+
+// RunTickerUnderAlloc wakes a goroutine every period for duration, each
+// time recording how far past its deadline it ran, while allocators
+// goroutines churn the heap with 1-64 KiB slices. Deadlines are absolute,
+// so a late tick doesn't shift the ones after it; like time.Ticker, a
+// tick that wakes past later deadlines skips them as missed.
+func RunTickerUnderAlloc(period, duration time.Duration, allocators int) TickerResult {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < allocators; i++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			live := make([][]byte, gcLiveObjects)
+			for j := 0; !stop.Load(); j++ {
+				buf := make([]byte, 1<<10<<(j%7))
+				buf[0] = byte(j)
+				live[j%gcLiveObjects] = buf
+			}
+		}()
+	}
+
+	ticks := int(duration / period)
+	lateness := make([]time.Duration, 0, ticks)
+	missed := 0
+	start := time.Now()
+	for i := 1; i <= ticks; i++ {
+		deadline := start.Add(time.Duration(i) * period)
+		time.Sleep(time.Until(deadline))
+		late := max(time.Since(deadline), 0)
+		lateness = append(lateness, late)
+		skip := min(int(late/period), ticks-i)
+		missed += skip
+		i += skip
+	}
+
+	stop.Store(true)
+	wg.Wait()
+	runtime.ReadMemStats(&after)
+	return TickerResult{Lateness: lateness, Missed: missed, GCs: after.NumGC - before.NumGC, Allocated: after.TotalAlloc - before.TotalAlloc}
+}
@@ -0,0 +1,42 @@
+package workloads
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Panic is a panic recovered from a workload goroutine, with the stack it
+// was raised on.
+type Panic struct {
+	Value any
+	Stack []byte
+}
+
+func (p *Panic) Error() string { return fmt.Sprintf("panic: %v", p.Value) }
+
+// panics holds the first panic Guard recovered until the harness takes it
+var panics = make(chan *Panic, 1)
+
+// Guard, deferred first thing in a goroutine, turns a panic in it into a
+// report on Panics instead of a crash of the whole process. The goroutine
+// then returns normally, running its other defers, so the wave it belongs
+// to can still be waited for. A close or send that the goroutine's
+// consumers wait on goes in a defer before Guard's, so it runs after a
+// panic too.
+func Guard() {
+	if v := recover(); v != nil {
+		Report(&Panic{Value: v, Stack: debug.Stack()})
+	}
+}
+
+// Report hands p to the harness. Only the first panic until the harness
+// takes it is kept; the rest are usually its echoes.
+func Report(p *Panic) {
+	select {
+	case panics <- p:
+	default:
+	}
+}
+
+// Panics delivers the panics Guard recovers.
+func Panics() <-chan *Panic { return panics }
@@ -0,0 +1,49 @@
+package workloads
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGuardReleasesConsumers checks a panicking producer still releases
+// whatever waits on it, so the wave returns instead of hanging.
+func TestGuardReleasesConsumers(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func()
+	}{
+		{"channel results", func() {
+			channelResults(8, func(i int) int {
+				if i == 3 {
+					panic("work failed")
+				}
+				return i
+			})
+		}},
+		{"future", func() {
+			Async(func() (int, error) { panic("work failed") }).Await()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				tt.run()
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("wave still waiting after its producer panicked")
+			}
+			select {
+			case p := <-Panics():
+				if p.Value != "work failed" {
+					t.Errorf("panic value = %v, want %q", p.Value, "work failed")
+				}
+			default:
+				t.Error("panic not reported")
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package workloads
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StreamElems sizes the streaming arrays: 4M float64s = 32MB each, well
+// past the LLC
+const StreamElems = 1 << 22
+
+// sink keeps the compiler from discarding kernels whose results are unused
+var sink uint64
+
+// Kernel is a workload with a known amount of arithmetic and memory
+// traffic, so it can be placed on a roofline.
+type Kernel struct {
+	Name  string
+	Ops   float64 // arithmetic operations per run
+	Bytes float64 // bytes moved to/from memory per run (0 = register-resident)
+	Run   func(procs int) time.Duration
+}
+
+// Intensity is the kernel's arithmetic intensity in ops/byte.
+func (k Kernel) Intensity() float64 {
+	if k.Bytes == 0 {
+		return math.Inf(1)
+	}
+	return k.Ops / k.Bytes
+}
+
+// ParallelFor splits [0, n) into procs contiguous chunks and runs body on
+// each chunk in its own goroutine.
+func ParallelFor(procs, n int, body func(lo, hi int)) time.Duration {
+	return parallelChunks(procs, procs, n, body)
+}
+
+// parallelChunks is ParallelFor with the chunk count apart from
+// GOMAXPROCS, so a workload keeps its goroutines as Ps vary.
+func parallelChunks(procs, chunks, n int, body func(lo, hi int)) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(procs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	chunk := (n + chunks - 1) / chunks
+	for lo := 0; lo < n; lo += chunk {
+		hi := min(lo+chunk, n)
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			body(lo, hi)
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start)
+}
+
+// StreamArrays allocates the three arrays the streaming kernels use and
+// touches every page up front, so page faults don't count as memory
+// traffic.
+func StreamArrays() (a, b, c []float64) {
+	a = make([]float64, StreamElems)
+	b = make([]float64, StreamElems)
+	c = make([]float64, StreamElems)
+	for i := range b {
+		a[i] = 1
+		b[i] = float64(i)
+		c[i] = float64(i) * 0.5
+	}
+	return a, b, c
+}
+
+// MeasureMemoryBandwidth estimates the memory ceiling with a parallel copy
+// between two arrays larger than the caches, keeping the best of 3 runs.
+func MeasureMemoryBandwidth(procs int, src, dst []float64) float64 {
+	best := time.Duration(math.MaxInt64)
+	for i := 0; i < 3; i++ {
+		elapsed := ParallelFor(procs, len(src), func(lo, hi int) {
+			copy(dst[lo:hi], src[lo:hi])
+		})
+		best = min(best, elapsed)
+	}
+	return float64(len(src)) * 16 / best.Seconds()
+}
+
+// PrimeInnerIterations counts the trial divisions done by one prime task.
+func PrimeInnerIterations(limit int) float64 {
+	total := 0
+	for n := 2; n < limit; n++ {
+		for i := 2; i*i <= n; i++ {
+			total++
+			if n%i == 0 {
+				break
+			}
+		}
+	}
+	return float64(total)
+}
+
+// RooflineKernels returns the kernels placed on the roofline, using a, b
+// and c from StreamArrays for the streaming ones.
+func RooflineKernels(a, b, c []float64) []Kernel {
+	sumOps := float64(10_000_000) * 2
+
+	return []Kernel{
+		{"Prime (CPU)", DefaultConfig().PrimeOps(), 0, func(p int) time.Duration { return RunCPUTasks(p, nil) }},
+		{"SumSquares", sumOps, 0, func(p int) time.Duration {
+			return ParallelFor(p, 10_000_000, func(lo, hi int) {
+				s := 0
+				for j := lo; j < hi; j++ {
+					s += j * j
+				}
+				atomic.AddUint64(&sink, uint64(s))
+			})
+		}},
+		{"Triad", float64(len(a)) * 2, float64(len(a)) * 24, func(p int) time.Duration {
+			return ParallelFor(p, len(a), func(lo, hi int) {
+				for i := lo; i < hi; i++ {
+					a[i] = b[i] + 3.0*c[i]
+				}
+			})
+		}},
+		{"Reduction", float64(len(a)), float64(len(a)) * 8, func(p int) time.Duration {
+			return ParallelFor(p, len(a), func(lo, hi int) {
+				s := 0.0
+				for i := lo; i < hi; i++ {
+					s += a[i]
+				}
+				atomic.AddUint64(&sink, uint64(s))
+			})
+		}},
+	}
+}
@@ -0,0 +1,97 @@
+package workloads
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// paretoAlpha is the Pareto distribution's shape: below 2 its variance is
+// infinite, so a few waits run to many times the mean
+const paretoAlpha = 1.5
+
+// LatencyDist is how the simulated I/O waits are spread around their
+// mean. Every distribution has the same mean; they differ in the tail.
+type LatencyDist struct {
+	Name string
+	Note string
+	// sample draws one wait with the given mean
+	sample func(rng *rand.Rand, mean time.Duration) time.Duration
+}
+
+// LatencyDists returns the distributions the I/O waits can follow, the
+// constant one first.
+func LatencyDists() []LatencyDist {
+	return []LatencyDist{
+		{"constant", "every wait is exactly -io-sleep", func(_ *rand.Rand, mean time.Duration) time.Duration { return mean }},
+		{"uniform", "anywhere from 0 to twice -io-sleep", uniformLatency},
+		{"normal", "-io-sleep give or take a quarter of it, never below 0", normalLatency},
+		{"lognormal", "skewed right with σ=1, like most measured service times", lognormalLatency},
+		{"pareto", "heavy-tailed with α=1.5: most waits are short, a few are huge", paretoLatency},
+	}
+}
+
+// LatencyDistByName finds a distribution in LatencyDists; "" is
+// constant.
+func LatencyDistByName(name string) (LatencyDist, bool) {
+	if name == "" {
+		name = "constant"
+	}
+	dists := LatencyDists()
+	i := slices.IndexFunc(dists, func(d LatencyDist) bool { return d.Name == name })
+	if i < 0 {
+		return LatencyDist{}, false
+	}
+	return dists[i], true
+}
+
+func validLatencyDist(name string) bool {
+	_, ok := LatencyDistByName(name)
+	return ok
+}
+
+func uniformLatency(rng *rand.Rand, mean time.Duration) time.Duration {
+	return time.Duration(rng.Float64() * 2 * float64(mean))
+}
+
+func normalLatency(rng *rand.Rand, mean time.Duration) time.Duration {
+	return max(0, time.Duration((1+rng.NormFloat64()/4)*float64(mean)))
+}
+
+func lognormalLatency(rng *rand.Rand, mean time.Duration) time.Duration {
+	// exp(μ + σZ) has mean exp(μ + σ²/2), so μ = ln(mean) - 1/2 at σ=1
+	mu := math.Log(float64(mean)) - 0.5
+	return time.Duration(math.Exp(mu + rng.NormFloat64()))
+}
+
+func paretoLatency(rng *rand.Rand, mean time.Duration) time.Duration {
+	// The mean is α·xm/(α-1), so xm is a third of it at α=1.5
+	xm := float64(mean) * (paretoAlpha - 1) / paretoAlpha
+	return time.Duration(xm / math.Pow(1-rng.Float64(), 1/paretoAlpha))
+}
+
+// ioWaits returns the waits of I/O task number task: c.IOSleep each when
+// the distribution is constant, otherwise draws from c.IODist seeded by
+// c.IOSeed and the task number, so every run of a wave waits the same.
+func (c Config) ioWaits(task int) func() time.Duration {
+	dist, _ := LatencyDistByName(c.IODist)
+	if dist.Name == "constant" {
+		return func() time.Duration { return c.IOSleep }
+	}
+	rng := rand.New(rand.NewSource(c.IOSeed + int64(task)))
+	return func() time.Duration { return dist.sample(rng, c.IOSleep) }
+}
+
+// SampleIOWaits draws n waits the way the I/O workload's tasks do, for
+// describing the distribution.
+func (c Config) SampleIOWaits(n int) []time.Duration {
+	waits := make([]time.Duration, 0, n)
+	for task := 0; len(waits) < n; task++ {
+		wait := c.ioWaits(task)
+		for i := 0; i < c.IOOps && len(waits) < n; i++ {
+			waits = append(waits, wait())
+		}
+	}
+	return waits
+}
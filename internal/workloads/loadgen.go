@@ -0,0 +1,69 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stall freezes a server for a while, like a stop-the-world pause, a
+// lock held across a slow call or a noisy neighbor taking the core.
+type Stall struct {
+	until atomic.Int64 // UnixNano the server stays frozen until
+}
+
+// Freeze stops every request Wrap serves from starting for d.
+func (s *Stall) Freeze(d time.Duration) {
+	s.until.Store(time.Now().Add(d).UnixNano())
+}
+
+// Wrap returns kind with each request first waiting out any freeze.
+func (s *Stall) Wrap(kind RequestKind) RequestKind {
+	return RequestKind{kind.Name, func() {
+		if wait := time.Until(time.Unix(0, s.until.Load())); wait > 0 {
+			time.Sleep(wait)
+		}
+		kind.Serve()
+	}}
+}
+
+// RunPacedClosedLoop has clients goroutines each send perClient requests
+// of kind, one every pace, but never before the previous one returned:
+// the fixed-goroutine load generator most tools are. It returns the wall
+// time and two latencies per request: raw, from the moment it was
+// actually sent, and corrected, from the moment the pace meant to send
+// it. While the server stalls, a client sends nothing, so raw latencies
+// record one slow request per client and omit every request that should
+// have been waiting meanwhile: coordinated omission.
+func RunPacedClosedLoop(kind RequestKind, clients, perClient int, pace time.Duration, maxProcs int) (elapsed time.Duration, raw, corrected []time.Duration) {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	raw = make([]time.Duration, clients*perClient)
+	corrected = make([]time.Duration, clients*perClient)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for c := 0; c < clients; c++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			// Stagger the clients so together they send evenly
+			offset := pace * time.Duration(c) / time.Duration(clients)
+			for i := 0; i < perClient; i++ {
+				intended := offset + time.Duration(i)*pace
+				if wait := intended - time.Since(start); wait > 0 {
+					time.Sleep(wait)
+				}
+				sent := time.Since(start)
+				kind.Serve()
+				done := time.Since(start)
+				raw[c*perClient+i] = done - sent
+				corrected[c*perClient+i] = done - intended
+			}
+		}()
+	}
+	wg.Wait()
+	return time.Since(start), raw, corrected
+}
@@ -0,0 +1,144 @@
+package workloads
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MapKeys is the size of the key space the maps suite reads and writes,
+// small enough that every key stays hot in cache.
+const MapKeys = 4096
+
+// mapShards is the number of shards of the sharded map
+const mapShards = 64
+
+// sharedMap is a map every worker reads and writes.
+type sharedMap interface {
+	load(k int) (int, bool)
+	store(k, v int)
+}
+
+// MapImpl is one way to share a map between goroutines.
+type MapImpl struct {
+	Name string
+	Note string
+	new  func() sharedMap
+}
+
+// MapImpls returns the map implementations the maps suite compares.
+func MapImpls() []MapImpl {
+	return []MapImpl{
+		{"syncmap", "sync.Map, lock-free reads of keys that are already stored", func() sharedMap { return &syncMap{} }},
+		{"mutex", "one map behind a sync.RWMutex: reads share RLock, writes take Lock", func() sharedMap { return &mutexMap{m: map[int]int{}} }},
+		{"sharded", fmt.Sprintf("%d maps, each behind its own sync.RWMutex, picked by key", mapShards), newShardedMap},
+	}
+}
+
+// MapPattern is a mix of reads and writes against a shared map.
+type MapPattern struct {
+	Name    string
+	ReadPct int
+}
+
+// MapPatterns returns the access patterns the maps suite runs.
+func MapPatterns() []MapPattern {
+	return []MapPattern{{"read-heavy", 90}, {"mixed", 50}, {"write-heavy", 10}}
+}
+
+type syncMap struct{ m sync.Map }
+
+func (s *syncMap) load(k int) (int, bool) {
+	v, ok := s.m.Load(k)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (s *syncMap) store(k, v int) { s.m.Store(k, v) }
+
+type mutexMap struct {
+	mu sync.RWMutex
+	m  map[int]int
+}
+
+func (s *mutexMap) load(k int) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[k]
+	return v, ok
+}
+
+func (s *mutexMap) store(k, v int) {
+	s.mu.Lock()
+	s.m[k] = v
+	s.mu.Unlock()
+}
+
+type shardedMap struct {
+	shards [mapShards]struct {
+		mutexMap
+		// Keeps neighbouring shards' locks off each other's cache lines
+		_ [32]byte
+	}
+}
+
+func newShardedMap() sharedMap {
+	s := &shardedMap{}
+	for i := range s.shards {
+		s.shards[i].m = map[int]int{}
+	}
+	return s
+}
+
+func (s *shardedMap) load(k int) (int, bool) { return s.shards[k%mapShards].load(k) }
+func (s *shardedMap) store(k, v int)         { s.shards[k%mapShards].store(k, v) }
+
+// MapWorkload has workers goroutines share ops operations on a map of
+// MapKeys keys built by impl, readPct percent of them loads and the rest
+// stores. The map is filled before the clock starts, so loads hit.
+func MapWorkload(impl MapImpl, pattern MapPattern, workers, ops int) Workload {
+	perWorker := ops / workers
+	name := fmt.Sprintf("%s %s", impl.Name, pattern.Name)
+	return New(name, workers, func(maxProcs int, m *Metrics) time.Duration {
+		oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+		defer runtime.GOMAXPROCS(oldMaxProcs)
+
+		sm := impl.new()
+		for k := 0; k < MapKeys; k++ {
+			sm.store(k, k)
+		}
+		var wg sync.WaitGroup
+		var seen atomic.Int64
+		start := time.Now()
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer Guard()
+				defer wg.Done()
+				sum := 0
+				// Each worker walks the keys with its own stride so they
+				// don't move in lockstep
+				k := w * 37
+				for j := 0; j < perWorker; j++ {
+					k = (k + 2*w + 1) % MapKeys
+					if (j+w*37)%100 < pattern.ReadPct {
+						v, _ := sm.load(k)
+						sum += v
+					} else {
+						sm.store(k, j)
+					}
+				}
+				seen.Add(int64(sum))
+			}()
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+		atomic.AddUint64(&sink, uint64(seen.Load()))
+		m.Set("goroutines", float64(workers))
+		return elapsed
+	})
+}
@@ -0,0 +1,74 @@
+package workloads
+
+import (
+	"sync"
+	"time"
+)
+
+// streamScalar is the q of STREAM's scale and triad kernels
+const streamScalar = 3.0
+
+// MemoryKernel is a STREAM kernel as a workload, with the bytes one run
+// moves to and from memory so its time converts to bandwidth.
+type MemoryKernel struct {
+	Workload
+	Bytes float64
+}
+
+// streamKernels are STREAM's four loops over arrays a, b and c, with the
+// bytes each moves per element
+var streamKernels = []struct {
+	name  string
+	bytes int
+	body  func(a, b, c []float64, lo, hi int)
+}{
+	{"Copy", 16, func(a, _, c []float64, lo, hi int) { copy(c[lo:hi], a[lo:hi]) }},
+	{"Scale", 16, func(_, b, c []float64, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			b[i] = streamScalar * c[i]
+		}
+	}},
+	{"Add", 24, func(a, b, c []float64, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			c[i] = a[i] + b[i]
+		}
+	}},
+	{"Triad", 24, func(a, b, c []float64, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			a[i] = b[i] + streamScalar*c[i]
+		}
+	}},
+}
+
+// MemoryKernels returns STREAM's copy, scale, add and triad over arrays
+// past the last-level cache, split into one chunk per core. They do so
+// little arithmetic per byte that memory bandwidth, not the cores, sets
+// their speed. The kernels share one set of StreamArrays, allocated on
+// the first run and freed with the kernels.
+func (c Config) MemoryKernels() []MemoryKernel {
+	arrays := sync.OnceValue(func() [3][]float64 {
+		a, b, c := StreamArrays()
+		return [3][]float64{a, b, c}
+	})
+	kernels := make([]MemoryKernel, 0, len(streamKernels))
+	for _, k := range streamKernels {
+		tasks := c.tasks(1)
+		kernels = append(kernels, MemoryKernel{
+			Workload: New(k.name, tasks, func(maxProcs int, m *Metrics) time.Duration {
+				v := arrays()
+				m.Set("goroutines", float64(tasks))
+				return parallelChunks(maxProcs, tasks, StreamElems, func(lo, hi int) {
+					k.body(v[0], v[1], v[2], lo, hi)
+				})
+			}),
+			Bytes: float64(StreamElems * k.bytes),
+		})
+	}
+	return kernels
+}
+
+// Triad is STREAM's triad, a[i] = b[i] + q·c[i], the memory-bound
+// counterpart of the CPU workload.
+func (c Config) Triad() Workload {
+	return c.MemoryKernels()[3].Workload
+}
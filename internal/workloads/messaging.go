@@ -0,0 +1,190 @@
+package workloads
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MessageSize is the payload every message-passing mode hands over
+const MessageSize = 16 << 10
+
+type message struct {
+	id   int
+	body []byte
+}
+
+// valueMessage travels by value: the channel copies all of it on send and
+// again on receive
+type valueMessage struct {
+	id   int
+	body [MessageSize]byte
+}
+
+// MessageMode is one way for a producer to hand a payload to a consumer.
+type MessageMode struct {
+	Name string
+	Note string
+	// pair sends messages payloads from one producer to one consumer and
+	// returns how many arrived with someone else's contents
+	pair func(messages int) int
+}
+
+// MessageModes returns the message-passing modes, from handing over
+// ownership of fresh buffers to copying, plus the aliasing bug that
+// ownership transfer invites when the sender keeps using its buffer. The
+// bug is left out under -race, where it belongs to -race-lesson.
+func MessageModes() []MessageMode {
+	modes := []MessageMode{
+		{"pointer", "ownership moves with a fresh buffer per message", pointerPair},
+		{"pooled", "ownership moves and returns through a sync.Pool", pooledPair},
+		{"value", "the channel copies a fixed-size array", valuePair},
+		{"deep copy", "the sender clones its buffer for each send", deepCopyPair},
+	}
+	if !RaceEnabled {
+		modes = append(modes, MessageMode{"aliased", "BUG: the sender reuses the buffer it handed over", aliasedPair})
+	}
+	return modes
+}
+
+// RunMessagePassing runs pairs producer/consumer pairs, each passing
+// messages payloads with mode, and returns the wall time and the number of
+// corrupted messages.
+func RunMessagePassing(mode MessageMode, maxProcs, pairs, messages int) (time.Duration, int) {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	corrupted := 0
+	start := time.Now()
+
+	for i := 0; i < pairs; i++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			n := mode.pair(messages)
+			mu.Lock()
+			corrupted += n
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start), corrupted
+}
+
+func fillMessage(body []byte, id int) {
+	for i := range body {
+		body[i] = byte(id)
+	}
+}
+
+// consumeMessage reads the whole payload, as a real consumer would, and
+// reports whether it still holds message id's contents.
+func consumeMessage(body []byte, id int) bool {
+	ok := true
+	for _, b := range body {
+		if b != byte(id) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// consume drains ch on the calling goroutine and counts corrupted messages.
+func consume(ch <-chan *message, done func(*message)) int {
+	corrupted := 0
+	for m := range ch {
+		if !consumeMessage(m.body, m.id) {
+			corrupted++
+		}
+		if done != nil {
+			done(m)
+		}
+	}
+	return corrupted
+}
+
+func pointerPair(messages int) int {
+	ch := make(chan *message, 16)
+	go func() {
+		defer Guard()
+		defer close(ch)
+		for i := 0; i < messages; i++ {
+			m := &message{id: i, body: make([]byte, MessageSize)}
+			fillMessage(m.body, i)
+			ch <- m // m belongs to the consumer from here on
+		}
+	}()
+	return consume(ch, nil)
+}
+
+func pooledPair(messages int) int {
+	pool := sync.Pool{New: func() any { return &message{body: make([]byte, MessageSize)} }}
+	ch := make(chan *message, 16)
+	go func() {
+		defer Guard()
+		defer close(ch)
+		for i := 0; i < messages; i++ {
+			m := pool.Get().(*message)
+			m.id = i
+			fillMessage(m.body, i)
+			ch <- m
+		}
+	}()
+	return consume(ch, func(m *message) { pool.Put(m) })
+}
+
+func valuePair(messages int) int {
+	ch := make(chan valueMessage, 16)
+	go func() {
+		defer Guard()
+		defer close(ch)
+		var m valueMessage
+		for i := 0; i < messages; i++ {
+			m.id = i
+			fillMessage(m.body[:], i)
+			ch <- m
+		}
+	}()
+
+	corrupted := 0
+	for m := range ch {
+		if !consumeMessage(m.body[:], m.id) {
+			corrupted++
+		}
+	}
+	return corrupted
+}
+
+func deepCopyPair(messages int) int {
+	ch := make(chan *message, 16)
+	go func() {
+		defer Guard()
+		defer close(ch)
+		buf := make([]byte, MessageSize)
+		for i := 0; i < messages; i++ {
+			fillMessage(buf, i)
+			ch <- &message{id: i, body: slices.Clone(buf)}
+		}
+	}()
+	return consume(ch, nil)
+}
+
+func aliasedPair(messages int) int {
+	ch := make(chan *message, 16)
+	go func() {
+		defer Guard()
+		defer close(ch)
+		buf := make([]byte, MessageSize)
+		for i := 0; i < messages; i++ {
+			// Overwrites payloads still queued or being read
+			fillMessage(buf, i)
+			ch <- &message{id: i, body: buf}
+		}
+	}()
+	return consume(ch, nil)
+}
@@ -0,0 +1,60 @@
+package workloads
+
+import (
+	"maps"
+	"sync"
+)
+
+// Metrics collects the named counters and gauges a workload records while
+// it runs: requests issued, bytes processed, cache hits. A nil *Metrics
+// discards everything, so callers that only want the timing pass nil.
+type Metrics struct {
+	mu       sync.Mutex
+	counters map[string]float64
+	gauges   map[string]float64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{counters: map[string]float64{}, gauges: map[string]float64{}}
+}
+
+// Add increases counter name by delta. Tasks should add once when they
+// finish rather than per operation, so the lock stays off the hot path.
+func (m *Metrics) Add(name string, delta float64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.counters[name] += delta
+	m.mu.Unlock()
+}
+
+// Set records the latest value of gauge name.
+func (m *Metrics) Set(name string, value float64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.gauges[name] = value
+	m.mu.Unlock()
+}
+
+// Counters returns a copy of the counters.
+func (m *Metrics) Counters() map[string]float64 {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.counters)
+}
+
+// Gauges returns a copy of the gauges.
+func (m *Metrics) Gauges() map[string]float64 {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.gauges)
+}
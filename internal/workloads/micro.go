@@ -0,0 +1,39 @@
+package workloads
+
+import "time"
+
+// Counters micro timing records into a workload's Metrics
+const (
+	MicroOps = "micro_ops"
+	MicroNS  = "micro_op_ns"
+)
+
+// opTimer times one goroutine's operations into locals when micro timing
+// is on and adds them to the Metrics once at the end, so the only cost on
+// the hot path is the clock reads themselves. Off, it does nothing.
+type opTimer struct {
+	on    bool
+	start time.Time
+	total time.Duration
+	ops   int
+}
+
+func (t *opTimer) begin() {
+	if t.on {
+		t.start = time.Now()
+	}
+}
+
+func (t *opTimer) end() {
+	if t.on {
+		t.total += time.Since(t.start)
+		t.ops++
+	}
+}
+
+func (t *opTimer) flush(m *Metrics) {
+	if t.on {
+		m.Add(MicroOps, float64(t.ops))
+		m.Add(MicroNS, float64(t.total.Nanoseconds()))
+	}
+}
@@ -0,0 +1,133 @@
+package workloads
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// netRequestBytes is the size of one request to the echo server, and of
+// its reply
+const netRequestBytes = 64
+
+// echoServer is a TCP server on loopback that answers every request with
+// the same bytes after a delay, so its clients wait in the netpoller.
+type echoServer struct {
+	ln net.Listener
+	// waits returns the delays of the nth connection's replies
+	waits func(n int) func() time.Duration
+	wg    sync.WaitGroup
+}
+
+// startEchoServer listens on a free loopback port and serves until
+// Close.
+func startEchoServer(waits func(n int) func() time.Duration) (*echoServer, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &echoServer{ln: ln, waits: waits}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Addr is the address clients dial.
+func (s *echoServer) Addr() string { return s.ln.Addr().String() }
+
+// Close stops accepting and waits for every connection to be closed by
+// its client.
+func (s *echoServer) Close() {
+	s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *echoServer) accept() {
+	defer Guard()
+	defer s.wg.Done()
+	for n := 0; ; n++ {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go s.serve(conn, s.waits(n))
+	}
+}
+
+// serve echoes each request on conn after wait, until the client hangs
+// up.
+func (s *echoServer) serve(conn net.Conn, wait func() time.Duration) {
+	defer Guard()
+	defer s.wg.Done()
+	defer conn.Close()
+	buf := make([]byte, netRequestBytes)
+	for {
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		time.Sleep(wait())
+		if _, err := conn.Write(buf); err != nil {
+			return
+		}
+	}
+}
+
+// startEcho starts the server a wave's network tasks dial when c.Net is
+// set, and returns its address and how to stop it. If it can't listen the
+// address is empty, so every request fails and counts as an error.
+func (c Config) startEcho() (addr string, stop func()) {
+	if !c.Net {
+		return "", func() {}
+	}
+	s, err := startEchoServer(c.ioWaits)
+	if err != nil {
+		return "", func() {}
+	}
+	return s.Addr(), s.Close
+}
+
+// netIOTask is IOIntensiveTask over the network: it dials addr once and
+// makes ops requests on the connection, each waiting for the echo.
+// Requests that fail count towards the "errors" counter instead of
+// "requests".
+func netIOTask(addr string, ops int, micro bool, wg *sync.WaitGroup, m *Metrics) {
+	defer Guard()
+	defer wg.Done()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		m.Add("errors", float64(ops))
+		return
+	}
+	defer conn.Close()
+
+	req := make([]byte, netRequestBytes)
+	reply := make([]byte, netRequestBytes)
+	failed := 0
+	timer := opTimer{on: micro}
+	defer timer.flush(m)
+	for i := 0; i < ops; i++ {
+		timer.begin()
+		if _, err := conn.Write(req); err != nil {
+			failed += ops - i
+			break
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			failed += ops - i
+			break
+		}
+
+		// Small CPU work between I/O, as in the simulated task
+		sum := 0
+		for j := 0; j < 50_000; j++ {
+			sum += j
+		}
+		timer.end()
+	}
+	m.Add("requests", float64(ops-failed))
+	if failed > 0 {
+		m.Add("errors", float64(failed))
+	}
+}
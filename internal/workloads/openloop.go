@@ -0,0 +1,127 @@
+package workloads
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// burstSize is how many requests arrive together in the bursts process
+const burstSize = 20
+
+// ArrivalProcess is how an open-loop generator spaces its requests. Every
+// process offers the same mean rate; they differ in how the arrivals
+// clump.
+type ArrivalProcess struct {
+	Name string
+	Note string
+	// schedule returns the offsets at which n requests arrive at rate
+	// requests per second
+	schedule func(rng *rand.Rand, n int, rate float64) []time.Duration
+}
+
+// ArrivalProcesses returns the arrival processes the open-loop mode can
+// offer load with.
+func ArrivalProcesses() []ArrivalProcess {
+	return []ArrivalProcess{
+		{"steady", "one request every 1/rate, the closed-loop ideal", steadyArrivals},
+		{"poisson", "independent arrivals with exponential gaps, like many unrelated clients", poissonArrivals},
+		{"bursts", "20 requests at once every 20/rate, like fan-out or a retry storm", burstArrivals},
+	}
+}
+
+func steadyArrivals(_ *rand.Rand, n int, rate float64) []time.Duration {
+	gap := time.Duration(float64(time.Second) / rate)
+	at := make([]time.Duration, n)
+	for i := range at {
+		at[i] = time.Duration(i) * gap
+	}
+	return at
+}
+
+func poissonArrivals(rng *rand.Rand, n int, rate float64) []time.Duration {
+	at := make([]time.Duration, n)
+	var t float64
+	for i := range at {
+		t += rng.ExpFloat64() / rate
+		at[i] = time.Duration(t * float64(time.Second))
+	}
+	return at
+}
+
+func burstArrivals(_ *rand.Rand, n int, rate float64) []time.Duration {
+	gap := time.Duration(float64(time.Second) * burstSize / rate)
+	at := make([]time.Duration, n)
+	for i := range at {
+		at[i] = time.Duration(i/burstSize) * gap
+	}
+	return at
+}
+
+// OpenLoopRun is the outcome of one RunOpenLoop, with a request's
+// timings at the same index in each slice.
+type OpenLoopRun struct {
+	Elapsed time.Duration
+	// Queued runs from a request's scheduled arrival to a worker taking
+	// it, Latencies from its scheduled arrival to finishing
+	Queued    []time.Duration
+	Latencies []time.Duration
+	// Uncorrected runs from when the generator actually sent a request to
+	// its finishing: what a generator that timestamps at send records.
+	// When the generator falls behind its schedule, that lag is missing.
+	Uncorrected []time.Duration
+}
+
+// RunOpenLoop offers requests requests of kind at rate per second, spaced
+// by process, to a server of workers goroutines at GOMAXPROCS=maxProcs.
+// Unlike a closed loop, a request arrives on schedule whether or not
+// earlier ones have finished, so a slow server builds a queue instead of
+// slowing its clients down. Queueing delay and latency are measured from
+// the schedule rather than the send, which avoids coordinated omission
+// when the generator itself falls behind; Uncorrected keeps the
+// send-based view for comparison.
+func RunOpenLoop(kind RequestKind, process ArrivalProcess, rate float64, requests, workers, maxProcs int) OpenLoopRun {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	// The same seed gives every run the same arrivals
+	schedule := process.schedule(rand.New(rand.NewSource(1)), requests, rate)
+	sent := make([]time.Duration, requests)
+	run := OpenLoopRun{
+		Queued:      make([]time.Duration, requests),
+		Latencies:   make([]time.Duration, requests),
+		Uncorrected: make([]time.Duration, requests),
+	}
+	// Buffered for every request, so arrivals never wait on the server
+	queue := make(chan int, requests)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for i := range queue {
+				run.Queued[i] = time.Since(start) - schedule[i]
+				kind.Serve()
+				done := time.Since(start)
+				run.Latencies[i] = done - schedule[i]
+				run.Uncorrected[i] = done - sent[i]
+			}
+		}()
+	}
+
+	for i, at := range schedule {
+		if wait := at - time.Since(start); wait > 0 {
+			time.Sleep(wait)
+		}
+		sent[i] = time.Since(start)
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	run.Elapsed = time.Since(start)
+	return run
+}
@@ -0,0 +1,238 @@
+package workloads
+
+import (
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PipelineStages names the pipeline's stages in order. Parse and
+// transform usually fan out across workers and aggregate fans them back in
+// to one goroutine, though StageWidths can size each stage.
+var PipelineStages = []string{"parse", "transform", "aggregate"}
+
+// transformRounds is how many times transform rehashes a record, making it
+// the heaviest stage per item
+const transformRounds = 24
+
+// StageWidths is the number of goroutines in each of PipelineStages.
+type StageWidths [3]int
+
+// FanOut is width goroutines in each of parse and transform fanning in to
+// one aggregate, the pipeline's usual shape.
+func FanOut(width int) StageWidths { return StageWidths{width, width, 1} }
+
+// ParseStageWidths reads either one width, for FanOut, or a
+// slash-separated width per stage such as "2/6/1".
+func ParseStageWidths(s string) (StageWidths, error) {
+	fields := strings.Split(s, "/")
+	if len(fields) == 1 {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return StageWidths{}, fmt.Errorf("stage width %q is not a positive count", s)
+		}
+		return FanOut(n), nil
+	}
+	if len(fields) != len(PipelineStages) {
+		return StageWidths{}, fmt.Errorf("stage widths %q: want one width or %d separated by /", s, len(PipelineStages))
+	}
+	var w StageWidths
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 {
+			return StageWidths{}, fmt.Errorf("stage widths %q: %s width %q is not a positive count", s, PipelineStages[i], f)
+		}
+		w[i] = n
+	}
+	return w, nil
+}
+
+// Total is the goroutines across every stage.
+func (w StageWidths) Total() int { return w[0] + w[1] + w[2] }
+
+func (w StageWidths) String() string { return fmt.Sprintf("%d/%d/%d", w[0], w[1], w[2]) }
+
+// PipelineResult is one pipeline run: its wall time and how long each
+// stage's goroutines spent working rather than waiting on a channel,
+// summed over the stage.
+type PipelineResult struct {
+	Widths  StageWidths
+	Elapsed time.Duration
+	Busy    [3]time.Duration
+	Keys    int
+}
+
+// Utilization is the share of stage i's goroutines' time spent working:
+// near 1 and every goroutine of the stage was always busy, so it sets the
+// pace and the others wait on it.
+func (r PipelineResult) Utilization(i int) float64 {
+	return float64(r.Busy[i]) / float64(r.Elapsed*time.Duration(r.Widths[i]))
+}
+
+// Bottleneck is the index in PipelineStages of the busiest stage.
+func (r PipelineResult) Bottleneck() int {
+	best := 0
+	for i := range PipelineStages {
+		if r.Utilization(i) > r.Utilization(best) {
+			best = i
+		}
+	}
+	return best
+}
+
+// pipelineRecord is a parsed line
+type pipelineRecord struct {
+	key    string
+	values [8]int64
+}
+
+// pipelineDigest is a transformed record on its way to the aggregate
+type pipelineDigest struct {
+	key  string
+	hash uint64
+}
+
+// PipelineLines builds n CSV lines of a key and eight numbers for the
+// pipeline to parse.
+func PipelineLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		var b strings.Builder
+		b.WriteString("key")
+		b.WriteString(strconv.Itoa(i % 1024))
+		for j := 0; j < 8; j++ {
+			b.WriteByte(',')
+			b.WriteString(strconv.FormatInt(itemValue(i*8+j), 10))
+		}
+		lines[i] = b.String()
+	}
+	return lines
+}
+
+// RunPipeline streams lines through parse, transform and aggregate stages
+// at GOMAXPROCS=maxProcs, with widths goroutines in the stages and
+// channels of capacity buffer between them. Aggregate goroutines each keep
+// their own totals, merged once the stream ends.
+func RunPipeline(lines []string, widths StageWidths, buffer, maxProcs int) PipelineResult {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	raw := make(chan string, buffer)
+	parsed := make(chan pipelineRecord, buffer)
+	digests := make(chan pipelineDigest, buffer)
+	var busy [3]time.Duration
+	var mu sync.Mutex
+	record := func(stage int, d time.Duration) {
+		mu.Lock()
+		busy[stage] += d
+		mu.Unlock()
+	}
+
+	start := time.Now()
+	go func() {
+		// Close even after a panic, or the parsers would wait forever
+		defer close(raw)
+		defer Guard()
+		for _, l := range lines {
+			raw <- l
+		}
+	}()
+
+	var parsers, transformers, aggregators sync.WaitGroup
+	for w := 0; w < widths[0]; w++ {
+		parsers.Add(1)
+		go func() {
+			defer Guard()
+			defer parsers.Done()
+			var spent time.Duration
+			for l := range raw {
+				t := time.Now()
+				r := parseRecord(l)
+				spent += time.Since(t)
+				parsed <- r
+			}
+			record(0, spent)
+		}()
+	}
+	for w := 0; w < widths[1]; w++ {
+		transformers.Add(1)
+		go func() {
+			defer Guard()
+			defer transformers.Done()
+			var spent time.Duration
+			for r := range parsed {
+				t := time.Now()
+				d := transformRecord(r)
+				spent += time.Since(t)
+				digests <- d
+			}
+			record(1, spent)
+		}()
+	}
+	go func() {
+		defer Guard()
+		parsers.Wait()
+		close(parsed)
+		transformers.Wait()
+		close(digests)
+	}()
+
+	// The aggregate is the fan-in: each goroutine owns its totals
+	parts := make([]map[string]uint64, widths[2])
+	for w := range parts {
+		aggregators.Add(1)
+		go func() {
+			defer Guard()
+			defer aggregators.Done()
+			totals := map[string]uint64{}
+			var spent time.Duration
+			for d := range digests {
+				t := time.Now()
+				totals[d.key] += d.hash
+				spent += time.Since(t)
+			}
+			parts[w] = totals
+			record(2, spent)
+		}()
+	}
+	aggregators.Wait()
+	totals := parts[0]
+	for _, p := range parts[1:] {
+		for k, v := range p {
+			totals[k] += v
+		}
+	}
+	elapsed := time.Since(start)
+	return PipelineResult{Widths: widths, Elapsed: elapsed, Busy: busy, Keys: len(totals)}
+}
+
+func parseRecord(line string) pipelineRecord {
+	var r pipelineRecord
+	fields := strings.Split(line, ",")
+	r.key = fields[0]
+	for i := range r.values {
+		r.values[i], _ = strconv.ParseInt(fields[i+1], 10, 64)
+	}
+	return r
+}
+
+func transformRecord(r pipelineRecord) pipelineDigest {
+	h := fnv.New64a()
+	var buf [8]byte
+	var sum uint64
+	for round := 0; round < transformRounds; round++ {
+		h.Write([]byte(r.key))
+		for _, v := range r.values {
+			for i := range buf {
+				buf[i] = byte(v >> (8 * i))
+			}
+			h.Write(buf[:])
+		}
+		sum += h.Sum64()
+	}
+	return pipelineDigest{r.key, sum}
+}
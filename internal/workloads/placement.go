@@ -0,0 +1,131 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pingPongAdds is how many times each pingpong worker increments the
+// shared counter
+const pingPongAdds = 2_000_000
+
+// privateReads is how many bytes each private-cache worker reads, in
+// passes over its own array
+const privateReads = 512 << 20
+
+// placementShared is the state a placement's workers share.
+type placementShared struct {
+	counter atomic.Int64
+}
+
+// PlacementKernel is a memory-bound loop that every worker of a placement
+// runs on its own pinned CPU. Each worker's work is fixed, so pinned pairs
+// that didn't interfere would take as long as one worker alone.
+type PlacementKernel struct {
+	Name string
+	Note string
+	// prepare runs on worker's pinned thread before the clock starts, so
+	// the worker's pages are first touched from its own CPU, and returns
+	// the loop to time
+	prepare func(worker int, shared *placementShared) func()
+}
+
+// PlacementKernels returns the kernels the placement suite pins to each
+// pair of CPUs, sizing the private-cache one to three quarters of an
+// l2Bytes L2.
+func PlacementKernels(l2Bytes int) []PlacementKernel {
+	private := l2Bytes * 3 / 4 / 8
+	return []PlacementKernel{
+		{"triad", "STREAM triad over each worker's own arrays past the last-level cache: bound by memory bandwidth", prepareTriad},
+		{"pingpong", "both workers increment one atomic counter: bound by moving its cache line between them", preparePingPong},
+		{"private", "each worker sums its own array of 3/4 of an L2 over and over: fits a core's L2 unless a sibling shares it", func(int, *placementShared) func() {
+			return preparePrivate(private)
+		}},
+	}
+}
+
+func prepareTriad(int, *placementShared) func() {
+	n := StreamElems / 2
+	a, b, c := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range b {
+		a[i] = 1
+		b[i] = float64(i)
+		c[i] = float64(i) * 0.5
+	}
+	return func() {
+		for pass := 0; pass < 4; pass++ {
+			for i := range a {
+				a[i] = b[i] + streamScalar*c[i]
+			}
+		}
+	}
+}
+
+func preparePingPong(_ int, shared *placementShared) func() {
+	return func() {
+		for i := 0; i < pingPongAdds; i++ {
+			shared.counter.Add(1)
+		}
+	}
+}
+
+func preparePrivate(elems int) func() {
+	data := make([]int64, max(1, elems))
+	for i := range data {
+		data[i] = int64(i)
+	}
+	return func() {
+		var sum int64
+		for pass := 0; pass < privateReads/8/len(data); pass++ {
+			for _, v := range data {
+				sum += v
+			}
+		}
+		atomic.AddUint64(&sink, uint64(sum))
+	}
+}
+
+// RunPinned runs k with one worker per CPU in cpus, each on a thread that
+// pin has restricted to its CPU, and times them from a common start until
+// the last one finishes.
+func RunPinned(k PlacementKernel, cpus []int, pin func(cpu int) (func(), error)) (time.Duration, error) {
+	oldMaxProcs := runtime.GOMAXPROCS(max(len(cpus)+1, runtime.GOMAXPROCS(0)))
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var shared placementShared
+	var ready, done sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, len(cpus))
+	for w, cpu := range cpus {
+		ready.Add(1)
+		done.Add(1)
+		go func() {
+			defer Guard()
+			defer done.Done()
+			unpin, err := pin(cpu)
+			if err != nil {
+				errs[w] = err
+				ready.Done()
+				return
+			}
+			defer unpin()
+			loop := k.prepare(w, &shared)
+			ready.Done()
+			<-start
+			loop()
+		}()
+	}
+	ready.Wait()
+	begin := time.Now()
+	close(start)
+	done.Wait()
+	elapsed := time.Since(begin)
+	for _, err := range errs {
+		if err != nil {
+			return 0, err
+		}
+	}
+	return elapsed, nil
+}
@@ -0,0 +1,149 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolStrategy is one way to run a set of tasks on goroutines.
+type PoolStrategy struct {
+	Name string
+	Note string
+	// run runs tasks tasks of kind, with at most limit goroutines at once
+	// where the strategy bounds them, counting its goroutines into live
+	run func(kind PoolTask, tasks, limit int, live *liveCount)
+}
+
+// PoolStrategies returns the strategies the pools suite compares.
+func PoolStrategies() []PoolStrategy {
+	return []PoolStrategy{
+		{"per-task", "one goroutine per task, all started at once", perTaskRun},
+		{"pool", "limit long-lived workers pulling tasks from a channel", poolRun},
+		{"limited", "errgroup.SetLimit-style: a goroutine per task, at most limit alive", limitedRun},
+	}
+}
+
+// PoolTask is a kind of task the pools suite runs.
+type PoolTask struct {
+	Name  string
+	Tasks int
+	run   func(i int)
+}
+
+// PoolTasks returns the task sets the pools suite runs: many short
+// computations, where goroutine start-up is a real share of each task,
+// and fewer tasks that mostly wait, where a small pool leaves the cores
+// idle.
+func PoolTasks() []PoolTask {
+	return []PoolTask{
+		{"compute", 20_000, func(i int) {
+			sum := 0
+			for j := 0; j < 5000; j++ {
+				sum += j ^ i
+			}
+			atomic.AddUint64(&sink, uint64(sum))
+		}},
+		{"wait", 2_000, func(int) { time.Sleep(time.Millisecond) }},
+	}
+}
+
+// liveCount tracks the goroutines a strategy has alive, from their go
+// statement until they return, and the most it had at once.
+type liveCount struct {
+	live, peak atomic.Int64
+}
+
+func (c *liveCount) enter() {
+	n := c.live.Add(1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			return
+		}
+	}
+}
+
+func (c *liveCount) exit() { c.live.Add(-1) }
+
+// PoolWorkload runs kind's tasks through s with at most limit goroutines
+// where s bounds them, recording the most goroutines it had alive at once
+// as the peak_goroutines gauge.
+func PoolWorkload(s PoolStrategy, kind PoolTask, limit int) Workload {
+	name := fmt.Sprintf("%s %s", s.Name, kind.Name)
+	return New(name, kind.Tasks, func(maxProcs int, m *Metrics) time.Duration {
+		oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+		defer runtime.GOMAXPROCS(oldMaxProcs)
+
+		var live liveCount
+		start := time.Now()
+		s.run(kind, kind.Tasks, limit, &live)
+		elapsed := time.Since(start)
+		m.Set("peak_goroutines", float64(live.peak.Load()))
+		return elapsed
+	})
+}
+
+func perTaskRun(kind PoolTask, tasks, _ int, live *liveCount) {
+	var wg sync.WaitGroup
+	for i := 0; i < tasks; i++ {
+		wg.Add(1)
+		live.enter()
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			defer live.exit()
+			kind.run(i)
+		}()
+	}
+	wg.Wait()
+}
+
+func poolRun(kind PoolTask, tasks, limit int, live *liveCount) {
+	var wg sync.WaitGroup
+	jobs := make(chan int, limit)
+	for w := 0; w < limit; w++ {
+		wg.Add(1)
+		live.enter()
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			defer live.exit()
+			for i := range jobs {
+				kind.run(i)
+			}
+		}()
+	}
+	for i := 0; i < tasks; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func limitedRun(kind PoolTask, tasks, limit int, live *liveCount) {
+	// errgroup.Group with SetLimit: Go blocks until a slot is free, then
+	// starts a fresh goroutine that frees its slot on return
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < tasks; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		live.enter()
+		go func() {
+			defer Guard()
+			defer func() { <-sem }()
+			defer wg.Done()
+			defer live.exit()
+			if ctx.Err() == nil {
+				kind.run(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,106 @@
+package workloads
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+)
+
+// Registration is a named workload constructor: the name -run and -list
+// see, the function that builds the workload at a Config's sizes, the
+// outcomes it is expected to show, and whether a user supplied it.
+type Registration struct {
+	Name         string
+	New          func(Config) Workload
+	Expectations []Expectation
+	Custom       bool
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []Registration
+)
+
+func init() {
+	Register("cpu", Config.CPU)
+	Register("io", Config.IO)
+	Register("mixed", Config.Mixed)
+	Register("scalability", Config.Scalability)
+	Register("triad", Config.Triad)
+
+	Expect("cpu", Expectation{MinSpeedup: 2, MinCores: 4})
+	Expect("io", Expectation{MaxSpeedup: 1.3})
+	Expect("mixed", Expectation{MinSpeedup: 1.2, MinCores: 4})
+	Expect("scalability", Expectation{MinSpeedup: 2, MinCores: 4})
+}
+
+// Register adds a workload under name, after those already registered.
+// Like database/sql.Register it panics on an empty or duplicate name, a
+// programming error best caught at init.
+func Register(name string, fn func(Config) Workload) {
+	register(Registration{Name: name, New: fn})
+}
+
+func register(reg Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if reg.Name == "" || reg.New == nil {
+		panic("workloads: Register needs a name and a constructor")
+	}
+	for _, r := range registry {
+		if r.Name == reg.Name {
+			panic(fmt.Sprintf("workloads: Register called twice for %q", reg.Name))
+		}
+	}
+	registry = append(registry, reg)
+}
+
+// Expect adds expected outcomes to the workload registered under name.
+// It panics if there is none, like Register on a bad name.
+func Expect(name string, e ...Expectation) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i := range registry {
+		if registry[i].Name == name {
+			registry[i].Expectations = append(registry[i].Expectations, e...)
+			return
+		}
+	}
+	panic(fmt.Sprintf("workloads: Expect called for unregistered %q", name))
+}
+
+// Registered returns the registrations whose names match re, in
+// registration order; a nil re matches them all.
+func Registered(re *regexp.Regexp) []Registration {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	var matched []Registration
+	for _, r := range registry {
+		if re == nil || re.MatchString(r.Name) {
+			r.Expectations = slices.Clone(r.Expectations)
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// Lookup returns the workload registered under name, built at c's sizes.
+func (c Config) Lookup(name string) (Workload, bool) {
+	for _, r := range Registered(nil) {
+		if r.Name == name {
+			return r.New(c), true
+		}
+	}
+	return nil, false
+}
+
+// Matching builds every registered workload whose name matches re at c's
+// sizes, in registration order.
+func (c Config) Matching(re *regexp.Regexp) []Workload {
+	var ws []Workload
+	for _, r := range Registered(re) {
+		ws = append(ws, r.New(c))
+	}
+	return ws
+}
@@ -0,0 +1,64 @@
+package workloads
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+// testWorkload builds a workload that does nothing, registered under name.
+func testWorkload(name string) func(Config) Workload {
+	return func(Config) Workload {
+		return New(name, 1, func(int, *Metrics) time.Duration { return 0 })
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-registry-a", testWorkload("A"))
+	Register("test-registry-b", testWorkload("B"))
+
+	regs := Registered(regexp.MustCompile("^test-registry-"))
+	if len(regs) != 2 || regs[0].Name != "test-registry-a" || regs[1].Name != "test-registry-b" {
+		t.Fatalf("registered %v, want test-registry-a then test-registry-b", regs)
+	}
+	if w, ok := (Config{}).Lookup("test-registry-b"); !ok || w.Name() != "B" {
+		t.Errorf("looking up test-registry-b gave %v, %v", w, ok)
+	}
+	if _, ok := (Config{}).Lookup("test-registry-c"); ok {
+		t.Error("looked up an unregistered workload")
+	}
+	ws := Config{}.Matching(regexp.MustCompile("registry-a$"))
+	if len(ws) != 1 || ws[0].Name() != "A" {
+		t.Errorf("matching registry-a$ built %v", ws)
+	}
+
+	// The built-in workloads stay registered first, in order
+	if all := Registered(nil); all[0].Name != "cpu" || all[1].Name != "io" {
+		t.Errorf("registry starts with %s and %s, want cpu and io", all[0].Name, all[1].Name)
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	Register("test-registry-dup", testWorkload("dup"))
+	tests := []struct {
+		name string
+		reg  func()
+	}{
+		{"duplicate", func() { Register("test-registry-dup", testWorkload("dup")) }},
+		{"empty name", func() { Register("", testWorkload("empty")) }},
+		{"no constructor", func() { Register("test-registry-nil", nil) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register did not panic")
+				}
+			}()
+			tt.reg()
+		})
+	}
+	if n := len(Registered(regexp.MustCompile("^test-registry-(dup|nil)$"))); n != 1 {
+		t.Errorf("%d registrations after the panics, want 1", n)
+	}
+}
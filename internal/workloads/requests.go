@@ -0,0 +1,230 @@
+package workloads
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// RequestKind is one kind of request a bounded worker pool serves.
+type RequestKind struct {
+	Name  string
+	Serve func()
+}
+
+// Requests returns the I/O and mixed requests c configures, and how to
+// release the temp files, connections and echo server they share. Each
+// makes one of the I/O workload's operations: a wait drawn from c.IODist
+// around c.IOSleep, or a real disk round trip or echo request when c.Disk
+// or c.Net is set. The I/O request then parses the reply, the mixed one
+// counts the primes below a fifth of c.PrimeLimit. Requests are timed one
+// by one, so they wait on the real clock even when c.Clock is virtual.
+// Stop reports the requests whose I/O failed.
+func (c Config) Requests() (ioKind, mixedKind RequestKind, stop func() error) {
+	addr, stopEcho := c.startEcho()
+	r := &requestIO{c: c, addr: addr, wait: c.ioWaits(0)}
+	ioKind = RequestKind{"I/O", func() {
+		r.do()
+		sum := 0
+		for j := 0; j < 50_000; j++ {
+			sum += j
+		}
+	}}
+	mixedKind = RequestKind{"Mixed", func() {
+		r.do()
+		countPrimes(c.PrimeLimit / 5)
+	}}
+	return ioKind, mixedKind, func() error {
+		err := r.close()
+		stopEcho()
+		return err
+	}
+}
+
+// requestIO makes the I/O of concurrent requests, reusing temp files and
+// echo connections between them.
+type requestIO struct {
+	c    Config
+	addr string
+
+	mu     sync.Mutex
+	wait   func() time.Duration
+	files  []*os.File
+	conns  []net.Conn
+	failed int
+	err    error
+}
+
+func (r *requestIO) do() {
+	var err error
+	switch {
+	case r.c.Disk:
+		err = r.disk()
+	case r.c.Net:
+		err = r.echo()
+	default:
+		// The draws share one source, so they take turns
+		r.mu.Lock()
+		d := r.wait()
+		r.mu.Unlock()
+		time.Sleep(d)
+	}
+	if err != nil {
+		r.mu.Lock()
+		r.failed++
+		if r.err == nil {
+			r.err = err
+		}
+		r.mu.Unlock()
+	}
+}
+
+// disk makes one round trip through an idle temp file, or a new one.
+func (r *requestIO) disk() error {
+	r.mu.Lock()
+	var f *os.File
+	if n := len(r.files); n > 0 {
+		f, r.files = r.files[n-1], r.files[:n-1]
+	}
+	r.mu.Unlock()
+	if f == nil {
+		var err error
+		if f, err = os.CreateTemp(r.c.DiskDir, "compare_process-io-*"); err != nil {
+			return err
+		}
+	}
+	err := diskRoundTrip(f, make([]byte, r.c.DiskBytes), r.c.Fsync)
+	r.mu.Lock()
+	r.files = append(r.files, f)
+	r.mu.Unlock()
+	return err
+}
+
+// echo makes one request on an idle echo connection, or a new one. A
+// connection that failed is dropped.
+func (r *requestIO) echo() error {
+	r.mu.Lock()
+	var conn net.Conn
+	if n := len(r.conns); n > 0 {
+		conn, r.conns = r.conns[n-1], r.conns[:n-1]
+	}
+	r.mu.Unlock()
+	if conn == nil {
+		var err error
+		if conn, err = net.Dial("tcp", r.addr); err != nil {
+			return err
+		}
+	}
+	buf := make([]byte, netRequestBytes)
+	if _, err := conn.Write(buf); err != nil {
+		conn.Close()
+		return err
+	}
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		conn.Close()
+		return err
+	}
+	r.mu.Lock()
+	r.conns = append(r.conns, conn)
+	r.mu.Unlock()
+	return nil
+}
+
+// close releases the idle files and connections, which once the requests
+// are done are all of them, and reports the failed requests.
+func (r *requestIO) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range r.files {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	for _, conn := range r.conns {
+		conn.Close()
+	}
+	r.files, r.conns = nil, nil
+	if r.failed > 0 {
+		return fmt.Errorf("%d requests failed, the first with: %w", r.failed, r.err)
+	}
+	return nil
+}
+
+// RunBoundedRequests serves requests requests of kind with at most limit
+// in flight at once, GOMAXPROCS at NumCPU. It returns the wall time and
+// each request's service latency, from taking a slot to finishing, which
+// is what more concurrency inflates once the work contends for CPU.
+func RunBoundedRequests(kind RequestKind, limit, requests int) (time.Duration, []time.Duration) {
+	oldMaxProcs := runtime.GOMAXPROCS(runtime.NumCPU())
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, limit)
+	latencies := make([]time.Duration, requests)
+	start := time.Now()
+
+	for i := 0; i < requests; i++ {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			defer func() { <-slots }()
+			begin := time.Now()
+			kind.Serve()
+			latencies[i] = time.Since(begin)
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start), latencies
+}
+
+// RunClosedLoop has clients goroutines each serve perClient requests of
+// kind back to back, GOMAXPROCS at NumCPU. It returns the wall time and
+// every request's latency; with no cap on concurrency, latency grows only
+// once the clients contend for CPU.
+func RunClosedLoop(kind RequestKind, clients, perClient int) (time.Duration, []time.Duration) {
+	oldMaxProcs := runtime.GOMAXPROCS(runtime.NumCPU())
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	latencies := make([]time.Duration, clients*perClient)
+	start := time.Now()
+
+	for c := 0; c < clients; c++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			for i := 0; i < perClient; i++ {
+				begin := time.Now()
+				kind.Serve()
+				latencies[c*perClient+i] = time.Since(begin)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start), latencies
+}
+
+func countPrimes(limit int) int {
+	count := 0
+	for n := 2; n < limit; n++ {
+		isPrime := true
+		for i := 2; i*i <= n; i++ {
+			if n%i == 0 {
+				isPrime = false
+				break
+			}
+		}
+		if isPrime {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,109 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	CounterOpsPerTask = 100_000
+	mapOpsPerTask     = 10_000
+	mapKeySpace       = 1024
+)
+
+// RunCounterTasks has numTasks goroutines increment one shared counter,
+// either under a mutex or racily, and returns the final count.
+func RunCounterTasks(maxProcs, numTasks int, racy bool) (int, time.Duration) {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	counter := 0
+	start := time.Now()
+
+	for i := 0; i < numTasks; i++ {
+		wg.Add(1)
+		if racy {
+			go racyCounterTask(&counter, &wg)
+		} else {
+			go counterTask(&counter, &mu, &wg)
+		}
+	}
+
+	wg.Wait()
+	return counter, time.Since(start)
+}
+
+// RunMapTasks has numTasks goroutines update one shared map, either under
+// a mutex or racily. Only run the racy version with maxProcs=1: with real
+// parallelism the runtime's concurrent-map-write check aborts the process.
+func RunMapTasks(maxProcs, numTasks int, racy bool) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	shared := make(map[int]int, mapKeySpace)
+	start := time.Now()
+
+	for i := 0; i < numTasks; i++ {
+		wg.Add(1)
+		if racy {
+			go racyMapTask(i, shared, &wg)
+		} else {
+			go mapTask(i, shared, &mu, &wg)
+		}
+	}
+
+	wg.Wait()
+	return time.Since(start)
+}
+
+func counterTask(counter *int, mu *sync.Mutex, wg *sync.WaitGroup) {
+	defer Guard()
+	defer wg.Done()
+
+	for i := 0; i < CounterOpsPerTask; i++ {
+		mu.Lock()
+		*counter++
+		mu.Unlock()
+	}
+}
+
+func racyCounterTask(counter *int, wg *sync.WaitGroup) {
+	defer Guard()
+	defer wg.Done()
+
+	// Unsynchronized read-modify-write: increments get lost under parallelism
+	for i := 0; i < CounterOpsPerTask; i++ {
+		*counter++
+	}
+}
+
+func mapTask(id int, shared map[int]int, mu *sync.Mutex, wg *sync.WaitGroup) {
+	defer Guard()
+	defer wg.Done()
+
+	for i := 0; i < mapOpsPerTask; i++ {
+		key := (id*mapOpsPerTask + i) % mapKeySpace
+		mu.Lock()
+		shared[key]++
+		mu.Unlock()
+	}
+}
+
+func racyMapTask(id int, shared map[int]int, wg *sync.WaitGroup) {
+	defer Guard()
+	defer wg.Done()
+
+	for i := 0; i < mapOpsPerTask; i++ {
+		key := (id*mapOpsPerTask + i) % mapKeySpace
+		shared[key]++
+		// Yield so goroutines interleave even on a single P
+		if i%1000 == 0 {
+			runtime.Gosched()
+		}
+	}
+}
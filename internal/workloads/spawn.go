@@ -0,0 +1,49 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SpawnGoroutines starts n goroutines that exit straight away at
+// GOMAXPROCS=maxProcs and returns the time until the last has exited, so
+// it is the cost of creating, scheduling and tearing down each one.
+func SpawnGoroutines(n, maxProcs int) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		go func() {
+			defer Guard()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// SpawnLatencies starts n goroutines like SpawnGoroutines and returns how
+// long each waited between its go statement and its first instruction.
+// Reading the clock twice per goroutine slows the spawning loop, so this
+// is kept apart from the throughput SpawnGoroutines measures.
+func SpawnLatencies(n, maxProcs int) []time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	latencies := make([]time.Duration, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(spawned time.Time) {
+			defer Guard()
+			defer wg.Done()
+			latencies[i] = time.Since(spawned)
+		}(time.Now())
+	}
+	wg.Wait()
+	return latencies
+}
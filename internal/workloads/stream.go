@@ -0,0 +1,189 @@
+package workloads
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// streamBatch is how many events the source buffers per shard before
+// sending, as a consumer reading from a partitioned log would
+const streamBatch = 256
+
+// StreamEvent is one keyed reading stamped with its event time.
+type StreamEvent struct {
+	Key   int32
+	Time  time.Duration
+	Value int64
+}
+
+// StreamSpec is a generated stream and the sliding windows summing it per
+// key: Window long, a new one every Slide.
+type StreamSpec struct {
+	Events int
+	Keys   int
+	Gap    time.Duration // event time between consecutive events
+	Window time.Duration
+	Slide  time.Duration
+}
+
+// DefaultStream is about a second of event time at a million events per
+// second over 4,096 keys, in 10ms windows sliding by 2ms.
+func DefaultStream() StreamSpec {
+	return StreamSpec{Events: 1 << 20, Keys: 4096, Gap: time.Microsecond, Window: 10 * time.Millisecond, Slide: 2 * time.Millisecond}
+}
+
+// Panes is how many slides each window spans.
+func (s StreamSpec) Panes() int { return int(s.Window / s.Slide) }
+
+// Generate builds s's events with fixed randomness, in event-time order.
+func (s StreamSpec) Generate() []StreamEvent {
+	rng := rand.New(rand.NewSource(1))
+	events := make([]StreamEvent, s.Events)
+	for i := range events {
+		events[i] = StreamEvent{Key: int32(rng.Intn(s.Keys)), Time: time.Duration(i) * s.Gap, Value: rng.Int63()}
+	}
+	return events
+}
+
+// StreamResult is one pass over the stream.
+type StreamResult struct {
+	Elapsed time.Duration
+	// Latencies holds, per window, the wall time from the source's
+	// watermark passing the window's end to the last shard emitting it
+	Latencies []time.Duration
+	// Checksum sums every key's every window, the same whatever the
+	// shard count when the aggregation is right
+	Checksum int64
+}
+
+// decodeEvent is a few hundred nanoseconds of parsing and validating one
+// event's payload
+func decodeEvent(v int64) int64 {
+	x := uint64(v)
+	for i := 0; i < 48; i++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return int64(x & 0xffff)
+}
+
+// streamMsg carries either a batch of events or a watermark closing a pane
+type streamMsg struct {
+	events    []StreamEvent
+	watermark int // pane index closed, -1 for none
+}
+
+// RunStream aggregates events per s's sliding windows on shards shard
+// goroutines at maxProcs. One source goroutine routes each event to the
+// shard owning its key. When an event starts a new slide, the source
+// first sends every shard a watermark for the finished one. A shard keeps
+// one partial sum per key per pane and on a watermark emits every key's
+// window ending there, then recycles the oldest pane.
+func RunStream(s StreamSpec, events []StreamEvent, maxProcs, shards int) StreamResult {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	panes := s.Panes()
+	last := int(events[len(events)-1].Time / s.Slide)
+	// emitted[p] is when the source sent pane p's watermark, written before
+	// the send so the shards' reports see it
+	emitted := make([]time.Duration, last+1)
+	latencies := make([]time.Duration, last+1)
+
+	start := time.Now()
+	inputs := make([]chan streamMsg, shards)
+	done := make(chan int, shards)
+	sums := make([]int64, shards)
+	var wg sync.WaitGroup
+	for id := range inputs {
+		inputs[id] = make(chan streamMsg, 16)
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			// Keys id, id+shards, ... each with a ring of pane sums
+			owned := (s.Keys - id + shards - 1) / shards
+			ring := make([]int64, owned*panes)
+			var sum int64
+			defer func() { sums[id] = sum }()
+			for msg := range inputs[id] {
+				for _, e := range msg.events {
+					pane := int(e.Time/s.Slide) % panes
+					ring[int(e.Key)/shards*panes+pane] += decodeEvent(e.Value)
+				}
+				if msg.watermark < 0 {
+					continue
+				}
+				oldest := (msg.watermark + 1) % panes
+				for k := 0; k < owned; k++ {
+					var window int64
+					for _, v := range ring[k*panes : (k+1)*panes] {
+						window += v
+					}
+					sum += window
+					ring[k*panes+oldest] = 0
+				}
+				done <- msg.watermark
+			}
+		}()
+	}
+
+	// The collector times each window from watermark to its last shard
+	collected := make(chan struct{})
+	go func() {
+		defer Guard()
+		defer close(collected)
+		reported := make([]int, last+1)
+		for p := range done {
+			if reported[p]++; reported[p] == shards {
+				latencies[p] = time.Since(start) - emitted[p]
+			}
+		}
+	}()
+
+	batches := make([][]StreamEvent, shards)
+	flush := func(id int) {
+		if len(batches[id]) > 0 {
+			inputs[id] <- streamMsg{events: batches[id], watermark: -1}
+			batches[id] = make([]StreamEvent, 0, streamBatch)
+		}
+	}
+	watermark := func(pane int) {
+		for id := range inputs {
+			flush(id)
+		}
+		emitted[pane] = time.Since(start)
+		for id := range inputs {
+			inputs[id] <- streamMsg{watermark: pane}
+		}
+	}
+
+	pane := 0
+	for _, e := range events {
+		for p := int(e.Time / s.Slide); pane < p; pane++ {
+			watermark(pane)
+		}
+		id := int(e.Key) % shards
+		batches[id] = append(batches[id], e)
+		if len(batches[id]) == streamBatch {
+			flush(id)
+		}
+	}
+	watermark(pane)
+	for _, in := range inputs {
+		close(in)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(done)
+	<-collected
+
+	r := StreamResult{Elapsed: elapsed, Latencies: latencies}
+	for _, sum := range sums {
+		r.Checksum += sum
+	}
+	return r
+}
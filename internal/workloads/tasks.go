@@ -0,0 +1,268 @@
+package workloads
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WarmUp runs a quick burst on every core to stabilize CPU frequency and
+// caches before measuring.
+func WarmUp() {
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			sum := 0
+			for j := 0; j < 1_000_000; j++ {
+				sum += j * j
+			}
+		}()
+	}
+	wg.Wait()
+	time.Sleep(100 * time.Millisecond)
+}
+
+// RunCPUTasks counts primes in one goroutine per core, at the default
+// sizes. m may be nil.
+func RunCPUTasks(maxProcs int, m *Metrics) time.Duration {
+	return DefaultConfig().runCPUTasks(maxProcs, m)
+}
+
+func (c Config) runCPUTasks(maxProcs int, m *Metrics) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	// Use number of goroutines equal to CPU cores for better measurement
+	numTasks := c.tasks(1)
+	m.Set("goroutines", float64(numTasks))
+	for i := 0; i < numTasks; i++ {
+		wg.Add(1)
+		go CPUIntensiveTask(c.PrimeLimit, c.Micro, &wg, m)
+	}
+
+	wg.Wait()
+	return time.Since(start)
+}
+
+func (c Config) runIOTasks(maxProcs int, m *Metrics) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	addr, stop := c.startEcho()
+	defer stop()
+
+	var wg sync.WaitGroup
+	clock := c.clock()
+	start := clock.Now()
+
+	// Use more goroutines for I/O tasks to show concurrency benefit
+	numTasks := c.tasks(2)
+	m.Set("goroutines", float64(numTasks))
+	if !c.Disk && !c.Net {
+		// Register every sleeper before any starts, or the first to sleep
+		// could find the rest not yet added and move a virtual clock on
+		clock.Add(numTasks)
+	}
+	for i := 0; i < numTasks; i++ {
+		wg.Add(1)
+		if c.Disk {
+			go diskIOTask(c.DiskDir, c.IOOps, c.DiskBytes, c.Fsync, c.Micro, &wg, m)
+		} else if c.Net {
+			go netIOTask(addr, c.IOOps, c.Micro, &wg, m)
+		} else {
+			go ioIntensiveTask(clock, c.IOOps, c.ioWaits(i), c.Micro, &wg, m)
+		}
+	}
+
+	wg.Wait()
+	return clock.Now().Sub(start)
+}
+
+func (c Config) runMixedTasks(maxProcs int, m *Metrics) time.Duration {
+	oldMaxProcs := runtime.GOMAXPROCS(maxProcs)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	addr, stop := c.startEcho()
+	defer stop()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	numTasks := c.tasks(1)
+	m.Set("goroutines", float64(numTasks))
+	for i := 0; i < numTasks; i++ {
+		wg.Add(1)
+		if i%2 == 0 {
+			go CPUIntensiveTask(c.PrimeLimit, c.Micro, &wg, m)
+		} else if c.Disk {
+			go diskIOTask(c.DiskDir, c.IOOps, c.DiskBytes, c.Fsync, c.Micro, &wg, m)
+		} else if c.Net {
+			go netIOTask(addr, c.IOOps, c.Micro, &wg, m)
+		} else {
+			go ioIntensiveTask(RealClock, c.IOOps, c.ioWaits(i), c.Micro, &wg, m)
+		}
+	}
+
+	wg.Wait()
+	return time.Since(start)
+}
+
+// RunScalabilityTest splits a fixed sum of squares across numGoroutines
+// at GOMAXPROCS=procs, leaving GOMAXPROCS set.
+func RunScalabilityTest(numGoroutines, procs int) time.Duration {
+	runtime.GOMAXPROCS(procs)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	workPerGoroutine := 10_000_000 / numGoroutines
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			sum := 0
+			for j := 0; j < workPerGoroutine; j++ {
+				sum += j * j
+			}
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start)
+}
+
+// unevenTasks is how many tasks RunUnevenScalabilityTest splits its sum
+// into
+const unevenTasks = 256
+
+// unevenSizes splits the scalability test's 10M terms into unevenTasks
+// tasks whose sizes grow linearly, so the last task is unevenTasks times
+// the first and an even split by count hands the last goroutine far more
+// work than the first.
+func unevenSizes() []int {
+	sizes := make([]int, unevenTasks)
+	weight := unevenTasks * (unevenTasks + 1) / 2
+	for i := range sizes {
+		sizes[i] = 10_000_000 * (i + 1) / weight
+	}
+	return sizes
+}
+
+// RunUnevenScalabilityTest is RunScalabilityTest's sum cut into tasks of
+// growing size. Static gives each goroutine an even share of the tasks
+// by count, up front; otherwise the goroutines pull tasks from a shared
+// channel until it is empty, so one stuck with big tasks gets fewer of
+// them. It returns the wall time and when each goroutine finished,
+// relative to the start, and leaves GOMAXPROCS set.
+func RunUnevenScalabilityTest(numGoroutines, procs int, static bool) (time.Duration, []time.Duration) {
+	runtime.GOMAXPROCS(procs)
+
+	sizes := unevenSizes()
+	finished := make([]time.Duration, numGoroutines)
+	queue := make(chan int, len(sizes))
+	for i := range sizes {
+		queue <- i
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for g := 0; g < numGoroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer Guard()
+			defer wg.Done()
+			sum := 0
+			run := func(task int) {
+				for j := 0; j < sizes[task]; j++ {
+					sum += j * j
+				}
+			}
+			if static {
+				lo, hi := span(g, numGoroutines, len(sizes))
+				for task := lo; task < hi; task++ {
+					run(task)
+				}
+			} else {
+				for task := range queue {
+					run(task)
+				}
+			}
+			atomic.AddUint64(&sink, uint64(sum))
+			finished[g] = time.Since(start)
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start), finished
+}
+
+// CPUIntensiveTask counts the primes below limit. With micro set it also
+// times each primality test, one operation.
+func CPUIntensiveTask(limit int, micro bool, wg *sync.WaitGroup, m *Metrics) {
+	defer Guard()
+	defer wg.Done()
+
+	// Calculate prime numbers - more realistic CPU work
+	count := 0
+	timer := opTimer{on: micro}
+	defer timer.flush(m)
+
+	for n := 2; n < limit; n++ {
+		timer.begin()
+		isPrime := true
+		for i := 2; i*i <= n; i++ {
+			if n%i == 0 {
+				isPrime = false
+				break
+			}
+		}
+		if isPrime {
+			count++
+		}
+		timer.end()
+	}
+
+	// Record rather than print, for cleaner output
+	m.Add("primes", float64(count))
+}
+
+// IOIntensiveTask makes ops simulated requests that each wait sleep.
+// With micro set it also times each request, one operation.
+func IOIntensiveTask(ops int, sleep time.Duration, micro bool, wg *sync.WaitGroup, m *Metrics) {
+	ioIntensiveTask(RealClock, ops, func() time.Duration { return sleep }, micro, wg, m)
+}
+
+// ioIntensiveTask is IOIntensiveTask waiting on clock, which the caller
+// has already added it to, for as long as wait says before each request.
+func ioIntensiveTask(clock Clock, ops int, wait func() time.Duration, micro bool, wg *sync.WaitGroup, m *Metrics) {
+	defer Guard()
+	defer wg.Done()
+	defer clock.Add(-1)
+
+	// Simulate realistic I/O pattern
+	defer m.Add("requests", float64(ops))
+	timer := opTimer{on: micro}
+	defer timer.flush(m)
+	for i := 0; i < ops; i++ {
+		timer.begin()
+		// Simulate network request or file I/O
+		clock.Sleep(wait())
+
+		// Small CPU work between I/O (like JSON parsing)
+		sum := 0
+		for j := 0; j < 50_000; j++ {
+			sum += j
+		}
+		timer.end()
+	}
+}
@@ -0,0 +1,36 @@
+// Package workloads contains the benchmark kernels: waves of goroutines
+// whose concurrent and parallel runs the suites compare.
+package workloads
+
+import "time"
+
+// PrimeLimit is how far each CPU task counts primes by default
+const PrimeLimit = 100_000
+
+// Workload is a wave of goroutines that can run under any GOMAXPROCS.
+type Workload interface {
+	Name() string
+	// Tasks is the number of goroutines one Run starts.
+	Tasks() int
+	// Run executes one wave with GOMAXPROCS set to maxProcs, restores the
+	// previous setting and returns the wave's wall time. The wave records
+	// its own counters and gauges into m, which may be nil.
+	Run(maxProcs int, m *Metrics) time.Duration
+}
+
+type funcWorkload struct {
+	name  string
+	tasks int
+	run   func(maxProcs int, m *Metrics) time.Duration
+}
+
+func (w funcWorkload) Name() string { return w.name }
+func (w funcWorkload) Tasks() int   { return w.tasks }
+func (w funcWorkload) Run(maxProcs int, m *Metrics) time.Duration {
+	return w.run(maxProcs, m)
+}
+
+// New adapts a run function to the Workload interface.
+func New(name string, tasks int, run func(maxProcs int, m *Metrics) time.Duration) Workload {
+	return funcWorkload{name, tasks, run}
+}